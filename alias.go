@@ -0,0 +1,98 @@
+// alias.go
+// Copyright (c) 2025 mattmc3
+// SPDX-License-Identifier: MIT
+// Project home: https://github.com/mattmc3/fluentflag
+
+package fluentflag
+
+import (
+	"flag"
+	"fmt"
+	"strings"
+)
+
+// aliasValue implements flag.Value for a flag's aliases, former names, and
+// --no-<name> form, sharing its value. It marks those registrations so they
+// can be told apart from the flag itself.
+type aliasValue struct {
+	flag.Value
+	of string // name of the aliased flag
+}
+
+// IsBoolFlag reports whether the aliased value takes no argument.
+func (self *aliasValue) IsBoolFlag() bool {
+	return isBoolValue(self.Value)
+}
+
+// optionalValue returns the aliased flag's value when given without one.
+func (self *aliasValue) optionalValue() (string, bool) {
+	if ov, ok := self.Value.(interface{ optionalValue() (string, bool) }); ok {
+		return ov.optionalValue()
+	}
+	return "", false
+}
+
+// isList reports whether the aliased value collects a value each time it is set.
+func (self *aliasValue) isList() bool {
+	return isListValue(self.Value)
+}
+
+// IsAlias reports whether f was registered by a FlagBuilder as an alias,
+// former name, or --no-<name> form of another flag, and returns that flag's
+// name. Tools that walk a flag set with VisitAll can use it to skip them.
+func IsAlias(f *flag.Flag) (string, bool) {
+	if av, ok := f.Value.(*aliasValue); ok {
+		return av.of, true
+	}
+	return "", false
+}
+
+// PrintDefaults prints the flag set's flags in the style of
+// flag.PrintDefaults, listing each flag's aliases with it rather than as
+// separate flags without usage text. Former names and --no-<name> forms are
+// left out.
+func (b *FlagBuilder) PrintDefaults() {
+	aliases := map[string][]string{}
+	b.flagSet.VisitAll(func(fl *flag.Flag) {
+		of, ok := IsAlias(fl)
+		if f := b.lookup(of); ok && f != nil && containsString(f.names(), fl.Name) {
+			aliases[of] = append(aliases[of], fl.Name)
+		}
+	})
+	var sb strings.Builder
+	b.flagSet.VisitAll(func(fl *flag.Flag) {
+		if _, ok := IsAlias(fl); ok {
+			return
+		}
+		start := sb.Len()
+		fmt.Fprintf(&sb, "  -%s", fl.Name)
+		for _, alias := range aliases[fl.Name] {
+			fmt.Fprintf(&sb, ", -%s", alias)
+		}
+		name, usage := flag.UnquoteUsage(fl)
+		f := b.lookup(fl.Name)
+		if f != nil {
+			name = strings.TrimSpace(f.typeString())
+		}
+		if name != "" {
+			sb.WriteString(" " + name)
+		}
+		if sb.Len()-start <= 4 {
+			sb.WriteString("\t")
+		} else {
+			sb.WriteString("\n    \t")
+		}
+		sb.WriteString(strings.ReplaceAll(usage, "\n", "\n    \t"))
+		def := fl.DefValue
+		if f != nil {
+			def = f.defaultString()
+		} else if def == "0" || def == "false" || def == "[]" {
+			def = ""
+		}
+		if def != "" {
+			fmt.Fprintf(&sb, " (default %s)", def)
+		}
+		sb.WriteString("\n")
+	})
+	fmt.Fprint(b.flagSet.Output(), sb.String())
+}
@@ -0,0 +1,62 @@
+//go:build go1.18
+
+package fluentflag
+
+import (
+	"flag"
+	"strings"
+	"testing"
+)
+
+func TestPrintDefaults(t *testing.T) {
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	var out strings.Builder
+	fs.SetOutput(&out)
+	b := NewFlagBuilderWithSet(fs)
+	b.StringFlag("output", "Output file").Alias('o').Default("out.txt").BuildVar()
+	b.BoolFlag("cache", "Use the cache").Negatable().BuildVar()
+	b.IntFlag("jobs", "Parallel jobs").RenamedFrom("j-count").BuildVar()
+	fs.String("plain", "", "A stdlib flag")
+
+	b.PrintDefaults()
+	want := `  -cache
+    	Use the cache
+  -jobs int
+    	Parallel jobs
+  -output, -o string
+    	Output file (default "out.txt")
+  -plain string
+    	A stdlib flag
+`
+	if out.String() != want {
+		t.Errorf("PrintDefaults mismatch.\nGot:\n%s\nWant:\n%s", out.String(), want)
+	}
+}
+
+func TestIsAlias(t *testing.T) {
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	b := NewFlagBuilderWithSet(fs)
+	b.BoolFlag("verbose", "Print more").Alias('v').Negatable().BuildVar()
+
+	got := map[string]string{}
+	fs.VisitAll(func(fl *flag.Flag) {
+		of, ok := IsAlias(fl)
+		if !ok {
+			of = "-"
+		}
+		got[fl.Name] = of
+	})
+	want := map[string]string{"verbose": "-", "v": "verbose", "no-verbose": "verbose"}
+	if len(got) != len(want) {
+		t.Fatalf("expected %v, got %v", want, got)
+	}
+	for name, of := range want {
+		if got[name] != of {
+			t.Errorf("%s: expected %q, got %q", name, of, got[name])
+		}
+	}
+
+	if _, err := b.Parse([]string{"-v", "--no-verbose", "-v"}); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
@@ -0,0 +1,76 @@
+// args.go
+// Copyright (c) 2025 mattmc3
+// SPDX-License-Identifier: MIT
+// Project home: https://github.com/mattmc3/fluentflag
+
+package fluentflag
+
+import (
+	"strconv"
+	"strings"
+)
+
+// ToArgs returns the command-line arguments that reproduce the flags whose
+// values differ from their defaults, like ["--workers=8", "--tag=a",
+// "--tag=b"], in the order the flags were defined. Each value is kept whole in
+// a single argument, so the result can be passed to exec.Command to re-run the
+// program or spawn workers with the same configuration.
+func (b *FlagBuilder) ToArgs() []string {
+	args := []string{}
+	for _, f := range b.flagsBuilt {
+		args = append(args, f.toArgs()...)
+	}
+	return args
+}
+
+// toArgs returns the arguments that set the flag to its current value, or
+// none if it still has the value it was built with. Slice and map flags give
+// one argument per value added since then.
+func (self *FluentFlag[T]) toArgs() []string {
+	name := "--" + self.name
+	vals := self.value.list()
+	if self.isList() {
+		var args []string
+		for _, v := range withoutValues(vals, self.initial) {
+			args = append(args, name+"="+v)
+		}
+		return args
+	}
+	if strings.Join(vals, "\x00") == strings.Join(self.initial, "\x00") {
+		return nil
+	}
+	if c, ok := self.value.(*counterValue); ok {
+		var args []string
+		for i := 0; i < *c.target; i++ {
+			args = append(args, name)
+		}
+		return args
+	}
+	if isBoolValue(self.value) {
+		if on, _ := strconv.ParseBool(vals[0]); on {
+			return []string{name}
+		}
+		if neg := self.negatedName(); neg != "" {
+			return []string{"--" + neg}
+		}
+		return []string{name + "=false"}
+	}
+	return []string{name + "=" + vals[0]}
+}
+
+// withoutValues returns vals with one occurrence of each of remove taken out.
+func withoutValues(vals, remove []string) []string {
+	counts := map[string]int{}
+	for _, v := range remove {
+		counts[v]++
+	}
+	var kept []string
+	for _, v := range vals {
+		if counts[v] > 0 {
+			counts[v]--
+			continue
+		}
+		kept = append(kept, v)
+	}
+	return kept
+}
@@ -0,0 +1,55 @@
+//go:build go1.18
+
+package fluentflag
+
+import (
+	"flag"
+	"reflect"
+	"testing"
+)
+
+func newArgsBuilder() *FlagBuilder {
+	b := NewFlagBuilderWithSet(flag.NewFlagSet("test", flag.ContinueOnError))
+	b.StringFlag("name", "name").Default("foo").BuildVar()
+	b.IntFlag("workers", "workers").Default(4).BuildVar()
+	b.StringFlag("tag", "tags").BuildSlice()
+	b.StringFlag("label", "labels").BuildMap()
+	b.BoolFlag("debug", "debug mode").BuildVar()
+	b.BoolFlag("cache", "use the cache").Default(true).Negatable().BuildVar()
+	b.BoolFlag("verbose", "more output").Alias('v').BuildCounter()
+	return b
+}
+
+func TestToArgs(t *testing.T) {
+	tests := []struct {
+		name string
+		args []string
+		want []string
+	}{
+		{"defaults", []string{}, []string{}},
+		{"default value given", []string{"--workers=4"}, []string{}},
+		{"scalars", []string{"--workers", "8", "--name=a b"}, []string{"--name=a b", "--workers=8"}},
+		{"lists", []string{"--tag=a", "--label", "k=v", "--tag", "-b"}, []string{"--tag=a", "--tag=-b", "--label=k=v"}},
+		{"bools", []string{"--debug", "--no-cache"}, []string{"--debug", "--no-cache"}},
+		{"counter", []string{"-vvv"}, []string{"--verbose", "--verbose", "--verbose"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b := newArgsBuilder()
+			if _, err := b.Parse(tt.args); err != nil {
+				t.Fatalf("Parse failed: %v", err)
+			}
+			got := b.ToArgs()
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Fatalf("expected %q, got %q", tt.want, got)
+			}
+			again := newArgsBuilder()
+			if _, err := again.Parse(got); err != nil {
+				t.Fatalf("Parse of ToArgs failed: %v", err)
+			}
+			if !reflect.DeepEqual(again.ToArgs(), got) {
+				t.Errorf("round trip: expected %q, got %q", got, again.ToArgs())
+			}
+		})
+	}
+}
@@ -0,0 +1,305 @@
+// bind.go
+// Copyright (c) 2025 mattmc3
+// SPDX-License-Identifier: MIT
+// Project home: https://github.com/mattmc3/fluentflag
+
+package fluentflag
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// fieldTag holds the struct tags Bind reads from a field.
+type fieldTag struct {
+	names    []string // flag name, then aliases
+	def      string   // default value, if given
+	hasDef   bool     // whether a default tag was given
+	usage    string
+	env      string
+	group    string
+	required bool
+}
+
+// fieldBinder registers a flag bound to the field ptr points to.
+type fieldBinder func(b *FlagBuilder, ptr any, tag fieldTag) error
+
+// fieldBinders bind the field types Bind supports: each flag type, slices of
+// them, and maps of them with string keys, and times and slices of times.
+var fieldBinders = newFieldBinders()
+
+// newFieldBinders returns the binders for each supported field type.
+func newFieldBinders() map[reflect.Type]fieldBinder {
+	binders := map[reflect.Type]fieldBinder{}
+	addFieldBinders[bool](binders)
+	addFieldBinders[string](binders)
+	addFieldBinders[int](binders)
+	addFieldBinders[int64](binders)
+	addFieldBinders[uint](binders)
+	addFieldBinders[uint64](binders)
+	addFieldBinders[float64](binders)
+	addFieldBinders[time.Duration](binders)
+	binders[reflect.TypeOf(time.Time{})] = bindTime
+	binders[reflect.TypeOf([]time.Time{})] = bindTimes
+	return binders
+}
+
+// addFieldBinders adds binders for T, []T, and map[string]T.
+func addFieldBinders[T FlagType](binders map[reflect.Type]fieldBinder) {
+	binders[reflect.TypeOf((*T)(nil)).Elem()] = bindValue[T]
+	binders[reflect.TypeOf((*[]T)(nil)).Elem()] = bindSlice[T]
+	binders[reflect.TypeOf((*map[string]T)(nil)).Elem()] = bindMap[T]
+}
+
+// Bind registers a flag for each field of the struct ptr points to that has
+// a `flag` tag, and binds the flag to the field, so parsed values are written
+// back into the struct:
+//
+//	type Config struct {
+//		Port    int           `flag:"port,p" default:"8080" usage:"listen port"`
+//		Timeout time.Duration `flag:"timeout" usage:"request timeout" env:"APP_TIMEOUT"`
+//		Tags    []string      `flag:"tag" usage:"tags to apply" group:"Output"`
+//	}
+//
+// The flag tag lists the flag's name and then its aliases, one character
+// long for short ones. The default tag is parsed as the field's type; without
+// one, the field's current value is the default. The usage, env, and group
+// tags are passed to Usage, Env, and Group, and required:"true" makes the
+// flag required. Slice fields collect each value given and map fields each
+// key=value pair, starting empty. Fields of nested structs are bound too.
+// Fields must be exported, of a flag type, or a slice or string-keyed map of
+// one.
+func (b *FlagBuilder) Bind(ptr any) {
+	v := reflect.ValueOf(ptr)
+	if v.Kind() != reflect.Pointer || v.IsNil() || v.Elem().Kind() != reflect.Struct {
+		b.fail(fmt.Errorf("fluentflag: Bind requires a pointer to a struct, got %T", ptr))
+		return
+	}
+	b.fail(b.bindStruct(v.Elem()))
+}
+
+// bindStruct binds the tagged fields of the struct v, recursing into untagged
+// struct fields.
+func (b *FlagBuilder) bindStruct(v reflect.Value) error {
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		raw, ok := field.Tag.Lookup("flag")
+		if !ok {
+			if field.Type.Kind() == reflect.Struct && field.Type != reflect.TypeOf(time.Time{}) && field.IsExported() {
+				if err := b.bindStruct(v.Field(i)); err != nil {
+					return err
+				}
+			}
+			continue
+		}
+		if raw == "-" {
+			continue
+		}
+		tag, err := parseFieldTag(field, raw)
+		if err != nil {
+			return err
+		}
+		if !field.IsExported() {
+			return fmt.Errorf("fluentflag: Bind: field %s for --%s is unexported", field.Name, tag.names[0])
+		}
+		bind, ok := fieldBinders[field.Type]
+		if !ok {
+			return fmt.Errorf("fluentflag: Bind: field %s for --%s has unsupported type %s", field.Name, tag.names[0], field.Type)
+		}
+		if err := bind(b, v.Field(i).Addr().Interface(), tag); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// parseFieldTag reads the struct tags Bind uses from field.
+func parseFieldTag(field reflect.StructField, raw string) (fieldTag, error) {
+	tag := fieldTag{
+		usage: field.Tag.Get("usage"),
+		env:   field.Tag.Get("env"),
+		group: field.Tag.Get("group"),
+	}
+	for _, name := range strings.Split(raw, ",") {
+		if name = strings.TrimSpace(name); name != "" {
+			tag.names = append(tag.names, name)
+		}
+	}
+	if len(tag.names) == 0 {
+		return tag, fmt.Errorf("fluentflag: Bind: field %s has an empty flag tag", field.Name)
+	}
+	tag.def, tag.hasDef = field.Tag.Lookup("default")
+	if s, ok := field.Tag.Lookup("required"); ok {
+		required, err := strconv.ParseBool(s)
+		if err != nil {
+			return tag, fmt.Errorf("fluentflag: Bind: field %s has an invalid required tag %q", field.Name, s)
+		}
+		tag.required = required
+	}
+	return tag, nil
+}
+
+// newFieldFlag starts a flag of type T configured by tag.
+func newFieldFlag[T FlagType](b *FlagBuilder, tag fieldTag) *FluentFlag[T] {
+	f := newFlag[T](b, tag.names[0], tag.usage)
+	f.addAliases(tag.names[1:]...)
+	if tag.env != "" {
+		f.Env(tag.env)
+	}
+	if tag.group != "" {
+		f.Group(tag.group)
+	}
+	if tag.required {
+		f.Required()
+	}
+	return f
+}
+
+// bindValue binds a flag of type T to the field ptr points to.
+func bindValue[T FlagType](b *FlagBuilder, ptr any, tag fieldTag) error {
+	p := ptr.(*T)
+	def := *p
+	if tag.hasDef {
+		v, err := parse[T](tag.def)
+		if err != nil {
+			return fmt.Errorf("fluentflag: Bind: --%s has an invalid default %q: %v", tag.names[0], tag.def, err)
+		}
+		def = v
+	}
+	return newFieldFlag[T](b, tag).Default(def).TryBuild(p)
+}
+
+// bindSlice binds a flag collecting values of type T to the slice field ptr
+// points to.
+func bindSlice[T FlagType](b *FlagBuilder, ptr any, tag fieldTag) error {
+	p := ptr.(*[]T)
+	*p = []T{}
+	f := newFieldFlag[T](b, tag)
+	return f.register(&accumValues[T]{flag: f, target: p})
+}
+
+// bindMap binds a flag collecting key=value pairs to the map field ptr points
+// to.
+func bindMap[T FlagType](b *FlagBuilder, ptr any, tag fieldTag) error {
+	p := ptr.(*map[string]T)
+	*p = map[string]T{}
+	f := newFieldFlag[T](b, tag)
+	return f.register(&mapValues[string, T]{flag: f, parseKey: parseMapKey, target: p})
+}
+
+// bindTime binds a time flag, parsed as RFC 3339, to the field ptr points to.
+func bindTime(b *FlagBuilder, ptr any, tag fieldTag) error {
+	p := ptr.(*time.Time)
+	f := newFieldFlag[string](b, tag)
+	f.layout = time.RFC3339
+	if tag.hasDef {
+		f.Default(tag.def)
+	} else if !p.IsZero() {
+		f.Default(p.Format(time.RFC3339))
+	}
+	return f.tryBuildTime(p)
+}
+
+// bindTimes binds a time flag collecting times to the slice field ptr points
+// to.
+func bindTimes(b *FlagBuilder, ptr any, tag fieldTag) error {
+	p := ptr.(*[]time.Time)
+	*p = []time.Time{}
+	f := newFieldFlag[string](b, tag)
+	f.layout = time.RFC3339
+	return f.tryBuildTimes(p)
+}
+
+// Unmarshal copies the values of b's flags, as resolved by Parse, into the
+// struct ptr points to, so a program can pass one config value around rather
+// than many pointers. A field gets the flag named first in its `flag` tag,
+// or else the flag whose name converts to the field's name, so --min-args
+// fills MinArgs. Fields without a matching flag are left alone, and fields of
+// nested structs are filled too. Slices and maps are copied. It returns an
+// error if a tagged field's flag doesn't exist or a field can't hold its
+// flag's value.
+func Unmarshal(b *FlagBuilder, ptr any) error {
+	v := reflect.ValueOf(ptr)
+	if v.Kind() != reflect.Pointer || v.IsNil() || v.Elem().Kind() != reflect.Struct {
+		return fmt.Errorf("fluentflag: Unmarshal requires a pointer to a struct, got %T", ptr)
+	}
+	return b.unmarshalStruct(v.Elem())
+}
+
+// unmarshalStruct fills the fields of the struct v from matching flags.
+func (b *FlagBuilder) unmarshalStruct(v reflect.Value) error {
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+		var f builtFlag
+		if raw, ok := field.Tag.Lookup("flag"); ok {
+			name, _, _ := strings.Cut(raw, ",")
+			if name = strings.TrimSpace(name); name == "-" {
+				continue
+			}
+			if f = b.lookup(name); f == nil {
+				return fmt.Errorf("fluentflag: Unmarshal: no flag --%s for field %s", name, field.Name)
+			}
+		} else {
+			for _, built := range b.flagsBuilt {
+				if goFieldName(built.names()[0]) == field.Name {
+					f = built
+					break
+				}
+			}
+		}
+		if f == nil {
+			if field.Type.Kind() == reflect.Struct {
+				if err := b.unmarshalStruct(v.Field(i)); err != nil {
+					return err
+				}
+			}
+			continue
+		}
+		val := f.boundValue().Get()
+		if !setField(v.Field(i), val) {
+			return fmt.Errorf("fluentflag: Unmarshal: field %s has type %s, but --%s is %T", field.Name, field.Type, f.names()[0], val)
+		}
+	}
+	return nil
+}
+
+// setField sets field to a copy of val, converting between types of the same
+// kind, like int and a named int type. It reports false if field can't hold
+// val.
+func setField(field reflect.Value, val any) bool {
+	src := reflect.ValueOf(val)
+	switch {
+	case src.Type().AssignableTo(field.Type()):
+	case src.Kind() == field.Kind() && src.Type().ConvertibleTo(field.Type()):
+		src = src.Convert(field.Type())
+	default:
+		return false
+	}
+	switch src.Kind() {
+	case reflect.Slice:
+		if !src.IsNil() {
+			dst := reflect.MakeSlice(src.Type(), src.Len(), src.Len())
+			reflect.Copy(dst, src)
+			src = dst
+		}
+	case reflect.Map:
+		if !src.IsNil() {
+			dst := reflect.MakeMapWithSize(src.Type(), src.Len())
+			iter := src.MapRange()
+			for iter.Next() {
+				dst.SetMapIndex(iter.Key(), iter.Value())
+			}
+			src = dst
+		}
+	}
+	field.Set(src)
+	return true
+}
@@ -0,0 +1,199 @@
+//go:build go1.18
+
+package fluentflag
+
+import (
+	"flag"
+	"reflect"
+	"testing"
+	"time"
+)
+
+type bindTestConfig struct {
+	Port    int               `flag:"port,p" default:"8080" usage:"listen port"`
+	Host    string            `flag:"host" usage:"host to bind"`
+	Timeout time.Duration     `flag:"timeout" usage:"request timeout" env:"BIND_TEST_TIMEOUT"`
+	Verbose bool              `flag:"verbose,v" usage:"print more" group:"Logging"`
+	Tags    []string          `flag:"tag,t" usage:"tags to apply"`
+	Labels  map[string]string `flag:"label" usage:"labels to set"`
+	Skipped string            `flag:"-"`
+	Ignored string
+	TLS     struct {
+		Cert string `flag:"tls-cert" usage:"certificate file" required:"true"`
+	}
+}
+
+func TestBind(t *testing.T) {
+	b := NewFlagBuilderWithSet(flag.NewFlagSet("test", flag.ContinueOnError))
+	cfg := bindTestConfig{Host: "localhost"}
+	b.Bind(&cfg)
+
+	args := []string{"-p", "9000", "-v", "-t", "a", "--tag=b", "--label", "env=prod", "--tls-cert", "c.pem", "--timeout", "5s"}
+	if _, err := b.Parse(args); err != nil {
+		t.Fatalf("Parse returned error: %v", err)
+	}
+	expected := bindTestConfig{
+		Port:    9000,
+		Host:    "localhost",
+		Timeout: 5 * time.Second,
+		Verbose: true,
+		Tags:    []string{"a", "b"},
+		Labels:  map[string]string{"env": "prod"},
+	}
+	expected.TLS.Cert = "c.pem"
+	if !reflect.DeepEqual(cfg, expected) {
+		t.Errorf("bound struct mismatch.\nGot:  %+v\nWant: %+v", cfg, expected)
+	}
+}
+
+func TestBind_Times(t *testing.T) {
+	var cfg struct {
+		Since time.Time   `flag:"since" default:"2025-01-01T00:00:00Z"`
+		Days  []time.Time `flag:"day"`
+	}
+	b := NewFlagBuilderWithSet(flag.NewFlagSet("test", flag.ContinueOnError))
+	b.Bind(&cfg)
+	if want := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC); !cfg.Since.Equal(want) {
+		t.Errorf("expected default %v, got %v", want, cfg.Since)
+	}
+	if _, err := b.Parse([]string{"--day=2025-03-01T00:00:00Z", "--day=2025-03-02T00:00:00Z"}); err != nil {
+		t.Fatalf("Parse returned error: %v", err)
+	}
+	if len(cfg.Days) != 2 || cfg.Days[1].Day() != 2 {
+		t.Errorf("unexpected days: %v", cfg.Days)
+	}
+}
+
+func TestBind_Usage(t *testing.T) {
+	b := NewFlagBuilderWithSet(flag.NewFlagSet("test", flag.ContinueOnError))
+	var cfg bindTestConfig
+	b.Bind(&cfg)
+
+	expected := `  -p, --port int           listen port (default 8080)
+      --host string        host to bind
+      --timeout duration   request timeout [env: BIND_TEST_TIMEOUT]
+  -t, --tag string         tags to apply
+      --label string       labels to set
+      --tls-cert string    certificate file (required)
+
+Logging:
+  -v, --verbose            print more
+`
+	if actual := b.UsageStringWidth(0); actual != expected {
+		t.Errorf("Usage output mismatch.\nGot:\n%s\nWant:\n%s", actual, expected)
+	}
+}
+
+func TestBind_Errors(t *testing.T) {
+	tests := []struct {
+		name string
+		ptr  any
+		err  string
+	}{
+		{"not a pointer", bindTestConfig{}, "fluentflag: Bind requires a pointer to a struct, got fluentflag.bindTestConfig"},
+		{"unsupported type", &struct {
+			Ratio float32 `flag:"ratio"`
+		}{}, "fluentflag: Bind: field Ratio for --ratio has unsupported type float32"},
+		{"unexported", &struct {
+			port int `flag:"port"`
+		}{}, "fluentflag: Bind: field port for --port is unexported"},
+		{"bad default", &struct {
+			Port int `flag:"port" default:"high"`
+		}{}, `fluentflag: Bind: --port has an invalid default "high": strconv.ParseInt: parsing "high": invalid syntax`},
+		{"bad required", &struct {
+			Port int `flag:"port" required:"yes"`
+		}{}, `fluentflag: Bind: field Port has an invalid required tag "yes"`},
+		{"empty name", &struct {
+			Port int `flag:","`
+		}{}, "fluentflag: Bind: field Port has an empty flag tag"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b := NewFlagBuilderWithSet(flag.NewFlagSet("test", flag.ContinueOnError))
+			b.SetErrorHandling(ReturnErrors)
+			b.Bind(tt.ptr)
+			if err := b.Err(); err == nil || err.Error() != tt.err {
+				t.Errorf("got error %v, want %q", err, tt.err)
+			}
+		})
+	}
+}
+
+func TestUnmarshal(t *testing.T) {
+	type level int
+	type config struct {
+		Name      string
+		MinArgs   level
+		Workers   int               `flag:"jobs"`
+		Tags      []string          `flag:"tag"`
+		Labels    map[string]string `flag:"label"`
+		Verbose   int               `flag:"v"`
+		Untouched string
+		Server    struct {
+			Timeout time.Duration
+		}
+	}
+	b := NewFlagBuilderWithSet(flag.NewFlagSet("test", flag.ContinueOnError))
+	b.StringFlag("name", "name").Default("app").BuildVar()
+	b.IntFlag("min-args", "minimum").BuildVar()
+	b.IntFlag("jobs", "jobs").Alias('j').BuildVar()
+	b.StringFlag("tag", "tags").BuildSlice()
+	b.StringFlag("label", "labels").BuildMap()
+	b.BoolFlag("verbose", "verbosity").Alias('v').BuildCounter()
+	b.DurationFlag("timeout", "timeout").Default(time.Second).BuildVar()
+	if _, err := b.Parse([]string{"--min-args=2", "-j", "4", "--tag=a", "--tag=b", "--label=k=v", "-vv"}); err != nil {
+		t.Fatalf("Parse returned error: %v", err)
+	}
+
+	cfg := config{Untouched: "kept"}
+	if err := Unmarshal(b, &cfg); err != nil {
+		t.Fatalf("Unmarshal returned error: %v", err)
+	}
+	expected := config{
+		Name:      "app",
+		MinArgs:   2,
+		Workers:   4,
+		Tags:      []string{"a", "b"},
+		Labels:    map[string]string{"k": "v"},
+		Verbose:   2,
+		Untouched: "kept",
+	}
+	expected.Server.Timeout = time.Second
+	if !reflect.DeepEqual(cfg, expected) {
+		t.Errorf("unmarshaled struct mismatch.\nGot:  %+v\nWant: %+v", cfg, expected)
+	}
+
+	cfg.Tags[0] = "changed"
+	if _, err := b.Parse([]string{"--tag=x"}); err != nil {
+		t.Fatalf("Parse returned error: %v", err)
+	}
+	if cfg.Tags[0] != "changed" {
+		t.Error("expected Unmarshal to copy slices")
+	}
+}
+
+func TestUnmarshal_Errors(t *testing.T) {
+	b := NewFlagBuilderWithSet(flag.NewFlagSet("test", flag.ContinueOnError))
+	b.IntFlag("port", "port").BuildVar()
+
+	tests := []struct {
+		name string
+		ptr  any
+		err  string
+	}{
+		{"not a pointer", struct{}{}, "fluentflag: Unmarshal requires a pointer to a struct, got struct {}"},
+		{"unknown flag", &struct {
+			Host string `flag:"host"`
+		}{}, "fluentflag: Unmarshal: no flag --host for field Host"},
+		{"wrong type", &struct {
+			Port string
+		}{}, "fluentflag: Unmarshal: field Port has type string, but --port is int"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := Unmarshal(b, tt.ptr); err == nil || err.Error() != tt.err {
+				t.Errorf("got error %v, want %q", err, tt.err)
+			}
+		})
+	}
+}
@@ -0,0 +1,95 @@
+// clone.go
+// Copyright (c) 2025 mattmc3
+// SPDX-License-Identifier: MIT
+// Project home: https://github.com/mattmc3/fluentflag
+
+package fluentflag
+
+import "flag"
+
+// rebuildFunc defines a flag or positional argument again on b, a clone of
+// the builder it was defined on.
+type rebuildFunc func(b *FlagBuilder)
+
+// Clone returns a new builder with the same flags, positional arguments,
+// subcommands, and settings, but its own flag set and fresh storage holding
+// the defaults, so tests and parallel workers can parse different command
+// lines without sharing state. Values are read from the clone with Get,
+// Lookup, or Unmarshal, since the pointers returned when the flags were built
+// still refer to the original. Functions given to the flags and builder, such
+// as validators and PostParse hooks, are shared with the original.
+func (b *FlagBuilder) Clone() *FlagBuilder {
+	clone := *b
+	c := &clone
+	c.flagSet = flag.NewFlagSet(b.flagSet.Name(), b.flagSet.ErrorHandling())
+	c.flagSet.SetOutput(b.flagSet.Output())
+	c.flagSet.Usage = func() {
+		c.printHelp(c.outputWriter())
+	}
+	c.flagsBuilt, c.positionals, c.commands = nil, nil, nil
+	c.building, c.activeTheme = nil, nil
+	c.forgetParse()
+	c.postParse = append([]parseHook(nil), b.postParse...)
+	c.configErrs = append([]error(nil), b.configErrs...)
+	c.examples = append([]usageExample(nil), b.examples...)
+	c.constraints = append([]flagConstraint(nil), b.constraints...)
+	c.groups = nil
+	for _, g := range b.groups {
+		group := *g
+		c.groups = append(c.groups, &group)
+	}
+	c.reserved = cloneMap(b.reserved)
+	c.decoders = cloneMap(b.decoders)
+	c.dotenv = cloneMap(b.dotenv)
+	for _, f := range b.flagsBuilt {
+		c.fail(f.cloneTo(c))
+	}
+	for _, p := range b.positionals {
+		p.rebuild(c)
+	}
+	if fl := b.flagSet.Lookup("completion"); fl != nil {
+		if _, ok := fl.Value.(*completionValue); ok {
+			c.WithCompletion()
+		}
+	}
+	for _, cmd := range b.commands {
+		sub := cmd.Clone()
+		sub.parent = c
+		c.commands = append(c.commands, sub)
+	}
+	return c
+}
+
+// Clone is like FlagBuilder.Clone, but returns a command with the same name,
+// usage, and Run function.
+func (c *Command) Clone() *Command {
+	return &Command{FlagBuilder: c.FlagBuilder.Clone(), name: c.name, usage: c.usage, run: c.run}
+}
+
+// cloneTo defines a copy of the flag on b, with fresh storage holding its
+// default.
+func (self *FluentFlag[T]) cloneTo(b *FlagBuilder) error {
+	if self.rebuild != nil {
+		self.rebuild(b)
+		return nil
+	}
+	clone := new(FluentFlag[T]) // allocate on heap
+	*clone = *self
+	clone.builder = b
+	val := self.value.(interface {
+		cloneFor(f *FluentFlag[T]) fluentValue
+	}).cloneFor(clone)
+	return clone.register(val)
+}
+
+// cloneMap returns a copy of m, or nil if m is nil.
+func cloneMap[K comparable, V any](m map[K]V) map[K]V {
+	if m == nil {
+		return nil
+	}
+	clone := make(map[K]V, len(m))
+	for k, v := range m {
+		clone[k] = v
+	}
+	return clone
+}
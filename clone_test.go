@@ -0,0 +1,108 @@
+//go:build go1.18
+
+package fluentflag
+
+import (
+	"flag"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func newCloneBuilder() (*FlagBuilder, *int) {
+	b := NewFlagBuilderWithSet(flag.NewFlagSet("test", flag.ContinueOnError))
+	b.EnableHelp()
+	workers := b.IntFlag("workers", "worker count").Alias('w').Default(4).Max(64).BuildVar()
+	b.StringFlag("tag", "tags").BuildSlice()
+	b.StringFlag("label", "labels").BuildMap()
+	b.BoolFlag("cache", "use the cache").Default(true).Negatable().BuildVar()
+	b.BoolFlag("verbose", "more output").Alias('v').BuildCounter()
+	b.Positional("file", "input file").Required().BuildString()
+	return b, workers
+}
+
+func TestClone(t *testing.T) {
+	b, workers := newCloneBuilder()
+	if _, err := b.Parse([]string{"-w", "8", "--tag=a", "in.txt"}); err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	clone := b.Clone()
+	if got := clone.ToArgs(); len(got) != 0 {
+		t.Errorf("expected clone to start at defaults, got %q", got)
+	}
+	if _, err := clone.Parse([]string{"--workers=16", "--no-cache", "--label=k=v", "-vv", "out.txt"}); err != nil {
+		t.Fatalf("Parse of clone failed: %v", err)
+	}
+	want := []string{"--workers=16", "--label=k=v", "--no-cache", "--verbose", "--verbose"}
+	if got := clone.ToArgs(); !reflect.DeepEqual(got, want) {
+		t.Errorf("expected clone args %q, got %q", want, got)
+	}
+	if n, err := Get[int](clone, "workers"); err != nil || n != 16 {
+		t.Errorf("expected clone --workers 16, got %d, %v", n, err)
+	}
+	if want := []string{"--workers=8", "--tag=a"}; !reflect.DeepEqual(b.ToArgs(), want) || *workers != 8 {
+		t.Errorf("expected original args %q, got %q", want, b.ToArgs())
+	}
+	if b.UsageStringWidth(80) != clone.UsageStringWidth(80) {
+		t.Errorf("expected the same usage.\nOriginal:\n%s\nClone:\n%s", b.UsageStringWidth(80), clone.UsageStringWidth(80))
+	}
+	if _, err := clone.Parse([]string{"--workers=100", "x"}); err == nil || !strings.Contains(err.Error(), "workers") {
+		t.Errorf("expected the clone to keep validation, got %v", err)
+	}
+	if _, err := clone.Parse([]string{}); err == nil || !strings.Contains(err.Error(), "missing argument <file>") {
+		t.Errorf("expected the clone to keep positional arguments, got %v", err)
+	}
+}
+
+func TestClone_Help(t *testing.T) {
+	b, _ := newCloneBuilder()
+	clone := b.Clone()
+	clone.StringFlag("extra", "only on the clone").BuildVar()
+	var out strings.Builder
+	clone.SetHelpOutput(&out)
+	clone.SetExitFunc(func(int) {})
+	clone.Parse([]string{"--help", "x"})
+	if !strings.Contains(out.String(), "--extra") {
+		t.Errorf("expected the clone's own help, got:\n%s", out.String())
+	}
+}
+
+func TestClone_Parallel(t *testing.T) {
+	b, _ := newCloneBuilder()
+	var wg sync.WaitGroup
+	for i := 1; i <= 8; i++ {
+		wg.Add(1)
+		go func(n int) {
+			defer wg.Done()
+			clone := b.Clone()
+			args := []string{"--workers=" + string(rune('0'+n)), "in.txt"}
+			if _, err := clone.Parse(args); err != nil {
+				t.Errorf("Parse failed: %v", err)
+				return
+			}
+			if got, _ := Get[int](clone, "workers"); got != n {
+				t.Errorf("expected --workers %d, got %d", n, got)
+			}
+		}(i)
+	}
+	wg.Wait()
+}
+
+func TestClone_Subcommand(t *testing.T) {
+	app := NewAppWithSet(flag.NewFlagSet("app", flag.ContinueOnError))
+	app.BoolFlag("debug", "debug mode").Persistent().BuildVar()
+	serve := app.Command("serve", "start the server")
+	serve.IntFlag("port", "port").Default(80).BuildVar()
+	clone := app.Clone()
+	cmd, _, err := clone.Dispatch([]string{"serve", "--debug", "--port=8080"})
+	if err != nil {
+		t.Fatalf("Dispatch failed: %v", err)
+	}
+	if cmd.Name() != "serve" || !clone.Changed("debug") || app.Changed("debug") {
+		t.Errorf("expected the clone's serve and --debug to be set on the clone only")
+	}
+	if port, _ := Get[int](cmd.FlagBuilder, "port"); port != 8080 {
+		t.Errorf("expected --port 8080, got %d", port)
+	}
+}
@@ -0,0 +1,63 @@
+// codegen.go
+// Copyright (c) 2025 mattmc3
+// SPDX-License-Identifier: MIT
+// Project home: https://github.com/mattmc3/fluentflag
+
+package fluentflag
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"io"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// GenerateGoStruct writes a Go struct declaration named typeName with one
+// field per built flag. Each field carries `flag` and `usage` struct tags.
+func (b *FlagBuilder) GenerateGoStruct(w io.Writer, typeName string) error {
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "type %s struct {\n", typeName)
+	for _, f := range b.flagsBuilt {
+		names := f.names()
+		tag := fmt.Sprintf("flag:%q usage:%q", strings.Join(names, ","), f.flagUsage())
+		if strings.Contains(tag, "`") {
+			tag = strconv.Quote(tag)
+		} else {
+			tag = "`" + tag + "`"
+		}
+		fmt.Fprintf(&buf, "%s %s %s\n", goFieldName(names[0]), f.goType(), tag)
+	}
+	buf.WriteString("}\n")
+	src, err := format.Source(buf.Bytes())
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(src)
+	return err
+}
+
+// goFieldName converts a flag name like "min-args" to an exported Go
+// identifier like "MinArgs".
+func goFieldName(name string) string {
+	var sb strings.Builder
+	upper := true
+	for _, r := range name {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+			upper = true
+			continue
+		}
+		if upper {
+			r = unicode.ToUpper(r)
+			upper = false
+		}
+		sb.WriteRune(r)
+	}
+	field := sb.String()
+	if field == "" || !unicode.IsLetter([]rune(field)[0]) {
+		field = "Flag" + field
+	}
+	return field
+}
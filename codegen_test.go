@@ -0,0 +1,50 @@
+//go:build go1.18
+
+package fluentflag
+
+import (
+	"flag"
+	"strings"
+	"testing"
+)
+
+func TestGenerateGoStruct(t *testing.T) {
+	b := NewFlagBuilderWithSet(flag.NewFlagSet("test", flag.ContinueOnError))
+	b.StringFlag("name", "Command name").Alias('n').BuildVar()
+	b.BoolFlag("dry-run", "Don't do anything").BuildVar()
+	b.IntFlag("min-args", "Minimum args").BuildVar()
+	b.Float64Flag("ratio", "A ratio").BuildVar()
+	b.Uint64Flag("size", "Size in bytes").BuildVar()
+	b.StringFlag("tag", "Tags").BuildSlice()
+
+	var buf strings.Builder
+	if err := b.GenerateGoStruct(&buf, "Config"); err != nil {
+		t.Fatalf("GenerateGoStruct failed: %v", err)
+	}
+	expected := "type Config struct {\n" +
+		"\tName    string   `flag:\"name,n\" usage:\"Command name\"`\n" +
+		"\tDryRun  bool     `flag:\"dry-run\" usage:\"Don't do anything\"`\n" +
+		"\tMinArgs int      `flag:\"min-args\" usage:\"Minimum args\"`\n" +
+		"\tRatio   float64  `flag:\"ratio\" usage:\"A ratio\"`\n" +
+		"\tSize    uint64   `flag:\"size\" usage:\"Size in bytes\"`\n" +
+		"\tTag     []string `flag:\"tag\" usage:\"Tags\"`\n" +
+		"}\n"
+	if buf.String() != expected {
+		t.Errorf("struct mismatch.\nGot:\n%s\nWant:\n%s", buf.String(), expected)
+	}
+}
+
+func TestGoFieldName(t *testing.T) {
+	tests := map[string]string{
+		"name":           "Name",
+		"min-args":       "MinArgs",
+		"log_level":      "LogLevel",
+		"2fa":            "Flag2fa",
+		"ignore-unknown": "IgnoreUnknown",
+	}
+	for in, want := range tests {
+		if got := goFieldName(in); got != want {
+			t.Errorf("goFieldName(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
@@ -0,0 +1,106 @@
+// color.go
+// Copyright (c) 2025 mattmc3
+// SPDX-License-Identifier: MIT
+// Project home: https://github.com/mattmc3/fluentflag
+
+package fluentflag
+
+import (
+	"io"
+	"os"
+	"strings"
+)
+
+// Theme holds the ANSI escape sequences usage text is colored with. An empty
+// field leaves that part of the text uncolored.
+type Theme struct {
+	Name    string // flag names, like --output
+	Type    string // value types and placeholders
+	Default string // default values
+	Header  string // section titles, like "Commands:"
+}
+
+// DefaultTheme colors flag names bold, types cyan, defaults yellow, and
+// section titles bold.
+var DefaultTheme = Theme{
+	Name:    "\x1b[1m",
+	Type:    "\x1b[36m",
+	Default: "\x1b[33m",
+	Header:  "\x1b[1m",
+}
+
+// ansiReset ends a colored span.
+const ansiReset = "\x1b[0m"
+
+// SetTheme colors usage text with theme. Colors are only used when usage is
+// printed to a terminal and the NO_COLOR environment variable is unset.
+func (b *FlagBuilder) SetTheme(theme Theme) {
+	b.theme = &theme
+}
+
+// themeFor returns the theme to color usage written to w with, or nil.
+func (b *FlagBuilder) themeFor(w io.Writer) *Theme {
+	if b.theme == nil || os.Getenv("NO_COLOR") != "" {
+		return nil
+	}
+	if f, ok := w.(*os.File); !ok || terminalWidth(f.Fd()) == 0 {
+		return nil
+	}
+	return b.theme
+}
+
+// paint wraps s in the escape sequence code, unless code or s is empty.
+func paint(code, s string) string {
+	if code == "" || s == "" {
+		return s
+	}
+	return code + s + ansiReset
+}
+
+// name colors a flag name, if the theme is not nil.
+func (t *Theme) name(s string) string {
+	if t == nil {
+		return s
+	}
+	return paint(t.Name, s)
+}
+
+// typ colors a value type, if the theme is not nil.
+func (t *Theme) typ(s string) string {
+	if t == nil {
+		return s
+	}
+	return paint(t.Type, s)
+}
+
+// defaultValue colors a default value, if the theme is not nil.
+func (t *Theme) defaultValue(s string) string {
+	if t == nil {
+		return s
+	}
+	return paint(t.Default, s)
+}
+
+// header colors a section title, if the theme is not nil.
+func (t *Theme) header(s string) string {
+	if t == nil {
+		return s
+	}
+	return paint(t.Header, s)
+}
+
+// visibleLen returns the length of s without ANSI escape sequences.
+func visibleLen(s string) int {
+	n := 0
+	for len(s) > 0 {
+		if strings.HasPrefix(s, "\x1b[") {
+			if end := strings.IndexByte(s, 'm'); end != -1 {
+				s = s[end+1:]
+				continue
+			}
+		}
+		s = s[1:]
+		n++
+	}
+	return n
+}
@@ -0,0 +1,58 @@
+//go:build go1.18
+
+package fluentflag
+
+import (
+	"flag"
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestTheme(t *testing.T) {
+	b := NewFlagBuilderWithSet(flag.NewFlagSet("test", flag.ContinueOnError))
+	b.StringFlag("output", "Output file").Alias('o').Default("out.txt").BuildVar()
+	b.BoolFlag("verbose", "Print more").Group("Logging").BuildVar()
+	b.SetTheme(Theme{Name: "\x1b[1m", Type: "\x1b[2m", Default: "\x1b[3m", Header: "\x1b[4m"})
+
+	b.activeTheme = b.theme
+	got := b.UsageStringWidth(0)
+	b.activeTheme = nil
+	got = strings.NewReplacer("\x1b[1m", "<n>", "\x1b[2m", "<t>", "\x1b[3m", "<d>", "\x1b[4m", "<h>", ansiReset, "</>").Replace(got)
+	expected := `  <n>-o</>, <n>--output</> <t>string</>      Output file (default <d>"out.txt"</>)
+
+<h>Logging:</>
+      <n>--verbose</>            Print more
+`
+	if got != expected {
+		t.Errorf("Usage output mismatch.\nGot:\n%s\nWant:\n%s", got, expected)
+	}
+}
+
+func TestTheme_Disabled(t *testing.T) {
+	file, err := os.CreateTemp(t.TempDir(), "usage")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+
+	b := NewFlagBuilderWithSet(flag.NewFlagSet("test", flag.ContinueOnError))
+	t.Setenv("NO_COLOR", "")
+	if b.themeFor(os.Stdout) != nil {
+		t.Error("expected no colors without a theme")
+	}
+	b.SetTheme(DefaultTheme)
+	if b.themeFor(&strings.Builder{}) != nil || b.themeFor(file) != nil {
+		t.Error("expected no colors when output isn't a terminal")
+	}
+	t.Setenv("NO_COLOR", "1")
+	if b.themeFor(os.Stdout) != nil {
+		t.Error("expected no colors with NO_COLOR set")
+	}
+}
+
+func TestVisibleLen(t *testing.T) {
+	if got := visibleLen("\x1b[1m--name\x1b[0m x"); got != 8 {
+		t.Errorf("expected 8, got %d", got)
+	}
+}
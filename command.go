@@ -0,0 +1,218 @@
+// command.go
+// Copyright (c) 2025 mattmc3
+// SPDX-License-Identifier: MIT
+// Project home: https://github.com/mattmc3/fluentflag
+
+package fluentflag
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Command is a program or subcommand with its own flags, defined with its
+// FlagBuilder, and optionally subcommands of its own.
+type Command struct {
+	*FlagBuilder
+	name  string
+	usage string
+	run   func(args []string) error
+}
+
+// NewApp creates the root command of a program with subcommands, named after
+// the program and parsing with flag.ExitOnError like flag.CommandLine.
+func NewApp() *Command {
+	return NewAppWithSet(flag.NewFlagSet(filepath.Base(os.Args[0]), flag.ExitOnError))
+}
+
+// NewAppWithSet creates the root command of a program with subcommands, with
+// its flags defined on flagSet.
+func NewAppWithSet(flagSet *flag.FlagSet) *Command {
+	b := NewFlagBuilderWithSet(flagSet)
+	return &Command{FlagBuilder: b, name: b.flagSet.Name()}
+}
+
+// Command adds a subcommand. It starts with the parent's error handling,
+// output writers, exit function, and usage width, theme, and sorting, and
+// has -h, --help if the parent does.
+func (c *Command) Command(name, usage string) *Command {
+	fs := flag.NewFlagSet(c.flagSet.Name()+" "+name, c.flagSet.ErrorHandling())
+	fs.SetOutput(c.flagSet.Output())
+	b := NewFlagBuilderWithSet(fs)
+	b.output, b.helpOutput = c.output, c.helpOutput
+	b.exitFunc, b.usageWidth = c.exitFunc, c.usageWidth
+	b.errorHandling = c.errorHandling
+	b.theme, b.sortFlags = c.theme, c.sortFlags
+	b.parent = c.FlagBuilder
+	if c.helpEnabled {
+		b.EnableHelp()
+	}
+	sub := &Command{FlagBuilder: b, name: name, usage: usage}
+	c.commands = append(c.commands, sub)
+	return sub
+}
+
+// Name returns the command's name.
+func (c *Command) Name() string {
+	return c.name
+}
+
+// Run sets the function Execute calls when the command is selected, with the
+// arguments left after its flags.
+func (c *Command) Run(fn func(args []string) error) *Command {
+	c.run = fn
+	return c
+}
+
+// Execute dispatches args, usually os.Args[1:], to the command they select
+// and runs it.
+func (c *Command) Execute(args []string) error {
+	cmd, rest, err := c.Dispatch(args)
+	if err != nil || cmd.run == nil {
+		return err
+	}
+	return cmd.run(rest)
+}
+
+// Dispatch parses the command's flags from args and routes the remaining
+// arguments to the subcommand named by the first of them, recursively. It
+// returns the selected command and the arguments left after its flags. A
+// command with subcommands but no Run function requires one to be named.
+// Subcommands are validated before their parents, once all persistent flags
+// have been parsed. Like Parse, it fails while a parse abandoned by
+// ParseContext is still running.
+func (c *Command) Dispatch(args []string) (*Command, []string, error) {
+	if c.parseRunning() {
+		return nil, nil, errParseRunning
+	}
+	if served, err := c.serveCompleteArgs(args); served {
+		return c, nil, err
+	}
+	c.registerGlobalFlags()
+	if err := c.parseArgs(args); err != nil {
+		return nil, nil, err
+	}
+	c.markGlobalFlagsSet()
+	cmd, rest, err := c.dispatchSubcommand(c.rest)
+	if err != nil {
+		return nil, nil, err
+	}
+	if err := c.finishParse(); err != nil {
+		return nil, nil, err
+	}
+	return cmd, rest, nil
+}
+
+// dispatchSubcommand dispatches args to the subcommand named by args[0], if
+// any, or else selects c itself.
+func (c *Command) dispatchSubcommand(args []string) (*Command, []string, error) {
+	if len(c.commands) == 0 {
+		return c, args, nil
+	}
+	if len(args) > 0 {
+		for _, sub := range c.commands {
+			if sub.name == args[0] {
+				return sub.Dispatch(args[1:])
+			}
+		}
+	}
+	if c.run != nil {
+		return c, args, nil
+	}
+	err := fmt.Errorf("missing command (want one of: %s)", strings.Join(c.commandNames(), ", "))
+	if len(args) > 0 {
+		err = fmt.Errorf("unknown command %q (want one of: %s)", args[0], strings.Join(c.commandNames(), ", "))
+	}
+	fmt.Fprintln(c.flagSet.Output(), err)
+	return nil, nil, c.parseFailed(err)
+}
+
+// commandNames returns the names of the command's subcommands.
+func (c *Command) commandNames() []string {
+	var names []string
+	for _, sub := range c.commands {
+		names = append(names, sub.name)
+	}
+	return names
+}
+
+// Persistent makes subcommands inherit the flag, so it can be given before or
+// after the subcommand name, as in "tool --verbose serve" or
+// "tool serve --verbose". Inherited flags are listed under "Global Flags" in
+// each subcommand's usage.
+func (self *FluentFlag[T]) Persistent() *FluentFlag[T] {
+	self.persistent = true
+	return self
+}
+
+// isPersistent reports whether subcommands inherit the flag.
+func (self *FluentFlag[T]) isPersistent() bool {
+	return self.persistent
+}
+
+// markSet records that a persistent flag was set on a subcommand's command
+// line, which its own flag set doesn't see.
+func (self *FluentFlag[T]) markSet() {
+	if self.builder.setOnSubcommand == nil {
+		self.builder.setOnSubcommand = map[string]bool{}
+	}
+	self.builder.setOnSubcommand[self.name] = true
+}
+
+// globalFlags returns the persistent flags the builder inherits from its
+// parent commands, nearest first, leaving out those its own flags shadow.
+func (b *FlagBuilder) globalFlags() []builtFlag {
+	var flags []builtFlag
+	seen := map[string]bool{}
+	for p := b.parent; p != nil; p = p.parent {
+		for _, f := range p.flagsBuilt {
+			name := f.names()[0]
+			if f.isPersistent() && !seen[name] && b.lookup(name) == nil {
+				flags = append(flags, f)
+				seen[name] = true
+			}
+		}
+	}
+	return flags
+}
+
+// registerGlobalFlags defines the inherited persistent flags on the command's
+// flag set, sharing their values with the parent's flags.
+func (c *Command) registerGlobalFlags() {
+	for _, f := range c.globalFlags() {
+		for i, name := range f.names() {
+			if c.flagSet.Lookup(name) != nil {
+				continue
+			}
+			if i == 0 {
+				c.flagSet.Var(f.boundValue(), name, f.flagUsage())
+			} else {
+				c.flagSet.Var(&aliasValue{Value: f.boundValue(), of: f.names()[0]}, name, "")
+			}
+		}
+		if neg := f.negatedName(); neg != "" && c.flagSet.Lookup(neg) == nil {
+			c.flagSet.Var(&aliasValue{Value: &negatedValue{target: f.boundValue()}, of: f.names()[0]}, neg, "")
+		}
+		for _, old := range f.oldNames() {
+			if c.flagSet.Lookup(old) == nil {
+				c.flagSet.Var(&aliasValue{Value: f.boundValue(), of: f.names()[0]}, old, "")
+			}
+		}
+	}
+}
+
+// markGlobalFlagsSet tells parent commands which of their persistent flags
+// were set on this command's command line.
+func (c *Command) markGlobalFlagsSet() {
+	globals := c.globalFlags()
+	c.flagSet.Visit(func(fl *flag.Flag) {
+		for _, f := range globals {
+			if hasName(f, fl.Name) {
+				f.markSet()
+			}
+		}
+	})
+}
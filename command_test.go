@@ -0,0 +1,156 @@
+//go:build go1.18
+
+package fluentflag
+
+import (
+	"flag"
+	"io"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// newTestApp returns an app with serve and migrate subcommands that records
+// which command ran and with what.
+func newTestApp(ran *string, gotArgs *[]string) (*Command, *bool, *int) {
+	app := NewAppWithSet(flag.NewFlagSet("tool", flag.ContinueOnError))
+	app.SetOutput(io.Discard)
+	app.flagSet.SetOutput(io.Discard)
+	verbose := app.BoolFlag("verbose", "more output").BuildVar()
+	serve := app.Command("serve", "Run the server")
+	port := serve.IntFlag("port", "listen port").Default(8080).BuildVar()
+	serve.Run(func(args []string) error {
+		*ran, *gotArgs = "serve", args
+		return nil
+	})
+	app.Command("migrate", "Apply database migrations").Run(func(args []string) error {
+		*ran, *gotArgs = "migrate", args
+		return nil
+	})
+	return app, verbose, port
+}
+
+func TestCommand_Execute(t *testing.T) {
+	tests := []struct {
+		name        string
+		args        []string
+		wantRan     string
+		wantArgs    []string
+		wantVerbose bool
+		wantPort    int
+		wantErr     string
+	}{
+		{"serve", []string{"serve", "--port=9090", "extra"}, "serve", []string{"extra"}, false, 9090, ""},
+		{"root flags first", []string{"--verbose", "migrate"}, "migrate", []string{}, true, 8080, ""},
+		{"missing", []string{}, "", nil, false, 8080, "missing command (want one of: serve, migrate)"},
+		{"unknown", []string{"deploy"}, "", nil, false, 8080, `unknown command "deploy" (want one of: serve, migrate)`},
+		{"subcommand flag error", []string{"serve", "--port=x"}, "", nil, false, 8080, `invalid value "x" for flag -port`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var ran string
+			var args []string
+			app, verbose, port := newTestApp(&ran, &args)
+			err := app.Execute(tt.args)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Errorf("expected error containing %q, got %v", tt.wantErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if ran != tt.wantRan || !reflect.DeepEqual(args, tt.wantArgs) {
+				t.Errorf("expected %s %v, got %s %v", tt.wantRan, tt.wantArgs, ran, args)
+			}
+			if *verbose != tt.wantVerbose || *port != tt.wantPort {
+				t.Errorf("expected verbose=%v port=%d, got verbose=%v port=%d", tt.wantVerbose, tt.wantPort, *verbose, *port)
+			}
+		})
+	}
+}
+
+func TestCommand_Dispatch(t *testing.T) {
+	var ran string
+	var args []string
+	app, _, _ := newTestApp(&ran, &args)
+	cmd, rest, err := app.Dispatch([]string{"serve", "a"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cmd.Name() != "serve" || !reflect.DeepEqual(rest, []string{"a"}) || ran != "" {
+		t.Errorf("expected serve [a] without running, got %s %v (ran %q)", cmd.Name(), rest, ran)
+	}
+}
+
+func TestCommand_Usage(t *testing.T) {
+	var ran string
+	var args []string
+	app, _, _ := newTestApp(&ran, &args)
+	want := `      --verbose            more output
+
+Commands:
+  serve                    Run the server
+  migrate                  Apply database migrations
+`
+	if got := app.UsageStringWidth(80); got != want {
+		t.Errorf("expected usage:\n%s\ngot:\n%s", want, got)
+	}
+}
+
+func TestPersistent(t *testing.T) {
+	tests := []struct {
+		name        string
+		args        []string
+		wantVerbose bool
+		wantConfig  string
+		wantErr     string
+	}{
+		{"before", []string{"--verbose", "--config=a", "serve"}, true, "a", ""},
+		{"after", []string{"serve", "-v", "--config=b"}, true, "b", ""},
+		{"nested", []string{"db", "migrate", "--config=c"}, false, "c", ""},
+		{"required missing", []string{"serve"}, false, "", "--config is required"},
+		{"local flag not inherited", []string{"serve", "--local"}, false, "", "flag provided but not defined: -local"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			app := NewAppWithSet(flag.NewFlagSet("tool", flag.ContinueOnError))
+			app.flagSet.SetOutput(io.Discard)
+			app.SetOutput(io.Discard)
+			verbose := app.BoolFlag("verbose", "more output").Alias('v').Persistent().BuildVar()
+			config := app.StringFlag("config", "config file").Required().Persistent().BuildVar()
+			app.BoolFlag("local", "root only").BuildVar()
+			app.Command("serve", "Run the server")
+			app.Command("db", "Manage the database").Command("migrate", "Apply migrations")
+			cmd, _, err := app.Dispatch(tt.args)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Errorf("expected error containing %q, got %v", tt.wantErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if *verbose != tt.wantVerbose || *config != tt.wantConfig {
+				t.Errorf("expected verbose=%v config=%q, got verbose=%v config=%q (command %s)", tt.wantVerbose, tt.wantConfig, *verbose, *config, cmd.Name())
+			}
+		})
+	}
+}
+
+func TestPersistent_Usage(t *testing.T) {
+	app := NewAppWithSet(flag.NewFlagSet("tool", flag.ContinueOnError))
+	app.BoolFlag("verbose", "more output").Alias('v').Persistent().BuildVar()
+	serve := app.Command("serve", "Run the server")
+	serve.IntFlag("port", "listen port").Default(8080).BuildVar()
+	want := `      --port int           listen port (default 8080)
+
+Global Flags:
+  -v, --verbose            more output
+`
+	if got := serve.UsageStringWidth(80); got != want {
+		t.Errorf("expected usage:\n%s\ngot:\n%s", want, got)
+	}
+}
@@ -0,0 +1,503 @@
+// completion.go
+// Copyright (c) 2025 mattmc3
+// SPDX-License-Identifier: MIT
+// Project home: https://github.com/mattmc3/fluentflag
+
+package fluentflag
+
+import (
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+	"unicode"
+)
+
+// DynamicChoices sets a function that lists the flag's candidate values at
+// completion time, for values that can't be known when the completion script
+// is generated. Generated scripts ask the program for them by running
+// "prog __complete --flag", which the program answers with ServeCompletion.
+func (self *FluentFlag[T]) DynamicChoices(fn func() []string) *FluentFlag[T] {
+	self.dynamicChoices = fn
+	return self
+}
+
+// CompleteFiles makes generated completion scripts complete the flag's value
+// with the shell's own path completion, limited to files matching any of the
+// glob patterns, like "*.yaml", if given. Directories are still offered so
+// that paths can be navigated. PowerShell offers all paths.
+func (self *FluentFlag[T]) CompleteFiles(patterns ...string) *FluentFlag[T] {
+	if !self.requireValue("CompleteFiles") {
+		return self
+	}
+	self.pathKind, self.pathGlobs = "file", patterns
+	return self
+}
+
+// CompleteDirs makes generated completion scripts complete the flag's value
+// with the shell's own path completion, limited to directories.
+func (self *FluentFlag[T]) CompleteDirs() *FluentFlag[T] {
+	if !self.requireValue("CompleteDirs") {
+		return self
+	}
+	self.pathKind, self.pathGlobs = "dir", nil
+	return self
+}
+
+// requireValue fails the builder and returns false if the flag doesn't take
+// a value, naming method in the error.
+func (self *FluentFlag[T]) requireValue(method string) bool {
+	if _, ok := any(self.defaultVal).(bool); ok {
+		self.builder.fail(fmt.Errorf("fluentflag: %s requires a flag that takes a value (--%s)", method, self.name))
+		return false
+	}
+	return true
+}
+
+// pathCompletion returns "file" or "dir" if the flag's values complete as
+// paths, along with any patterns file completion is limited to.
+func (self *FluentFlag[T]) pathCompletion() (kind string, globs []string) {
+	return self.pathKind, self.pathGlobs
+}
+
+// completeFunc returns completion candidates for the prefix being completed.
+type completeFunc func(prefix string) []string
+
+// CompleteWith sets a function that returns the flag's candidate values for
+// the prefix being completed, for things like profiles, regions, or container
+// names that are only known at completion time. Unlike DynamicChoices, fn is
+// given the prefix, so it can look up matches itself, and its results are
+// used as is. Generated scripts ask the program for them by running
+// "prog __complete --flag prefix", which Parse answers.
+func (self *FluentFlag[T]) CompleteWith(fn func(prefix string) []string) *FluentFlag[T] {
+	self.completeWith = fn
+	return self
+}
+
+// completions returns the flag's candidate values starting with prefix.
+func (self *FluentFlag[T]) completions(prefix string) []string {
+	candidates := self.choiceStrings()
+	if self.dynamicChoices != nil {
+		candidates = append(candidates, self.dynamicChoices()...)
+	}
+	var matches []string
+	for _, c := range candidates {
+		if strings.HasPrefix(c, prefix) {
+			matches = append(matches, c)
+		}
+	}
+	if self.completeWith != nil {
+		matches = append(matches, self.completeWith(prefix)...)
+	}
+	return matches
+}
+
+// hasDynamicChoices reports whether the flag's candidates are computed at
+// completion time.
+func (self *FluentFlag[T]) hasDynamicChoices() bool {
+	return self.dynamicChoices != nil || self.completeWith != nil
+}
+
+// completeCommand is the hidden command generated scripts run to ask the
+// program for a flag's candidates computed at completion time.
+const completeCommand = "__complete"
+
+// serveCompleteArgs answers args like "__complete --flag prefix" with
+// ServeCompletion, written to the help output, and exits. It reports whether
+// args were a completion request, which they can only be if some flag has
+// candidates computed at completion time.
+func (b *FlagBuilder) serveCompleteArgs(args []string) (bool, error) {
+	if len(args) == 0 || args[0] != completeCommand {
+		return false, nil
+	}
+	dynamic := false
+	for _, f := range b.flagsBuilt {
+		dynamic = dynamic || f.hasDynamicChoices()
+	}
+	if !dynamic {
+		return false, nil
+	}
+	if err := b.ServeCompletion(args[1:], b.helpWriter()); err != nil {
+		return true, err
+	}
+	b.exit(0)
+	return true, nil
+}
+
+// GenerateCompletion writes a shell completion script for prog to w. The
+// supported shells are "bash", "zsh", "fish", and "powershell".
+func (b *FlagBuilder) GenerateCompletion(w io.Writer, shell, prog string) error {
+	switch shell {
+	case "bash":
+		return b.generateBash(w, prog)
+	case "zsh":
+		return b.generateZsh(w, prog)
+	case "fish":
+		return b.generateFish(w, prog)
+	case "powershell":
+		return b.generatePowerShell(w, prog)
+	default:
+		return fmt.Errorf("fluentflag: unsupported completion shell %q", shell)
+	}
+}
+
+// ServeCompletion answers a completion request from a generated script. args
+// are the arguments following "__complete": the flag being completed and an
+// optional prefix. Matching candidates are written to w, one per line. Parse
+// and Dispatch answer these requests themselves, so this is only needed by
+// programs that handle their arguments another way:
+//
+//	if len(os.Args) > 1 && os.Args[1] == "__complete" {
+//		builder.ServeCompletion(os.Args[2:], os.Stdout)
+//		return
+//	}
+func (b *FlagBuilder) ServeCompletion(args []string, w io.Writer) error {
+	if len(args) == 0 {
+		return fmt.Errorf("fluentflag: __complete requires a flag name")
+	}
+	name := strings.TrimLeft(args[0], "-")
+	f := b.lookup(name)
+	if f == nil {
+		return fmt.Errorf("fluentflag: __complete for unknown flag %s", args[0])
+	}
+	prefix := ""
+	if len(args) > 1 {
+		prefix = args[1]
+	}
+	for _, c := range f.completions(prefix) {
+		if _, err := fmt.Fprintln(w, c); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// WithCompletion registers a hidden --completion=<shell> flag that prints the
+// completion script for the shell to the help output and exits.
+func (b *FlagBuilder) WithCompletion() {
+	b.flagSet.Var(&completionValue{builder: b}, "completion", "print the completion script for a shell (bash, zsh, fish, powershell)")
+}
+
+// completionValue implements the --completion flag.
+type completionValue struct {
+	builder *FlagBuilder
+	shell   string
+}
+
+// String returns the requested shell.
+func (self *completionValue) String() string {
+	return self.shell
+}
+
+// Set prints the completion script for shell and exits.
+func (self *completionValue) Set(shell string) error {
+	b := self.builder
+	if err := b.GenerateCompletion(b.helpWriter(), shell, filepath.Base(b.flagSet.Name())); err != nil {
+		return err
+	}
+	self.shell = shell
+	b.exit(0)
+	return nil
+}
+
+// generateBash writes a bash completion script.
+func (b *FlagBuilder) generateBash(w io.Writer, prog string) error {
+	fn := "_" + strings.Map(func(r rune) rune {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			return r
+		}
+		return '_'
+	}, prog) + "_completion"
+
+	var sb strings.Builder
+	var words []string
+	fmt.Fprintf(&sb, "%s() {\n", fn)
+	sb.WriteString("    local cur=\"${COMP_WORDS[COMP_CWORD]}\" prev=\"${COMP_WORDS[COMP_CWORD-1]}\"\n")
+	sb.WriteString("    case \"$prev\" in\n")
+	for _, f := range b.visibleFlags() {
+		var opts []string
+		for _, name := range f.names() {
+			opts = append(opts, dashed(name))
+		}
+		words = append(words, opts...)
+		if !f.takesValue() {
+			continue
+		}
+		fmt.Fprintf(&sb, "        %s)\n", strings.Join(opts, "|"))
+		switch {
+		case f.hasDynamicChoices():
+			fmt.Fprintf(&sb, "            COMPREPLY=($(compgen -W \"$(%s __complete %s \"$cur\")\" -- \"$cur\"))\n", prog, opts[0])
+		case len(f.completions("")) > 0:
+			fmt.Fprintf(&sb, "            COMPREPLY=($(compgen -W %s -- \"$cur\"))\n", shellQuote(strings.Join(f.completions(""), " ")))
+		case hasPathCompletion(f):
+			kind, globs := f.pathCompletion()
+			var gens []string
+			if kind == "dir" || len(globs) > 0 {
+				gens = append(gens, `$(compgen -d -- "$cur")`)
+			}
+			for _, glob := range globs {
+				gens = append(gens, fmt.Sprintf(`$(compgen -f -X %s -- "$cur")`, shellQuote("!"+glob)))
+			}
+			if kind == "file" && len(globs) == 0 {
+				gens = append(gens, `$(compgen -f -- "$cur")`)
+			}
+			sb.WriteString("            compopt -o filenames 2>/dev/null\n")
+			fmt.Fprintf(&sb, "            COMPREPLY=(%s)\n", strings.Join(gens, " "))
+		default:
+			sb.WriteString("            COMPREPLY=()\n")
+		}
+		sb.WriteString("            return\n")
+		sb.WriteString("            ;;\n")
+	}
+	sb.WriteString("    esac\n")
+	sb.WriteString("    if [[ \"$cur\" == -* ]]; then\n")
+	fmt.Fprintf(&sb, "        COMPREPLY=($(compgen -W %s -- \"$cur\"))\n", shellQuote(strings.Join(words, " ")))
+	sb.WriteString("    fi\n")
+	sb.WriteString("}\n")
+	fmt.Fprintf(&sb, "complete -o default -F %s %s\n", fn, prog)
+	_, err := io.WriteString(w, sb.String())
+	return err
+}
+
+// shellQuote single-quotes s for POSIX shells.
+func shellQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
+
+// generateZsh writes a zsh completion script using _arguments, with each
+// flag's usage as its description. A flag's names exclude each other, so
+// once one is given the others aren't offered, unless the flag is a list.
+func (b *FlagBuilder) generateZsh(w io.Writer, prog string) error {
+	fn := "_" + strings.Map(func(r rune) rune {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			return r
+		}
+		return '_'
+	}, prog)
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "#compdef %s\n\n", prog)
+	fmt.Fprintf(&sb, "%s() {\n", fn)
+	sb.WriteString("    _arguments -s \\\n")
+	for _, f := range b.visibleFlags() {
+		var shorts, longs []string
+		for _, name := range f.names() {
+			if isShortName(name) {
+				shorts = append(shorts, dashed(name))
+			} else {
+				longs = append(longs, dashed(name))
+			}
+		}
+		opts := append(shorts, longs...)
+		exclude := ""
+		switch {
+		case f.isList():
+			exclude = "*"
+		case len(opts) > 1:
+			exclude = "(" + strings.Join(opts, " ") + ")"
+		}
+		desc := "[" + zshEscape(f.flagUsage()) + "]"
+		action := ""
+		if f.takesValue() {
+			switch {
+			case f.hasDynamicChoices():
+				action = fmt.Sprintf(`:%s:{compadd -- ${(f)"$(%s __complete %s "$PREFIX")"}}`, f.names()[0], prog, dashed(f.names()[0]))
+			case len(f.completions("")) > 0:
+				var choices []string
+				for _, c := range f.completions("") {
+					choices = append(choices, zshEscapeChoice(c))
+				}
+				action = fmt.Sprintf(":%s:(%s)", f.names()[0], strings.Join(choices, " "))
+			case hasPathCompletion(f):
+				kind, globs := f.pathCompletion()
+				action = fmt.Sprintf(":%s:_files", f.names()[0])
+				if kind == "dir" {
+					action += " -/"
+				} else if len(globs) > 0 {
+					action += fmt.Sprintf(` -g "%s"`, strings.Join(globs, " "))
+				}
+			default:
+				action = fmt.Sprintf(":%s: ", f.names()[0])
+			}
+		}
+		for _, opt := range opts {
+			if f.takesValue() && strings.HasPrefix(opt, "--") {
+				opt += "="
+			}
+			fmt.Fprintf(&sb, "        %s \\\n", shellQuote(exclude+opt+desc+action))
+		}
+	}
+	sb.WriteString("        '*:file:_files'\n")
+	sb.WriteString("}\n\n")
+	fmt.Fprintf(&sb, "if [ \"$funcstack[1]\" = %s ]; then\n", shellQuote(fn))
+	fmt.Fprintf(&sb, "    %s \"$@\"\n", fn)
+	sb.WriteString("else\n")
+	fmt.Fprintf(&sb, "    compdef %s %s\n", fn, prog)
+	sb.WriteString("fi\n")
+	_, err := io.WriteString(w, sb.String())
+	return err
+}
+
+// zshEscape backslash-escapes the characters that are special in _arguments
+// descriptions.
+func zshEscape(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `[`, `\[`, `]`, `\]`, `:`, `\:`).Replace(s)
+}
+
+// zshEscapeChoice backslash-escapes the characters that are special in an
+// _arguments list of values.
+func zshEscapeChoice(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `:`, `\:`, `(`, `\(`, `)`, `\)`, ` `, `\ `).Replace(s)
+}
+
+// generateFish writes a fish completion script, with a complete line for
+// each flag giving its short and long names, its description, and its
+// choices. Negatable flags get a line for their --no-<name> form too.
+func (b *FlagBuilder) generateFish(w io.Writer, prog string) error {
+	for _, f := range b.visibleFlags() {
+		names := f.names()
+		line := "complete -c " + prog
+		for _, name := range names {
+			if isShortName(name) {
+				line += " -s " + name
+			}
+		}
+		for _, name := range names {
+			if !isShortName(name) {
+				line += " -l " + name
+			}
+		}
+		if f.takesValue() {
+			switch {
+			case f.hasDynamicChoices():
+				line += " -x -a " + fishQuote(fmt.Sprintf("(%s __complete %s (commandline -ct))", prog, dashed(names[0])))
+			case len(f.completions("")) > 0:
+				line += " -x -a " + fishQuote(strings.Join(f.completions(""), " "))
+			case hasPathCompletion(f):
+				line += fishPathCompletion(f)
+			default:
+				line += " -r"
+			}
+		}
+		if usage := f.flagUsage(); usage != "" {
+			line += " -d " + fishQuote(usage)
+		}
+		if _, err := fmt.Fprintln(w, line); err != nil {
+			return err
+		}
+		if neg := f.negatedName(); neg != "" {
+			line = fmt.Sprintf("complete -c %s -l %s -d %s", prog, neg, fishQuote("Turn off "+dashed(names[0])))
+			if _, err := fmt.Fprintln(w, line); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// fishPathCompletion returns the options completing the flag's value as a
+// path. Patterns like "*.yaml" become suffix filters; fish can't filter by
+// other patterns, so they complete any file.
+func fishPathCompletion(f builtFlag) string {
+	kind, globs := f.pathCompletion()
+	if kind == "dir" {
+		return " -x -a '(__fish_complete_directories (commandline -ct))'"
+	}
+	var calls []string
+	for _, glob := range globs {
+		suffix := strings.TrimPrefix(glob, "*")
+		if suffix == glob || suffix == "" || strings.TrimFunc(suffix, isSuffixRune) != "" {
+			return " -r -F"
+		}
+		calls = append(calls, "(__fish_complete_suffix "+suffix+")")
+	}
+	if len(calls) == 0 {
+		return " -r -F"
+	}
+	return " -x -a " + fishQuote(strings.Join(calls, " "))
+}
+
+// isSuffixRune reports whether r may appear in a file suffix that fish
+// completion filters by.
+func isSuffixRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r) || r == '.' || r == '-' || r == '_'
+}
+
+// hasPathCompletion reports whether the flag's values complete as paths.
+func hasPathCompletion(f builtFlag) bool {
+	kind, _ := f.pathCompletion()
+	return kind != ""
+}
+
+// fishQuote single-quotes s for fish.
+func fishQuote(s string) string {
+	return "'" + strings.NewReplacer(`\`, `\\`, `'`, `\'`).Replace(s) + "'"
+}
+
+// generatePowerShell writes a PowerShell completion script that registers an
+// argument completer for prog. Flag names are offered with their usage as
+// tooltips, and values from their choices.
+func (b *FlagBuilder) generatePowerShell(w io.Writer, prog string) error {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "Register-ArgumentCompleter -Native -CommandName %s -ScriptBlock {\n", pwshQuote(prog))
+	sb.WriteString("    param($wordToComplete, $commandAst, $cursorPosition)\n")
+	sb.WriteString("    $words = @($commandAst.CommandElements | Where-Object { $_.Extent.StartOffset -lt $cursorPosition } | ForEach-Object { $_.ToString() })\n")
+	sb.WriteString("    $prev = if ($wordToComplete) { $words[-2] } else { $words[-1] }\n")
+	sb.WriteString("    $values = $null\n")
+	sb.WriteString("    switch ($prev) {\n")
+	var flags []string
+	for _, f := range b.visibleFlags() {
+		var opts []string
+		for _, name := range f.names() {
+			// Completion tooltips can't be empty, so flags without usage show
+			// their name.
+			usage := f.flagUsage()
+			if usage == "" {
+				usage = dashed(name)
+			}
+			opts = append(opts, pwshQuote(dashed(name)))
+			flags = append(flags, fmt.Sprintf("[pscustomobject]@{ Name = %s; Usage = %s }", pwshQuote(dashed(name)), pwshQuote(usage)))
+		}
+		if !f.takesValue() {
+			continue
+		}
+		fmt.Fprintf(&sb, "        { $_ -in %s } { ", strings.Join(opts, ", "))
+		switch {
+		case f.hasDynamicChoices():
+			fmt.Fprintf(&sb, "$values = @(& %s __complete %s $wordToComplete)", pwshQuote(prog), opts[0])
+		case len(f.completions("")) > 0:
+			var choices []string
+			for _, c := range f.completions("") {
+				choices = append(choices, pwshQuote(c))
+			}
+			fmt.Fprintf(&sb, "$values = @(%s)", strings.Join(choices, ", "))
+		default:
+			sb.WriteString("return")
+		}
+		sb.WriteString(" }\n")
+	}
+	sb.WriteString("    }\n")
+	sb.WriteString("    if ($null -ne $values) {\n")
+	sb.WriteString("        $values | Where-Object { $_ -like \"$wordToComplete*\" } | ForEach-Object {\n")
+	sb.WriteString("            [System.Management.Automation.CompletionResult]::new($_, $_, 'ParameterValue', $_)\n")
+	sb.WriteString("        }\n")
+	sb.WriteString("        return\n")
+	sb.WriteString("    }\n")
+	sb.WriteString("    if ($wordToComplete -notlike '-*') { return }\n")
+	sb.WriteString("    $flags = @(\n")
+	for _, f := range flags {
+		fmt.Fprintf(&sb, "        %s\n", f)
+	}
+	sb.WriteString("    )\n")
+	sb.WriteString("    $flags | Where-Object { $_.Name -like \"$wordToComplete*\" } | ForEach-Object {\n")
+	sb.WriteString("        [System.Management.Automation.CompletionResult]::new($_.Name, $_.Name, 'ParameterName', $_.Usage)\n")
+	sb.WriteString("    }\n")
+	sb.WriteString("}\n")
+	_, err := io.WriteString(w, sb.String())
+	return err
+}
+
+// pwshQuote single-quotes s for PowerShell.
+func pwshQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", "''") + "'"
+}
@@ -0,0 +1,343 @@
+//go:build go1.18
+
+package fluentflag
+
+import (
+	"flag"
+	"strings"
+	"testing"
+)
+
+func newCompletionBuilder() *FlagBuilder {
+	b := NewFlagBuilderWithSet(flag.NewFlagSet("mytool", flag.ContinueOnError))
+	b.StringFlag("name", "Command name").Alias('n').BuildVar()
+	b.BoolFlag("verbose", "Don't be quiet").Alias('v').BuildVar()
+	b.StringFlag("format", "Output format").Choices("json", "yaml").BuildVar()
+	b.StringFlag("profile", "Profile to use").DynamicChoices(func() []string {
+		return []string{"dev", "prod", "staging"}
+	}).BuildVar()
+	return b
+}
+
+func TestGenerateCompletion_Fish(t *testing.T) {
+	var buf strings.Builder
+	if err := newCompletionBuilder().GenerateCompletion(&buf, "fish", "mytool"); err != nil {
+		t.Fatalf("GenerateCompletion failed: %v", err)
+	}
+	expected := `complete -c mytool -s n -l name -r -d 'Command name'
+complete -c mytool -s v -l verbose -d 'Don\'t be quiet'
+complete -c mytool -l format -x -a 'json yaml' -d 'Output format'
+complete -c mytool -l profile -x -a '(mytool __complete --profile (commandline -ct))' -d 'Profile to use'
+`
+	if buf.String() != expected {
+		t.Errorf("fish completion mismatch.\nGot:\n%s\nWant:\n%s", buf.String(), expected)
+	}
+}
+
+func TestGenerateCompletion_FishNegatable(t *testing.T) {
+	b := NewFlagBuilderWithSet(flag.NewFlagSet("mytool", flag.ContinueOnError))
+	b.BoolFlag("cache", "Use the cache").Alias('c').Default(true).Negatable().BuildVar()
+
+	var buf strings.Builder
+	if err := b.GenerateCompletion(&buf, "fish", "mytool"); err != nil {
+		t.Fatalf("GenerateCompletion failed: %v", err)
+	}
+	expected := `complete -c mytool -s c -l cache -d 'Use the cache'
+complete -c mytool -l no-cache -d 'Turn off --cache'
+`
+	if buf.String() != expected {
+		t.Errorf("fish completion mismatch.\nGot:\n%s\nWant:\n%s", buf.String(), expected)
+	}
+}
+
+func TestGenerateCompletion_UnsupportedShell(t *testing.T) {
+	err := newCompletionBuilder().GenerateCompletion(&strings.Builder{}, "tcsh", "mytool")
+	if err == nil {
+		t.Error("expected error for unsupported shell")
+	}
+}
+
+func TestServeCompletion(t *testing.T) {
+	tests := []struct {
+		name string
+		args []string
+		want string
+	}{
+		{"dynamic", []string{"--profile"}, "dev\nprod\nstaging\n"},
+		{"dynamic prefix", []string{"--profile", "p"}, "prod\n"},
+		{"static", []string{"--format", "y"}, "yaml\n"},
+		{"none", []string{"--name"}, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf strings.Builder
+			if err := newCompletionBuilder().ServeCompletion(tt.args, &buf); err != nil {
+				t.Fatalf("ServeCompletion failed: %v", err)
+			}
+			if buf.String() != tt.want {
+				t.Errorf("expected %q, got %q", tt.want, buf.String())
+			}
+		})
+	}
+	if err := newCompletionBuilder().ServeCompletion([]string{"--bogus"}, &strings.Builder{}); err == nil {
+		t.Error("expected error for unknown flag")
+	}
+}
+
+func TestCompleteWith(t *testing.T) {
+	b := NewFlagBuilderWithSet(flag.NewFlagSet("mytool", flag.ContinueOnError))
+	var prefixes []string
+	b.StringFlag("region", "Region").CompleteWith(func(prefix string) []string {
+		prefixes = append(prefixes, prefix)
+		return []string{prefix + "-east-1", prefix + "-west-2"}
+	}).BuildVar()
+
+	var buf strings.Builder
+	if err := b.ServeCompletion([]string{"--region", "us"}, &buf); err != nil {
+		t.Fatalf("ServeCompletion failed: %v", err)
+	}
+	if expected := "us-east-1\nus-west-2\n"; buf.String() != expected {
+		t.Errorf("expected %q, got %q", expected, buf.String())
+	}
+	if len(prefixes) != 1 || prefixes[0] != "us" {
+		t.Errorf("expected the callback to get prefix \"us\", got %q", prefixes)
+	}
+
+	var script strings.Builder
+	if err := b.GenerateCompletion(&script, "bash", "mytool"); err != nil {
+		t.Fatalf("GenerateCompletion failed: %v", err)
+	}
+	if !strings.Contains(script.String(), `mytool __complete --region "$cur"`) {
+		t.Errorf("expected the script to ask the program for candidates:\n%s", script.String())
+	}
+}
+
+func TestParse_ServesCompletion(t *testing.T) {
+	b := newCompletionBuilder()
+	b.StringFlag("host", "Host").Required().BuildVar()
+	var out strings.Builder
+	exitCode := -1
+	b.SetHelpOutput(&out)
+	b.SetExitFunc(func(code int) { exitCode = code })
+	if _, err := b.Parse([]string{"__complete", "--profile", "d"}); err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	if exitCode != 0 {
+		t.Errorf("expected exit code 0, got %d", exitCode)
+	}
+	if out.String() != "dev\n" {
+		t.Errorf("expected %q, got %q", "dev\n", out.String())
+	}
+
+	plain := NewFlagBuilderWithSet(flag.NewFlagSet("mytool", flag.ContinueOnError))
+	plain.StringFlag("format", "Output format").Choices("json").BuildVar()
+	rest, err := plain.Parse([]string{"__complete"})
+	if err != nil || len(rest) != 1 || rest[0] != "__complete" {
+		t.Errorf("expected __complete to be an argument without dynamic flags, got %q, %v", rest, err)
+	}
+}
+
+func TestCompleteFiles(t *testing.T) {
+	newBuilder := func() *FlagBuilder {
+		b := NewFlagBuilderWithSet(flag.NewFlagSet("mytool", flag.ContinueOnError))
+		b.StringFlag("config", "Config file").CompleteFiles("*.yaml", "*.yml").BuildVar()
+		b.StringFlag("log", "Log file").CompleteFiles().BuildVar()
+		b.StringFlag("dir", "Work dir").CompleteDirs().BuildVar()
+		return b
+	}
+	tests := []struct {
+		shell string
+		want  []string
+	}{
+		{"bash", []string{
+			`COMPREPLY=($(compgen -d -- "$cur") $(compgen -f -X '!*.yaml' -- "$cur") $(compgen -f -X '!*.yml' -- "$cur"))`,
+			`COMPREPLY=($(compgen -f -- "$cur"))`,
+			`COMPREPLY=($(compgen -d -- "$cur"))`,
+			"compopt -o filenames 2>/dev/null",
+		}},
+		{"zsh", []string{
+			`'--config=[Config file]:config:_files -g "*.yaml *.yml"'`,
+			`'--log=[Log file]:log:_files'`,
+			`'--dir=[Work dir]:dir:_files -/'`,
+		}},
+		{"fish", []string{
+			`complete -c mytool -l config -x -a '(__fish_complete_suffix .yaml) (__fish_complete_suffix .yml)' -d 'Config file'`,
+			`complete -c mytool -l log -r -F -d 'Log file'`,
+			`complete -c mytool -l dir -x -a '(__fish_complete_directories (commandline -ct))' -d 'Work dir'`,
+		}},
+		{"powershell", []string{
+			`{ $_ -in '--config' } { return }`,
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.shell, func(t *testing.T) {
+			var buf strings.Builder
+			if err := newBuilder().GenerateCompletion(&buf, tt.shell, "mytool"); err != nil {
+				t.Fatalf("GenerateCompletion failed: %v", err)
+			}
+			for _, want := range tt.want {
+				if !strings.Contains(buf.String(), want) {
+					t.Errorf("completion is missing %q:\n%s", want, buf.String())
+				}
+			}
+		})
+	}
+
+	b := NewFlagBuilderWithSet(flag.NewFlagSet("mytool", flag.ContinueOnError))
+	b.SetErrorHandling(ReturnErrors)
+	b.BoolFlag("verbose", "Print more").CompleteDirs().BuildVar()
+	if err := b.Err(); err == nil || !strings.Contains(err.Error(), "CompleteDirs requires a flag that takes a value (--verbose)") {
+		t.Errorf("expected an error for a bool flag, got %v", err)
+	}
+}
+
+func TestGenerateCompletion_Bash(t *testing.T) {
+	var buf strings.Builder
+	if err := newCompletionBuilder().GenerateCompletion(&buf, "bash", "mytool"); err != nil {
+		t.Fatalf("GenerateCompletion failed: %v", err)
+	}
+	expected := `_mytool_completion() {
+    local cur="${COMP_WORDS[COMP_CWORD]}" prev="${COMP_WORDS[COMP_CWORD-1]}"
+    case "$prev" in
+        --name|-n)
+            COMPREPLY=()
+            return
+            ;;
+        --format)
+            COMPREPLY=($(compgen -W 'json yaml' -- "$cur"))
+            return
+            ;;
+        --profile)
+            COMPREPLY=($(compgen -W "$(mytool __complete --profile "$cur")" -- "$cur"))
+            return
+            ;;
+    esac
+    if [[ "$cur" == -* ]]; then
+        COMPREPLY=($(compgen -W '--name -n --verbose -v --format --profile' -- "$cur"))
+    fi
+}
+complete -o default -F _mytool_completion mytool
+`
+	if buf.String() != expected {
+		t.Errorf("bash completion mismatch.\nGot:\n%s\nWant:\n%s", buf.String(), expected)
+	}
+}
+
+func TestGenerateCompletion_Zsh(t *testing.T) {
+	var buf strings.Builder
+	if err := newCompletionBuilder().GenerateCompletion(&buf, "zsh", "mytool"); err != nil {
+		t.Fatalf("GenerateCompletion failed: %v", err)
+	}
+	expected := `#compdef mytool
+
+_mytool() {
+    _arguments -s \
+        '(-n --name)-n[Command name]:name: ' \
+        '(-n --name)--name=[Command name]:name: ' \
+        '(-v --verbose)-v[Don'\''t be quiet]' \
+        '(-v --verbose)--verbose[Don'\''t be quiet]' \
+        '--format=[Output format]:format:(json yaml)' \
+        '--profile=[Profile to use]:profile:{compadd -- ${(f)"$(mytool __complete --profile "$PREFIX")"}}' \
+        '*:file:_files'
+}
+
+if [ "$funcstack[1]" = '_mytool' ]; then
+    _mytool "$@"
+else
+    compdef _mytool mytool
+fi
+`
+	if buf.String() != expected {
+		t.Errorf("zsh completion mismatch.\nGot:\n%s\nWant:\n%s", buf.String(), expected)
+	}
+}
+
+func TestGenerateCompletion_ZshLists(t *testing.T) {
+	b := NewFlagBuilderWithSet(flag.NewFlagSet("my-tool", flag.ContinueOnError))
+	b.StringFlag("tag", "Tags [key:value]").Alias('t').BuildSlice()
+	b.StringFlag("mode", "Mode").Choices("a b", "c:d").BuildVar()
+
+	var buf strings.Builder
+	if err := b.GenerateCompletion(&buf, "zsh", "my-tool"); err != nil {
+		t.Fatalf("GenerateCompletion failed: %v", err)
+	}
+	for _, want := range []string{
+		"_my_tool() {",
+		`'*-t[Tags \[key\:value\]]:tag: ' \`,
+		`'*--tag=[Tags \[key\:value\]]:tag: ' \`,
+		`'--mode=[Mode]:mode:(a\ b c\:d)' \`,
+	} {
+		if !strings.Contains(buf.String(), want) {
+			t.Errorf("zsh completion is missing %q:\n%s", want, buf.String())
+		}
+	}
+}
+
+func TestGenerateCompletion_PowerShell(t *testing.T) {
+	b := newCompletionBuilder()
+	b.IntFlag("level", "").BuildVar()
+	var buf strings.Builder
+	if err := b.GenerateCompletion(&buf, "powershell", "mytool"); err != nil {
+		t.Fatalf("GenerateCompletion failed: %v", err)
+	}
+	expected := `Register-ArgumentCompleter -Native -CommandName 'mytool' -ScriptBlock {
+    param($wordToComplete, $commandAst, $cursorPosition)
+    $words = @($commandAst.CommandElements | Where-Object { $_.Extent.StartOffset -lt $cursorPosition } | ForEach-Object { $_.ToString() })
+    $prev = if ($wordToComplete) { $words[-2] } else { $words[-1] }
+    $values = $null
+    switch ($prev) {
+        { $_ -in '--name', '-n' } { return }
+        { $_ -in '--format' } { $values = @('json', 'yaml') }
+        { $_ -in '--profile' } { $values = @(& 'mytool' __complete '--profile' $wordToComplete) }
+        { $_ -in '--level' } { return }
+    }
+    if ($null -ne $values) {
+        $values | Where-Object { $_ -like "$wordToComplete*" } | ForEach-Object {
+            [System.Management.Automation.CompletionResult]::new($_, $_, 'ParameterValue', $_)
+        }
+        return
+    }
+    if ($wordToComplete -notlike '-*') { return }
+    $flags = @(
+        [pscustomobject]@{ Name = '--name'; Usage = 'Command name' }
+        [pscustomobject]@{ Name = '-n'; Usage = 'Command name' }
+        [pscustomobject]@{ Name = '--verbose'; Usage = 'Don''t be quiet' }
+        [pscustomobject]@{ Name = '-v'; Usage = 'Don''t be quiet' }
+        [pscustomobject]@{ Name = '--format'; Usage = 'Output format' }
+        [pscustomobject]@{ Name = '--profile'; Usage = 'Profile to use' }
+        [pscustomobject]@{ Name = '--level'; Usage = '--level' }
+    )
+    $flags | Where-Object { $_.Name -like "$wordToComplete*" } | ForEach-Object {
+        [System.Management.Automation.CompletionResult]::new($_.Name, $_.Name, 'ParameterName', $_.Usage)
+    }
+}
+`
+	if buf.String() != expected {
+		t.Errorf("PowerShell completion mismatch.\nGot:\n%s\nWant:\n%s", buf.String(), expected)
+	}
+}
+
+func TestWithCompletion(t *testing.T) {
+	b := newCompletionBuilder()
+	b.WithCompletion()
+	var out strings.Builder
+	exitCode := -1
+	b.SetHelpOutput(&out)
+	b.SetExitFunc(func(code int) { exitCode = code })
+	if _, err := b.Parse([]string{"--completion=bash"}); err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	if exitCode != 0 {
+		t.Errorf("expected exit code 0, got %d", exitCode)
+	}
+	if !strings.Contains(out.String(), "complete -o default -F _mytool_completion mytool") {
+		t.Errorf("expected bash completion script, got:\n%s", out.String())
+	}
+
+	var usage strings.Builder
+	b.SetOutput(&usage)
+	b.PrintUsage()
+	if strings.Contains(usage.String(), "completion") {
+		t.Errorf("expected --completion to be hidden from usage, got:\n%s", usage.String())
+	}
+}
@@ -0,0 +1,93 @@
+// compose.go
+// Copyright (c) 2025 mattmc3
+// SPDX-License-Identifier: MIT
+// Project home: https://github.com/mattmc3/fluentflag
+
+package fluentflag
+
+// FlagSetOption configures how AddFlagSet adds another builder's flags.
+type FlagSetOption func(*flagSetOptions)
+
+// flagSetOptions holds the settings made by the options passed to AddFlagSet.
+type flagSetOptions struct {
+	prefix string
+}
+
+// WithPrefix makes AddFlagSet put prefix in front of the names of the flags it
+// adds, so --host becomes --db-host with WithPrefix("db-"). Short aliases are
+// dropped, since they can't be prefixed. So that a component added under two
+// prefixes reads separate settings, a flag's Env variable is prefixed too, in
+// environment variable form, so HOST becomes DB_HOST, and its ConfigPath is
+// dropped, so it is read from config files by its prefixed name.
+func WithPrefix(prefix string) FlagSetOption {
+	return func(opts *flagSetOptions) {
+		opts.prefix = prefix
+	}
+}
+
+// AddFlagSet defines the flags built on child on b as well, sharing their
+// storage, so reusable components like database or logging options can
+// define their flags once and be added to several programs. Their groups and
+// constraints between them come along too. Built-in flags like --help, and the
+// child's positional arguments, subcommands, and PostParse hooks, are not
+// added.
+func (b *FlagBuilder) AddFlagSet(child *FlagBuilder, opts ...FlagSetOption) {
+	var options flagSetOptions
+	for _, opt := range opts {
+		opt(&options)
+	}
+	for _, g := range child.groups {
+		if group := b.usageGroup(g.title); group.desc == "" {
+			group.desc = g.desc
+		}
+	}
+	for _, f := range child.flagsBuilt {
+		b.fail(f.addTo(b, options.prefix))
+	}
+	for _, c := range child.constraints {
+		b.constraints = append(b.constraints, flagConstraint{exclusive: c.exclusive, names: prefixNames(options.prefix, c.names)})
+	}
+}
+
+// addTo defines the flag on b too, with prefix in front of its names, bound
+// to the same storage.
+func (self *FluentFlag[T]) addTo(b *FlagBuilder, prefix string) error {
+	if self.rebuild != nil {
+		return nil
+	}
+	added := new(FluentFlag[T]) // allocate on heap
+	*added = *self
+	added.builder = b
+	if prefix != "" {
+		added.name = prefix + self.name
+		added.alias = 0
+		added.aliases = nil
+		for _, alias := range self.aliases {
+			if !isShortName(alias) {
+				added.aliases = append(added.aliases, prefix+alias)
+			}
+		}
+		added.renamedFrom = prefixNames(prefix, self.renamedFrom)
+		added.requires = prefixNames(prefix, self.requires)
+		if self.choicesFrom != "" {
+			added.choicesFrom = prefix + self.choicesFrom
+		}
+		if self.env != "" {
+			added.env = envName(prefix) + self.env
+		}
+		added.configPath = ""
+	}
+	val := self.value.(interface {
+		shareWith(f *FluentFlag[T]) fluentValue
+	}).shareWith(added)
+	return added.register(val)
+}
+
+// prefixNames returns names with prefix in front of each.
+func prefixNames(prefix string, names []string) []string {
+	var prefixed []string
+	for _, name := range names {
+		prefixed = append(prefixed, prefix+name)
+	}
+	return prefixed
+}
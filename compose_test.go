@@ -0,0 +1,160 @@
+//go:build go1.18
+
+package fluentflag
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+type dbOptions struct {
+	host  string
+	port  int
+	debug bool
+}
+
+func (o *dbOptions) flags() *FlagBuilder {
+	b := NewFlagBuilderWithSet(flag.NewFlagSet("db", flag.ContinueOnError))
+	b.EnableHelp()
+	b.StringFlag("host", "database host").Alias('H').LongAlias("server").Default("localhost").Group("Database").Build(&o.host)
+	b.IntFlag("port", "database port").Default(5432).Group("Database").Build(&o.port)
+	b.BoolFlag("debug", "log queries").Requires("host").Group("Database").Build(&o.debug)
+	b.MutuallyExclusive("host", "port")
+	return b
+}
+
+func TestAddFlagSet(t *testing.T) {
+	b := NewFlagBuilderWithSet(flag.NewFlagSet("test", flag.ContinueOnError))
+	b.BoolFlag("verbose", "more output").Alias('v').BuildVar()
+	var primary, replica dbOptions
+	b.AddFlagSet(primary.flags(), WithPrefix("db-"))
+	b.AddFlagSet(replica.flags(), WithPrefix("replica-"))
+
+	if _, err := b.Parse([]string{"--db-server=db.local", "--replica-port=6543"}); err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	if primary.host != "db.local" || primary.port != 5432 || replica.host != "localhost" || replica.port != 6543 {
+		t.Errorf("unexpected values %+v %+v", primary, replica)
+	}
+
+	want := `  -v, --verbose            more output
+
+Database:
+      --db-host, --db-server string
+                           database host (conflicts with --db-port) (default
+                           "localhost")
+      --db-port int        database port (conflicts with --db-host) (default
+                           5432)
+      --db-debug           log queries (requires --db-host)
+      --replica-host, --replica-server string
+                           database host (conflicts with --replica-port)
+                           (default "localhost")
+      --replica-port int   database port (conflicts with --replica-host)
+                           (default 5432)
+      --replica-debug      log queries (requires --replica-host)
+`
+	if got := b.UsageStringWidth(80); got != want {
+		t.Errorf("Usage output mismatch.\nGot:\n%s\nWant:\n%s", got, want)
+	}
+}
+
+func TestAddFlagSet_Constraints(t *testing.T) {
+	tests := []struct {
+		name    string
+		args    []string
+		wantErr string
+	}{
+		{"requires", []string{"--db-debug"}, "--db-host"},
+		{"exclusive", []string{"--db-host=x", "--db-port=1"}, "--db-host"},
+		{"short alias dropped", []string{"-H", "x"}, "-H"},
+		{"help not added", []string{"--db-help"}, "db-help"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fs := flag.NewFlagSet("test", flag.ContinueOnError)
+			fs.SetOutput(&strings.Builder{})
+			b := NewFlagBuilderWithSet(fs)
+			b.SetOutput(&strings.Builder{})
+			var db dbOptions
+			b.AddFlagSet(db.flags(), WithPrefix("db-"))
+			if _, err := b.Parse(tt.args); err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("expected error containing %q, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestAddFlagSet_NoPrefix(t *testing.T) {
+	b := NewFlagBuilderWithSet(flag.NewFlagSet("test", flag.ContinueOnError))
+	var db dbOptions
+	b.AddFlagSet(db.flags())
+	if _, err := b.Parse([]string{"-H", "x", "--debug"}); err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	if db.host != "x" || !db.debug {
+		t.Errorf("unexpected values %+v", db)
+	}
+}
+
+func TestAddFlagSet_DuplicatePanics(t *testing.T) {
+	b := NewFlagBuilderWithSet(flag.NewFlagSet("test", flag.ContinueOnError))
+	b.StringFlag("db-host", "host").BuildVar()
+	defer func() {
+		if r := recover(); r == nil || !strings.Contains(fmt.Sprint(r), "already defined") {
+			t.Errorf("expected a panic naming --db-host, got %v", r)
+		}
+	}()
+	var db dbOptions
+	b.AddFlagSet(db.flags(), WithPrefix("db-"))
+}
+
+func TestAddFlagSet_PrefixedChecks(t *testing.T) {
+	newChild := func(port *int) *FlagBuilder {
+		child := NewFlagBuilderWithSet(flag.NewFlagSet("db", flag.ContinueOnError))
+		child.IntFlag("port", "database port").Max(10).Build(port)
+		return child
+	}
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	fs.SetOutput(&strings.Builder{})
+	b := NewFlagBuilderWithSet(fs)
+	b.SetOutput(&strings.Builder{})
+	var primary, replica int
+	b.AddFlagSet(newChild(&primary), WithPrefix("db-"))
+	b.AddFlagSet(newChild(&replica), WithPrefix("replica-"))
+	_, err := b.Parse([]string{"--replica-port=50"})
+	if want := "--replica-port must be at most 10, got 50"; err == nil || !strings.Contains(err.Error(), want) {
+		t.Errorf("expected error containing %q, got %v", want, err)
+	}
+	var fe *FlagError
+	if !errors.As(err, &fe) || fe.Flag != "replica-port" {
+		t.Errorf("expected a FlagError for --replica-port, got %#v", err)
+	}
+}
+
+func TestAddFlagSet_PrefixedEnv(t *testing.T) {
+	t.Setenv("ZZ_HOST", "envhost")
+	t.Setenv("REPLICA_ZZ_HOST", "replicahost")
+	newChild := func(host *string) *FlagBuilder {
+		child := NewFlagBuilderWithSet(flag.NewFlagSet("db", flag.ContinueOnError))
+		child.StringFlag("host", "database host").Env("ZZ_HOST").ConfigPath("database.host").Build(host)
+		return child
+	}
+	b := NewFlagBuilderWithSet(flag.NewFlagSet("test", flag.ContinueOnError))
+	var primary, replica string
+	b.AddFlagSet(newChild(&primary), WithPrefix("db-"))
+	b.AddFlagSet(newChild(&replica), WithPrefix("replica-"))
+	if _, err := b.Parse(nil); err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	if primary != "" || replica != "replicahost" {
+		t.Errorf("expected db-host unset and replica-host from REPLICA_ZZ_HOST, got %q and %q", primary, replica)
+	}
+	for _, name := range []string{"db-host", "replica-host"} {
+		if key := b.lookup(name).configKey(); key != name {
+			t.Errorf("expected --%s to use config key %q, got %q", name, name, key)
+		}
+	}
+}
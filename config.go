@@ -0,0 +1,281 @@
+// config.go
+// Copyright (c) 2025 mattmc3
+// SPDX-License-Identifier: MIT
+// Project home: https://github.com/mattmc3/fluentflag
+
+package fluentflag
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// ConfigPath sets the dotted path, like "server.port", of the flag's value in
+// a config file. By default a flag reads the top-level key matching its name.
+func (self *FluentFlag[T]) ConfigPath(path string) *FluentFlag[T] {
+	self.configPath = path
+	return self
+}
+
+// configKey returns the dotted path of the flag's value in a config file.
+func (self *FluentFlag[T]) configKey() string {
+	if self.configPath != "" {
+		return self.configPath
+	}
+	return self.name
+}
+
+// ConfigDecoder decodes config data into v, which points to a map[string]any.
+// Its signature matches json.Unmarshal and the Unmarshal functions of common
+// YAML and TOML packages, so they can be plugged in with SetConfigDecoder.
+type ConfigDecoder func(data []byte, v any) error
+
+// defaultDecoders are the config formats supported out of the box. The YAML and
+// TOML decoders handle the common subset of those formats used in config files:
+// nested tables or mappings, scalars, and lists of scalars.
+var defaultDecoders = map[string]ConfigDecoder{
+	"json": decodeJSON,
+	"yaml": decodeYAML,
+	"toml": decodeTOML,
+}
+
+// SetConfigDecoder sets the decoder used for config files of format, like
+// "yaml", replacing the built-in one or adding a new format.
+func (b *FlagBuilder) SetConfigDecoder(format string, decode ConfigDecoder) {
+	if b.decoders == nil {
+		b.decoders = map[string]ConfigDecoder{}
+	}
+	b.decoders[strings.ToLower(format)] = decode
+}
+
+// ConfigFile loads a config file whose values are used by Parse for flags not
+// set on the command line. The format is taken from the file extension: .json,
+// .yaml or .yml, .toml, or any format added with SetConfigDecoder.
+func (b *FlagBuilder) ConfigFile(path string) error {
+	format := strings.TrimPrefix(strings.ToLower(filepath.Ext(path)), ".")
+	if format == "yml" {
+		format = "yaml"
+	}
+	decode := b.decoder(format)
+	if decode == nil {
+		return fmt.Errorf("fluentflag: unsupported config file format %q", "."+format)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	if err := b.loadConfig(data, decode); err != nil {
+		return fmt.Errorf("fluentflag: config file %s: %w", path, err)
+	}
+	return nil
+}
+
+// ConfigReader is like ConfigFile, but reads the config from r in the given
+// format, like "json".
+func (b *FlagBuilder) ConfigReader(r io.Reader, format string) error {
+	decode := b.decoder(strings.ToLower(format))
+	if decode == nil {
+		return fmt.Errorf("fluentflag: unsupported config format %q", format)
+	}
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return err
+	}
+	if err := b.loadConfig(data, decode); err != nil {
+		return fmt.Errorf("fluentflag: %s config: %w", format, err)
+	}
+	return nil
+}
+
+// decoder returns the decoder for format, or nil if there is none.
+func (b *FlagBuilder) decoder(format string) ConfigDecoder {
+	if decode, ok := b.decoders[format]; ok {
+		return decode
+	}
+	return defaultDecoders[format]
+}
+
+// loadConfig decodes data and keeps the result for Parse.
+func (b *FlagBuilder) loadConfig(data []byte, decode ConfigDecoder) error {
+	var config map[string]any
+	if err := decode(data, &config); err != nil {
+		return err
+	}
+	b.config = config
+	return nil
+}
+
+// decodeJSON decodes JSON, keeping numbers as written.
+func decodeJSON(data []byte, v any) error {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	return dec.Decode(v)
+}
+
+// applyConfig sets flags that weren't set on the command line from the loaded
+// config file.
+func (b *FlagBuilder) applyConfig() error {
+	for _, f := range b.flagsBuilt {
+		if b.isSet(f) {
+			continue
+		}
+		val, ok := lookupPath(b.config, f.configKey())
+		if !ok || val == nil {
+			continue
+		}
+		for _, s := range configStrings(val) {
+			if err := f.set(s); err != nil {
+				return &FlagError{Kind: kindOf(err), Flag: f.names()[0], Value: s, Err: err,
+					msg: fmt.Sprintf("config value %q for flag --%s: %v", s, f.names()[0], err)}
+			}
+		}
+		b.sources[f.names()[0]] = SourceConfig
+	}
+	return nil
+}
+
+// lookupPath finds the value at a dotted path like "server.port" in nested maps.
+func lookupPath(config map[string]any, path string) (any, bool) {
+	var val any = config
+	for _, key := range strings.Split(path, ".") {
+		var ok bool
+		switch m := val.(type) {
+		case map[string]any:
+			val, ok = m[key]
+		case map[any]any: // as decoded by some YAML packages
+			val, ok = m[key]
+		}
+		if !ok {
+			return nil, false
+		}
+	}
+	return val, true
+}
+
+// configStrings converts a decoded config value to the strings a flag is set
+// from: one per list element, one key=value pair per map entry, or the value
+// itself.
+func configStrings(val any) []string {
+	switch v := val.(type) {
+	case []any:
+		var strs []string
+		for _, item := range v {
+			strs = append(strs, configString(item))
+		}
+		return strs
+	case map[string]any:
+		var strs []string
+		for key, item := range v {
+			strs = append(strs, key+"="+configString(item))
+		}
+		sort.Strings(strs)
+		return strs
+	case map[any]any:
+		var strs []string
+		for key, item := range v {
+			strs = append(strs, fmt.Sprint(key)+"="+configString(item))
+		}
+		sort.Strings(strs)
+		return strs
+	default:
+		return []string{configString(v)}
+	}
+}
+
+// configString formats a decoded scalar. Floats are written out in full, since
+// decoders like json.Unmarshal turn integers into floats and 1e+06 wouldn't
+// parse as an int flag.
+func configString(val any) string {
+	if f, ok := val.(float64); ok {
+		return strconv.FormatFloat(f, 'f', -1, 64)
+	}
+	return fmt.Sprint(val)
+}
+
+// unquoteScalar returns s without its quotes if it is a double-quoted string,
+// with Go escapes, or a single-quoted string. Other values are returned as is.
+func unquoteScalar(s string) (string, error) {
+	if len(s) >= 2 && s[0] == '"' && s[len(s)-1] == '"' {
+		return strconv.Unquote(s)
+	}
+	if len(s) >= 2 && s[0] == '\'' && s[len(s)-1] == '\'' {
+		return s[1 : len(s)-1], nil
+	}
+	return s, nil
+}
+
+// splitInlineList splits the items of an inline list like `[a, "b, c"]`,
+// respecting quotes. Nested lists aren't supported.
+func splitInlineList(s string) ([]any, error) {
+	inner := strings.TrimSpace(s[1 : len(s)-1])
+	if inner == "" {
+		return []any{}, nil
+	}
+	var items []any
+	var quote byte
+	start := 0
+	for i := 0; i <= len(inner); i++ {
+		if i < len(inner) {
+			c := inner[i]
+			switch {
+			case quote != 0:
+				if c == '\\' && quote == '"' {
+					i++
+				} else if c == quote {
+					quote = 0
+				}
+				continue
+			case c == '"' || c == '\'':
+				quote = c
+				continue
+			case c == '[' || c == '{':
+				return nil, fmt.Errorf("nested lists and tables are not supported: %s", s)
+			case c != ',':
+				continue
+			}
+		}
+		item := strings.TrimSpace(inner[start:i])
+		start = i + 1
+		if item == "" && i == len(inner) {
+			break // trailing comma
+		}
+		val, err := unquoteScalar(item)
+		if err != nil {
+			return nil, fmt.Errorf("invalid string %s", item)
+		}
+		items = append(items, val)
+	}
+	if quote != 0 {
+		return nil, fmt.Errorf("unterminated string in %s", s)
+	}
+	return items, nil
+}
+
+// stripComment removes a trailing # comment from a config line, ignoring #
+// characters inside quotes.
+func stripComment(line string) string {
+	var quote byte
+	for i := 0; i < len(line); i++ {
+		c := line[i]
+		switch {
+		case quote != 0:
+			if c == '\\' && quote == '"' {
+				i++
+			} else if c == quote {
+				quote = 0
+			}
+		case c == '"' || c == '\'':
+			quote = c
+		case c == '#' && (i == 0 || line[i-1] == ' ' || line[i-1] == '\t'):
+			return line[:i]
+		}
+	}
+	return line
+}
@@ -0,0 +1,148 @@
+//go:build go1.18
+
+package fluentflag
+
+import (
+	"flag"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// writeConfig writes content to a config file named name in a temp directory.
+func writeConfig(t *testing.T, name, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestConfigPath(t *testing.T) {
+	path := writeConfig(t, "config.json", `{
+		"server": {"port": 9090, "tags": ["a", "b"]},
+		"verbose": true
+	}`)
+	tests := []struct {
+		name string
+		args []string
+		want int
+	}{
+		{"from config", []string{}, 9090},
+		{"command line wins", []string{"--port=7070"}, 7070},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b := NewFlagBuilderWithSet(flag.NewFlagSet("test", flag.ContinueOnError))
+			port := b.IntFlag("port", "listen port").Default(8080).ConfigPath("server.port").BuildVar()
+			tags := b.StringFlag("tag", "tags").ConfigPath("server.tags").BuildSlice()
+			verbose := b.BoolFlag("verbose", "verbose output").BuildVar()
+			if err := b.ConfigFile(path); err != nil {
+				t.Fatal(err)
+			}
+			if _, err := b.Parse(tt.args); err != nil {
+				t.Fatal(err)
+			}
+			if *port != tt.want {
+				t.Errorf("expected port %d, got %d", tt.want, *port)
+			}
+			if strings.Join(*tags, ",") != "a,b" {
+				t.Errorf("expected tags a,b, got %v", *tags)
+			}
+			if !*verbose {
+				t.Error("expected verbose from the top-level key")
+			}
+		})
+	}
+}
+
+func TestConfigFile_InvalidValue(t *testing.T) {
+	path := writeConfig(t, "config.json", `{"server": {"port": "high"}}`)
+	b := NewFlagBuilderWithSet(flag.NewFlagSet("test", flag.ContinueOnError))
+	b.IntFlag("port", "listen port").ConfigPath("server.port").BuildVar()
+	if err := b.ConfigFile(path); err != nil {
+		t.Fatal(err)
+	}
+	_, err := b.Parse([]string{})
+	if err == nil || !strings.Contains(err.Error(), `config value "high" for flag --port`) {
+		t.Errorf("expected config value error, got %v", err)
+	}
+}
+
+func TestConfigFile_UnsupportedFormat(t *testing.T) {
+	path := writeConfig(t, "config.ini", "port=1")
+	b := NewFlagBuilderWithSet(flag.NewFlagSet("test", flag.ContinueOnError))
+	err := b.ConfigFile(path)
+	if err == nil || !strings.Contains(err.Error(), `unsupported config file format ".ini"`) {
+		t.Errorf("expected format error, got %v", err)
+	}
+}
+
+func TestConfigFile_Formats(t *testing.T) {
+	tests := []struct {
+		file    string
+		content string
+	}{
+		{"config.json", `{"server": {"port": 9090, "hosts": ["a", "b"]}, "name": "api", "ratio": 1000000}`},
+		{"config.yaml", "# service config\nname: api\nratio: 1000000\nserver:\n  port: 9090\n  hosts:\n    - a\n    - \"b\"\n"},
+		{"config.yml", "name: 'api'\nratio: 1000000\nserver:\n  port: 9090 # default is 8080\n  hosts: [a, b]\n"},
+		{"config.toml", "name = \"api\"\nratio = 1000000\n\n[server]\nport = 9090\nhosts = [\n  \"a\",\n  \"b\",\n]\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.file, func(t *testing.T) {
+			b := NewFlagBuilderWithSet(flag.NewFlagSet("test", flag.ContinueOnError))
+			name := b.StringFlag("name", "service name").BuildVar()
+			ratio := b.IntFlag("ratio", "sample ratio").BuildVar()
+			port := b.IntFlag("port", "listen port").ConfigPath("server.port").BuildVar()
+			hosts := b.StringFlag("host", "hosts").ConfigPath("server.hosts").BuildSlice()
+			if err := b.ConfigFile(writeConfig(t, tt.file, tt.content)); err != nil {
+				t.Fatal(err)
+			}
+			if _, err := b.Parse([]string{"--name=web"}); err != nil {
+				t.Fatal(err)
+			}
+			if *name != "web" || *ratio != 1000000 || *port != 9090 || strings.Join(*hosts, ",") != "a,b" {
+				t.Errorf("unexpected values name=%q ratio=%d port=%d hosts=%v", *name, *ratio, *port, *hosts)
+			}
+		})
+	}
+}
+
+func TestConfigReader(t *testing.T) {
+	b := NewFlagBuilderWithSet(flag.NewFlagSet("test", flag.ContinueOnError))
+	port := b.IntFlag("port", "listen port").BuildVar()
+	if err := b.ConfigReader(strings.NewReader("port = 9090\n"), "TOML"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := b.Parse([]string{}); err != nil {
+		t.Fatal(err)
+	}
+	if *port != 9090 {
+		t.Errorf("expected 9090, got %d", *port)
+	}
+	err := b.ConfigReader(strings.NewReader("port: [9090"), "yaml")
+	if err == nil || !strings.Contains(err.Error(), "fluentflag: yaml config: line 1: unterminated list") {
+		t.Errorf("expected yaml error, got %v", err)
+	}
+}
+
+func TestSetConfigDecoder(t *testing.T) {
+	b := NewFlagBuilderWithSet(flag.NewFlagSet("test", flag.ContinueOnError))
+	port := b.IntFlag("port", "listen port").BuildVar()
+	b.SetConfigDecoder("ini", func(data []byte, v any) error {
+		key, val, _ := strings.Cut(strings.TrimSpace(string(data)), "=")
+		*v.(*map[string]any) = map[string]any{key: val}
+		return nil
+	})
+	if err := b.ConfigFile(writeConfig(t, "config.ini", "port=9090")); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := b.Parse([]string{}); err != nil {
+		t.Fatal(err)
+	}
+	if *port != 9090 {
+		t.Errorf("expected 9090, got %d", *port)
+	}
+}
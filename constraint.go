@@ -0,0 +1,90 @@
+// constraint.go
+// Copyright (c) 2025 mattmc3
+// SPDX-License-Identifier: MIT
+// Project home: https://github.com/mattmc3/fluentflag
+
+package fluentflag
+
+import (
+	"fmt"
+	"strings"
+)
+
+// flagConstraint is a constraint across several flags, checked by Validate.
+type flagConstraint struct {
+	exclusive bool     // at most one may be set; otherwise all or none
+	names     []string // the flags' long names
+}
+
+// MutuallyExclusive makes Validate fail if more than one of the named flags is
+// set, like --json and --yaml. Each flag's usage names the others.
+func (b *FlagBuilder) MutuallyExclusive(names ...string) {
+	b.constraints = append(b.constraints, flagConstraint{exclusive: true, names: names})
+}
+
+// RequiredTogether makes Validate fail if some but not all of the named flags
+// are set, like --user and --password. Each flag's usage names the others.
+func (b *FlagBuilder) RequiredTogether(names ...string) {
+	b.constraints = append(b.constraints, flagConstraint{names: names})
+}
+
+// Requires makes Validate fail if this flag is set without each of the named
+// flags, like --tls-cert without --tls-key.
+func (self *FluentFlag[T]) Requires(names ...string) *FluentFlag[T] {
+	self.requires = append(self.requires, names...)
+	return self
+}
+
+// validateConstraints returns an error for each violated flag constraint.
+func (b *FlagBuilder) validateConstraints() []error {
+	var errs []error
+	for _, c := range b.constraints {
+		var set, missing []string
+		for _, name := range c.names {
+			f := b.lookup(name)
+			if f == nil {
+				errs = append(errs, fmt.Errorf("fluentflag: constraint on unknown flag --%s", name))
+				continue
+			}
+			if b.isSet(f) {
+				set = append(set, name)
+			} else {
+				missing = append(missing, name)
+			}
+		}
+		if c.exclusive && len(set) > 1 {
+			errs = append(errs, newFlagError(ErrConstraintViolated, set[1], "", "only one of %s may be given, got --%s", flagList(c.names), strings.Join(set, " and --")))
+		} else if !c.exclusive && len(set) > 0 && len(missing) > 0 {
+			errs = append(errs, newFlagError(ErrConstraintViolated, missing[0], "", "%s must be given together, missing --%s", flagList(c.names), strings.Join(missing, " and --")))
+		}
+	}
+	return errs
+}
+
+// constraintNotes returns the usage notes for the constraints on the named
+// flag, like "conflicts with --yaml".
+func (b *FlagBuilder) constraintNotes(name string) []string {
+	var notes []string
+	for _, c := range b.constraints {
+		if !containsString(c.names, name) {
+			continue
+		}
+		var others []string
+		for _, other := range c.names {
+			if other != name {
+				others = append(others, other)
+			}
+		}
+		if c.exclusive {
+			notes = append(notes, "conflicts with "+flagList(others))
+		} else {
+			notes = append(notes, "must be used with "+flagList(others))
+		}
+	}
+	return notes
+}
+
+// flagList formats flag names as "--a, --b".
+func flagList(names []string) string {
+	return "--" + strings.Join(names, ", --")
+}
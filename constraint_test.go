@@ -0,0 +1,104 @@
+//go:build go1.18
+
+package fluentflag
+
+import (
+	"flag"
+	"strings"
+	"testing"
+)
+
+func TestMutuallyExclusiveAndRequiredTogether(t *testing.T) {
+	tests := []struct {
+		name    string
+		args    []string
+		wantErr string
+	}{
+		{"none", []string{}, ""},
+		{"one format", []string{"--json"}, ""},
+		{"two formats", []string{"--json", "--yaml"}, "only one of --json, --yaml, --table may be given, got --json and --yaml"},
+		{"credentials", []string{"--user=a", "--password=b"}, ""},
+		{"user only", []string{"--user=a"}, "--user, --password must be given together, missing --password"},
+		{"both violated", []string{"--password=b", "--json", "--table"}, "only one of --json, --yaml, --table may be given, got --json and --table\n--user, --password must be given together, missing --user"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b := NewFlagBuilderWithSet(flag.NewFlagSet("test", flag.ContinueOnError))
+			b.BoolFlag("json", "JSON output").BuildVar()
+			b.BoolFlag("yaml", "YAML output").BuildVar()
+			b.BoolFlag("table", "table output").BuildVar()
+			b.StringFlag("user", "user name").BuildVar()
+			b.StringFlag("password", "password").BuildVar()
+			b.MutuallyExclusive("json", "yaml", "table")
+			b.RequiredTogether("user", "password")
+			_, err := b.Parse(tt.args)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("unexpected error: %v", err)
+				}
+			} else if err == nil || err.Error() != tt.wantErr {
+				t.Errorf("expected error %q, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestConstraints_Usage(t *testing.T) {
+	b := NewFlagBuilderWithSet(flag.NewFlagSet("test", flag.ContinueOnError))
+	json := b.BoolFlag("json", "JSON output")
+	json.BuildVar()
+	user := b.StringFlag("user", "user name")
+	user.BuildVar()
+	b.BoolFlag("yaml", "YAML output").BuildVar()
+	b.StringFlag("password", "password").BuildVar()
+	b.MutuallyExclusive("json", "yaml")
+	b.RequiredTogether("user", "password")
+	if got := json.Usage(); !strings.HasSuffix(got, "JSON output (conflicts with --yaml)") {
+		t.Errorf("unexpected usage %q", got)
+	}
+	if got := user.Usage(); !strings.HasSuffix(got, "user name (must be used with --password)") {
+		t.Errorf("unexpected usage %q", got)
+	}
+}
+
+func TestConstraints_UnknownFlag(t *testing.T) {
+	b := NewFlagBuilderWithSet(flag.NewFlagSet("test", flag.ContinueOnError))
+	b.MutuallyExclusive("json", "yaml")
+	if err := b.Validate(); err == nil || !strings.Contains(err.Error(), "unknown flag --json") {
+		t.Errorf("expected unknown flag error, got %v", err)
+	}
+}
+
+func TestRequires(t *testing.T) {
+	tests := []struct {
+		name    string
+		args    []string
+		wantErr string
+	}{
+		{"neither", []string{}, ""},
+		{"both", []string{"--tls-cert=a.pem", "--tls-key=a.key"}, ""},
+		{"key only", []string{"--tls-key=a.key"}, ""},
+		{"cert only", []string{"--tls-cert=a.pem"}, "--tls-cert requires --tls-key"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b := NewFlagBuilderWithSet(flag.NewFlagSet("test", flag.ContinueOnError))
+			b.StringFlag("tls-cert", "certificate file").Requires("tls-key").BuildVar()
+			b.StringFlag("tls-key", "key file").BuildVar()
+			_, err := b.Parse(tt.args)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("unexpected error: %v", err)
+				}
+			} else if err == nil || err.Error() != tt.wantErr {
+				t.Errorf("expected error %q, got %v", tt.wantErr, err)
+			}
+		})
+	}
+
+	b := NewFlagBuilderWithSet(flag.NewFlagSet("test", flag.ContinueOnError))
+	cert := b.StringFlag("tls-cert", "certificate file").Requires("tls-key")
+	if got := cert.Usage(); !strings.HasSuffix(got, "certificate file (requires --tls-key)") {
+		t.Errorf("unexpected usage %q", got)
+	}
+}
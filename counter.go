@@ -0,0 +1,87 @@
+// counter.go
+// Copyright (c) 2025 mattmc3
+// SPDX-License-Identifier: MIT
+// Project home: https://github.com/mattmc3/fluentflag
+
+package fluentflag
+
+import (
+	"fmt"
+	"strconv"
+)
+
+// counterValue implements flag.Value for counting how often a flag is given.
+type counterValue struct {
+	target *int
+}
+
+// String returns the count.
+func (self *counterValue) String() string {
+	if self.target == nil {
+		return "0"
+	}
+	return strconv.Itoa(*self.target)
+}
+
+// Set increments the count, or resets it for a false value like --verbose=false.
+func (self *counterValue) Set(val string) error {
+	on, err := strconv.ParseBool(val)
+	if err != nil {
+		return err
+	}
+	if on {
+		*self.target++
+	} else {
+		*self.target = 0
+	}
+	return nil
+}
+
+// Get returns the count.
+func (self *counterValue) Get() any {
+	return *self.target
+}
+
+// IsBoolFlag lets the flag package accept counter flags without a value.
+func (self *counterValue) IsBoolFlag() bool {
+	return true
+}
+
+// list returns the count formatted as a one-element list.
+func (self *counterValue) list() []string {
+	return []string{self.String()}
+}
+
+// setZero resets the count.
+func (self *counterValue) setZero() {
+	*self.target = 0
+}
+
+// cloneFor returns a new count for the clone f of its flag.
+func (self *counterValue) cloneFor(f *FluentFlag[bool]) fluentValue {
+	return &counterValue{target: new(int)}
+}
+
+// shareWith returns a value for f, a copy of its flag under another name,
+// bound to the same count.
+func (self *counterValue) shareWith(f *FluentFlag[bool]) fluentValue {
+	return &counterValue{target: self.target}
+}
+
+// goType returns the Go type of the count.
+func (self *counterValue) goType() string {
+	return "int"
+}
+
+// BuildCounter registers a bool flag that counts how many times it is given,
+// so -v -v -v or -vvv yields 3. This is the usual idiom for verbosity levels.
+func (self *FluentFlag[T]) BuildCounter() *int {
+	if _, ok := any(self.defaultVal).(bool); !ok {
+		self.builder.building = nil
+		self.builder.fail(fmt.Errorf("fluentflag: BuildCounter requires a bool flag (--%s)", self.name))
+		return new(int)
+	}
+	count := new(int) // allocate on heap
+	self.builder.fail(self.register(&counterValue{target: count}))
+	return count
+}
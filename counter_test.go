@@ -0,0 +1,51 @@
+//go:build go1.18
+
+package fluentflag
+
+import (
+	"flag"
+	"reflect"
+	"testing"
+)
+
+func TestBuildCounter(t *testing.T) {
+	tests := []struct {
+		name     string
+		args     []string
+		want     int
+		wantRest []string
+	}{
+		{"none", []string{}, 0, []string{}},
+		{"repeated", []string{"-v", "-v", "--verbose"}, 3, []string{}},
+		{"cluster", []string{"-vvv", "file"}, 3, []string{"file"}},
+		{"cluster and single", []string{"-vv", "-v"}, 3, []string{}},
+		{"reset", []string{"-vv", "--verbose=false", "-v"}, 1, []string{}},
+		{"after terminator", []string{"--", "-vvv"}, 0, []string{"-vvv"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b := NewFlagBuilderWithSet(flag.NewFlagSet("test", flag.ContinueOnError))
+			verbose := b.BoolFlag("verbose", "more output").Alias('v').BuildCounter()
+			rest, err := b.Parse(tt.args)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if *verbose != tt.want {
+				t.Errorf("expected %d, got %d", tt.want, *verbose)
+			}
+			if !reflect.DeepEqual(rest, tt.wantRest) {
+				t.Errorf("expected rest %v, got %v", tt.wantRest, rest)
+			}
+		})
+	}
+}
+
+func TestBuildCounter_NonBoolPanics(t *testing.T) {
+	defer func() {
+		if r := recover(); r == nil {
+			t.Error("expected panic for a non-bool counter")
+		}
+	}()
+	b := NewFlagBuilderWithSet(flag.NewFlagSet("test", flag.ContinueOnError))
+	b.IntFlag("verbose", "more output").BuildCounter()
+}
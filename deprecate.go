@@ -0,0 +1,61 @@
+// deprecate.go
+// Copyright (c) 2025 mattmc3
+// SPDX-License-Identifier: MIT
+// Project home: https://github.com/mattmc3/fluentflag
+
+package fluentflag
+
+import (
+	"flag"
+	"fmt"
+)
+
+// Deprecated marks the flag as deprecated. It keeps working, but using it on
+// the command line prints a warning with the message to the output writer,
+// and its usage notes the message.
+//
+//	b.StringFlag("out", "output file").Deprecated("use --output instead")
+func (self *FluentFlag[T]) Deprecated(msg string) *FluentFlag[T] {
+	self.deprecated = msg
+	return self
+}
+
+// deprecation returns the flag's deprecation message, if any.
+func (self *FluentFlag[T]) deprecation() string {
+	return self.deprecated
+}
+
+// RenamedFrom registers old as a former name of the flag. It keeps working,
+// but the first time it is used a warning names the new spelling.
+func (self *FluentFlag[T]) RenamedFrom(old string) *FluentFlag[T] {
+	self.renamedFrom = append(self.renamedFrom, old)
+	return self
+}
+
+// oldNames returns the flag's former names.
+func (self *FluentFlag[T]) oldNames() []string {
+	return self.renamedFrom
+}
+
+// warnDeprecated prints a warning for each deprecated flag given on the
+// command line, and for each former flag name the first time it is used.
+func (b *FlagBuilder) warnDeprecated() {
+	for _, f := range b.flagsBuilt {
+		used := false
+		b.flagSet.Visit(func(fl *flag.Flag) {
+			if hasName(f, fl.Name) {
+				used = true
+			}
+			if containsString(f.oldNames(), fl.Name) && !b.warnedRenames[fl.Name] {
+				if b.warnedRenames == nil {
+					b.warnedRenames = map[string]bool{}
+				}
+				b.warnedRenames[fl.Name] = true
+				fmt.Fprintf(b.outputWriter(), "warning: --%s has been renamed to --%s\n", fl.Name, f.names()[0])
+			}
+		})
+		if msg := f.deprecation(); msg != "" && used {
+			fmt.Fprintf(b.outputWriter(), "warning: --%s is deprecated: %s\n", f.names()[0], msg)
+		}
+	}
+}
@@ -0,0 +1,79 @@
+//go:build go1.18
+
+package fluentflag
+
+import (
+	"flag"
+	"strings"
+	"testing"
+)
+
+func TestDeprecated(t *testing.T) {
+	tests := []struct {
+		name        string
+		args        []string
+		wantOut     string
+		wantWarning bool
+	}{
+		{"not used", []string{"--output=a.txt"}, "a.txt", false},
+		{"used", []string{"--out=b.txt"}, "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b := NewFlagBuilderWithSet(flag.NewFlagSet("test", flag.ContinueOnError))
+			var buf strings.Builder
+			b.SetOutput(&buf)
+			out := b.StringFlag("out", "output file").Deprecated("use --output instead").BuildVar()
+			output := b.StringFlag("output", "output file").BuildVar()
+			if _, err := b.Parse(tt.args); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if *output != tt.wantOut {
+				t.Errorf("expected --output %q, got %q", tt.wantOut, *output)
+			}
+			if tt.wantWarning && *out != "b.txt" {
+				t.Errorf("expected deprecated flag to still work, got %q", *out)
+			}
+			warning := "warning: --out is deprecated: use --output instead\n"
+			if got := buf.String() == warning; got != tt.wantWarning {
+				t.Errorf("unexpected output %q", buf.String())
+			}
+		})
+	}
+}
+
+func TestDeprecated_Usage(t *testing.T) {
+	b := NewFlagBuilderWithSet(flag.NewFlagSet("test", flag.ContinueOnError))
+	f := b.StringFlag("out", "output file").Deprecated("use --output instead")
+	if got := f.Usage(); !strings.HasSuffix(got, "output file (deprecated: use --output instead)") {
+		t.Errorf("unexpected usage %q", got)
+	}
+}
+
+func TestRenamedFrom(t *testing.T) {
+	b := NewFlagBuilderWithSet(flag.NewFlagSet("test", flag.ContinueOnError))
+	var buf strings.Builder
+	b.SetOutput(&buf)
+	dir := b.StringFlag("output-dir", "output directory").RenamedFrom("outdir").BuildVar()
+
+	if _, err := b.Parse([]string{"--outdir=build"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if *dir != "build" {
+		t.Errorf("expected --output-dir %q, got %q", "build", *dir)
+	}
+	if want := "warning: --outdir has been renamed to --output-dir\n"; buf.String() != want {
+		t.Errorf("expected warning %q, got %q", want, buf.String())
+	}
+	if !b.isSet(b.lookup("output-dir")) {
+		t.Error("expected --output-dir to count as set")
+	}
+
+	buf.Reset()
+	if _, err := b.Parse([]string{"--outdir=dist"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if buf.String() != "" {
+		t.Errorf("expected a single warning, got %q", buf.String())
+	}
+}
@@ -0,0 +1,251 @@
+// man.go
+// Copyright (c) 2025 mattmc3
+// SPDX-License-Identifier: MIT
+// Project home: https://github.com/mattmc3/fluentflag
+
+// Package doc generates documentation, such as man pages, from the flags
+// defined with a fluentflag.FlagBuilder.
+package doc
+
+import (
+	"bytes"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/mattmc3/fluentflag"
+)
+
+// ManOptions sets the title line of a generated man page.
+type ManOptions struct {
+	Name    string    // command name; defaults to the base name of the flag set
+	Section string    // manual section; defaults to "1"
+	Date    time.Time // date of the page; left out if zero
+	Source  string    // source of the command, like "mytool 1.4.2"
+	Manual  string    // title of the manual, like "User Commands"
+}
+
+// GenerateMan returns a man page in roff format for the command defined by b,
+// with sections for its synopsis, flags and their defaults, arguments,
+// commands, environment variables, and examples. The description is the text
+// set with UsageHeader, and the text set with UsageFooter is printed under
+// NOTES. It returns the builder's misconfiguration errors, if any.
+func GenerateMan(b *fluentflag.FlagBuilder, opts ManOptions) ([]byte, error) {
+	if err := b.Err(); err != nil {
+		return nil, err
+	}
+	data := b.UsageData()
+	name := opts.Name
+	if name == "" {
+		name = filepath.Base(data.Name)
+	}
+	section := opts.Section
+	if section == "" {
+		section = "1"
+	}
+	date := ""
+	if !opts.Date.IsZero() {
+		date = opts.Date.Format("2006-01-02")
+	}
+
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, ".TH %s %s %s %s %s\n", quote(strings.ToUpper(name)), quote(section),
+		quote(date), quote(opts.Source), quote(opts.Manual))
+
+	buf.WriteString(".SH NAME\n")
+	if data.Header != "" {
+		fmt.Fprintf(&buf, "%s \\- %s\n", escape(name), escape(firstLine(data.Header)))
+	} else {
+		buf.WriteString(escape(name) + "\n")
+	}
+
+	buf.WriteString(".SH SYNOPSIS\n")
+	cmd := filepath.Base(data.Name)
+	if strings.HasPrefix(data.Synopsis, data.Name) {
+		cmd = data.Name
+	}
+	rest := strings.TrimPrefix(data.Synopsis, cmd)
+	if opts.Name != "" {
+		cmd = opts.Name
+	}
+	fmt.Fprintf(&buf, "\\fB%s\\fR%s\n", escape(cmd), escape(rest))
+
+	if data.Header != "" {
+		buf.WriteString(".SH DESCRIPTION\n")
+		writeParagraphs(&buf, data.Header)
+	}
+
+	if len(data.Flags) > 0 {
+		buf.WriteString(".SH OPTIONS\n")
+		for _, f := range data.Flags {
+			if f.Group == "" {
+				writeFlag(&buf, f)
+			}
+		}
+		for _, g := range data.Groups {
+			fmt.Fprintf(&buf, ".SS %s\n", escape(g.Title))
+			if g.Description != "" {
+				writeParagraphs(&buf, g.Description)
+			}
+			for _, f := range g.Flags {
+				writeFlag(&buf, f)
+			}
+		}
+	}
+
+	if len(data.Arguments) > 0 {
+		buf.WriteString(".SH ARGUMENTS\n")
+		for _, a := range data.Arguments {
+			arg := a.Name
+			if a.Variadic {
+				arg += "..."
+			}
+			fmt.Fprintf(&buf, ".TP\n\\fI%s\\fR\n", escape(arg))
+			writeText(&buf, a.Usage)
+			if a.Required {
+				buf.WriteString(".br\nRequired.\n")
+			} else if a.Default != "" {
+				fmt.Fprintf(&buf, ".br\nDefault: %s\n", escape(a.Default))
+			}
+		}
+	}
+
+	if len(data.Commands) > 0 {
+		buf.WriteString(".SH COMMANDS\n")
+		for _, c := range data.Commands {
+			fmt.Fprintf(&buf, ".TP\n\\fB%s\\fR\n", escape(c.Name))
+			writeText(&buf, c.Usage)
+		}
+	}
+
+	var envFlags []fluentflag.UsageFlag
+	for _, f := range data.Flags {
+		if f.Env != "" {
+			envFlags = append(envFlags, f)
+		}
+	}
+	if len(envFlags) > 0 {
+		buf.WriteString(".SH ENVIRONMENT\n")
+		for _, f := range envFlags {
+			fmt.Fprintf(&buf, ".TP\n\\fB%s\\fR\n", escape(f.Env))
+			fmt.Fprintf(&buf, "Sets \\fB%s\\fR.\n", escape(dashed(f.Name)))
+		}
+	}
+
+	if len(data.Examples) > 0 {
+		buf.WriteString(".SH EXAMPLES\n")
+		for i, ex := range data.Examples {
+			if i > 0 {
+				buf.WriteString(".PP\n")
+			}
+			if ex.Description != "" {
+				writeText(&buf, ex.Description)
+			}
+			fmt.Fprintf(&buf, ".PP\n.RS 4\n.nf\n%s\n.fi\n.RE\n", escape(ex.Command))
+		}
+	}
+
+	if data.Footer != "" {
+		buf.WriteString(".SH NOTES\n")
+		writeParagraphs(&buf, data.Footer)
+	}
+	return buf.Bytes(), nil
+}
+
+// writeFlag writes a flag's tagged paragraph: its names and value type, then
+// its usage, default, and other notes.
+func writeFlag(buf *bytes.Buffer, f fluentflag.UsageFlag) {
+	var names []string
+	for _, n := range append([]string{f.Name}, f.Aliases...) {
+		if isShort(n) {
+			names = append(names, `\fB`+escape(dashed(n))+`\fR`)
+		}
+	}
+	for _, n := range append([]string{f.Name}, f.Aliases...) {
+		if !isShort(n) {
+			names = append(names, `\fB`+escape(dashed(n))+`\fR`)
+		}
+	}
+	buf.WriteString(".TP\n" + strings.Join(names, ", "))
+	if f.Type != "" {
+		buf.WriteString(` \fI` + escape(f.Type) + `\fR`)
+	}
+	buf.WriteString("\n")
+	writeText(buf, f.Usage)
+	if f.Default != "" {
+		fmt.Fprintf(buf, ".br\nDefault: %s\n", escape(f.Default))
+	}
+	if f.Required {
+		buf.WriteString(".br\nRequired.\n")
+	}
+	if f.Env != "" {
+		fmt.Fprintf(buf, ".br\nEnvironment: \\fB%s\\fR\n", escape(f.Env))
+	}
+	if len(f.RenamedFrom) > 0 {
+		var old []string
+		for _, n := range f.RenamedFrom {
+			old = append(old, `\fB`+escape(dashed(n))+`\fR`)
+		}
+		fmt.Fprintf(buf, ".br\nFormerly %s.\n", strings.Join(old, ", "))
+	}
+	if f.Deprecated != "" {
+		fmt.Fprintf(buf, ".br\nDeprecated: %s\n", escape(f.Deprecated))
+	}
+}
+
+// writeText writes s as a line of running text, or nothing if s is empty.
+func writeText(buf *bytes.Buffer, s string) {
+	if s = strings.TrimSpace(s); s != "" {
+		buf.WriteString(escape(s) + "\n")
+	}
+}
+
+// writeParagraphs writes s as running text, starting a new paragraph at each
+// blank line.
+func writeParagraphs(buf *bytes.Buffer, s string) {
+	for i, para := range strings.Split(strings.TrimSpace(s), "\n\n") {
+		if i > 0 {
+			buf.WriteString(".PP\n")
+		}
+		for _, line := range strings.Split(para, "\n") {
+			writeText(buf, line)
+		}
+	}
+}
+
+// firstLine returns the first line of s.
+func firstLine(s string) string {
+	line, _, _ := strings.Cut(strings.TrimSpace(s), "\n")
+	return line
+}
+
+// isShort reports whether name is a one-character flag name.
+func isShort(name string) bool {
+	return utf8.RuneCountInString(name) == 1
+}
+
+// dashed returns name as given on the command line, like "-v" or "--verbose".
+func dashed(name string) string {
+	if isShort(name) {
+		return "-" + name
+	}
+	return "--" + name
+}
+
+// escape makes s safe as roff text: backslashes and hyphens are escaped, and
+// a leading period or apostrophe is kept from being read as a request.
+func escape(s string) string {
+	s = strings.ReplaceAll(s, `\`, `\e`)
+	s = strings.ReplaceAll(s, "-", `\-`)
+	if strings.HasPrefix(s, ".") || strings.HasPrefix(s, "'") {
+		s = `\&` + s
+	}
+	return s
+}
+
+// quote returns s as a double-quoted argument to a roff request.
+func quote(s string) string {
+	return `"` + strings.ReplaceAll(escape(s), `"`, `\(dq`) + `"`
+}
@@ -0,0 +1,108 @@
+//go:build go1.18
+
+package doc
+
+import (
+	"errors"
+	"flag"
+	"testing"
+	"time"
+
+	"github.com/mattmc3/fluentflag"
+)
+
+func TestGenerateMan(t *testing.T) {
+	b := fluentflag.NewFlagBuilderWithSet(flag.NewFlagSet("/usr/bin/mytool", flag.ContinueOnError))
+	b.UsageHeader("Process input files")
+	b.UsageFooter("Report bugs at the project home.")
+	b.StringFlag("output", "Write results to a file").Alias('o').Default("out.txt").Env("MYTOOL_OUTPUT").BuildVar()
+	b.BoolFlag("verbose", "Print more").Alias('v').Group("Logging").BuildVar()
+	b.Positional("file", "Input file").Required().BuildString()
+	b.ExampleWithDescription("mytool -o result.txt in.txt", "Write to result.txt")
+
+	got, err := GenerateMan(b, ManOptions{
+		Section: "1",
+		Date:    time.Date(2025, 3, 14, 0, 0, 0, 0, time.UTC),
+		Source:  "mytool 1.4.2",
+		Manual:  "User Commands",
+	})
+	if err != nil {
+		t.Fatalf("GenerateMan returned error: %v", err)
+	}
+	expected := `.TH "MYTOOL" "1" "2025\-03\-14" "mytool 1.4.2" "User Commands"
+.SH NAME
+mytool \- Process input files
+.SH SYNOPSIS
+\fBmytool\fR [flags] <file>
+.SH DESCRIPTION
+Process input files
+.SH OPTIONS
+.TP
+\fB\-o\fR, \fB\-\-output\fR \fIstring\fR
+Write results to a file
+.br
+Default: "out.txt"
+.br
+Environment: \fBMYTOOL_OUTPUT\fR
+.SS Logging
+.TP
+\fB\-v\fR, \fB\-\-verbose\fR
+Print more
+.SH ARGUMENTS
+.TP
+\fIfile\fR
+Input file
+.br
+Required.
+.SH ENVIRONMENT
+.TP
+\fBMYTOOL_OUTPUT\fR
+Sets \fB\-\-output\fR.
+.SH EXAMPLES
+Write to result.txt
+.PP
+.RS 4
+.nf
+mytool \-o result.txt in.txt
+.fi
+.RE
+.SH NOTES
+Report bugs at the project home.
+`
+	if string(got) != expected {
+		t.Errorf("Man page mismatch.\nGot:\n%s\nWant:\n%s", got, expected)
+	}
+}
+
+func TestGenerateMan_Escape(t *testing.T) {
+	tests := []struct {
+		in   string
+		want string
+	}{
+		{`plain`, `plain`},
+		{`a\b`, `a\eb`},
+		{`--flag`, `\-\-flag`},
+		{`.start`, `\&.start`},
+		{`'quoted'`, `\&'quoted'`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			if got := escape(tt.in); got != tt.want {
+				t.Errorf("escape(%q) = %q, want %q", tt.in, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGenerateMan_ConfigError(t *testing.T) {
+	b := fluentflag.NewFlagBuilderWithSet(flag.NewFlagSet("test", flag.ContinueOnError))
+	b.SetErrorHandling(fluentflag.ReturnErrors)
+	b.StringFlag("name", "Name").BuildVar()
+	b.StringFlag("name", "Name again").BuildVar()
+
+	if _, err := GenerateMan(b, ManOptions{}); err == nil {
+		t.Fatal("expected the builder's configuration error")
+	} else if errors.Is(err, fluentflag.ErrUnknownFlag) {
+		t.Errorf("unexpected error kind: %v", err)
+	}
+}
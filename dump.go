@@ -0,0 +1,38 @@
+// dump.go
+// Copyright (c) 2025 mattmc3
+// SPDX-License-Identifier: MIT
+// Project home: https://github.com/mattmc3/fluentflag
+
+package fluentflag
+
+import (
+	"fmt"
+	"io"
+	"strings"
+)
+
+// DumpConfig writes the effective value of every built flag to w, one
+// name=value line per flag. Multiple values are separated by commas.
+func (b *FlagBuilder) DumpConfig(w io.Writer) error {
+	for _, f := range b.flagsBuilt {
+		if _, err := fmt.Fprintf(w, "%s=%s\n", f.names()[0], strings.Join(f.values(), ",")); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// SetDumpOnDebug makes Parse write the effective configuration to the output
+// (see SetOutput) when the named flag, such as --debug, is set.
+func (b *FlagBuilder) SetDumpOnDebug(flagName string) {
+	b.postParse = append(b.postParse, func(b *FlagBuilder) error {
+		f := b.lookup(flagName)
+		if f == nil {
+			return fmt.Errorf("fluentflag: cannot dump on unknown flag --%s", flagName)
+		}
+		if !b.isOn(f) {
+			return nil
+		}
+		return b.DumpConfig(b.outputWriter())
+	})
+}
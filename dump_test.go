@@ -0,0 +1,59 @@
+//go:build go1.18
+
+package fluentflag
+
+import (
+	"flag"
+	"strings"
+	"testing"
+)
+
+func newDumpBuilder() *FlagBuilder {
+	b := NewFlagBuilderWithSet(flag.NewFlagSet("test", flag.ContinueOnError))
+	b.StringFlag("name", "name").Default("foo").BuildVar()
+	b.IntFlag("workers", "workers").Default(4).BuildVar()
+	b.StringFlag("tag", "tags").BuildSlice()
+	b.BoolFlag("debug", "debug mode").BuildVar()
+	return b
+}
+
+func TestDumpConfig(t *testing.T) {
+	b := newDumpBuilder()
+	if _, err := b.Parse([]string{"--tag=a", "--tag=b", "--workers=8"}); err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	var buf strings.Builder
+	if err := b.DumpConfig(&buf); err != nil {
+		t.Fatalf("DumpConfig failed: %v", err)
+	}
+	expected := "name=foo\nworkers=8\ntag=a,b\ndebug=false\n"
+	if buf.String() != expected {
+		t.Errorf("expected %q, got %q", expected, buf.String())
+	}
+}
+
+func TestSetDumpOnDebug(t *testing.T) {
+	tests := []struct {
+		name string
+		args []string
+		want string
+	}{
+		{"debug set", []string{"--debug"}, "name=foo\nworkers=4\ntag=\ndebug=true\n"},
+		{"debug unset", []string{}, ""},
+		{"debug false", []string{"--debug=false"}, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b := newDumpBuilder()
+			var buf strings.Builder
+			b.SetOutput(&buf)
+			b.SetDumpOnDebug("debug")
+			if _, err := b.Parse(tt.args); err != nil {
+				t.Fatalf("Parse failed: %v", err)
+			}
+			if buf.String() != tt.want {
+				t.Errorf("expected %q, got %q", tt.want, buf.String())
+			}
+		})
+	}
+}
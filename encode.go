@@ -0,0 +1,341 @@
+// encode.go
+// Copyright (c) 2025 mattmc3
+// SPDX-License-Identifier: MIT
+// Project home: https://github.com/mattmc3/fluentflag
+
+package fluentflag
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// Format is a config file format written by WriteConfig.
+type Format string
+
+const (
+	FormatJSON Format = "json"
+	FormatYAML Format = "yaml"
+	FormatTOML Format = "toml"
+)
+
+// WriteConfig writes the current value of every flag to w as a config file
+// in format, at each flag's config path (see ConfigPath), so ConfigFile can
+// read it back. In YAML and TOML each value is preceded by the flag's usage as
+// a comment, making the output a ready-made template for users. Hidden flags
+// and --help and --version are left out.
+func (b *FlagBuilder) WriteConfig(w io.Writer, format Format) error {
+	return b.writeConfig(w, format, false)
+}
+
+// WriteChangedConfig is like WriteConfig, but only writes the flags that were
+// set, as reported by Changed.
+func (b *FlagBuilder) WriteChangedConfig(w io.Writer, format Format) error {
+	return b.writeConfig(w, format, true)
+}
+
+// writeConfig writes the flags to w in format, or only the set ones if
+// changedOnly is true.
+func (b *FlagBuilder) writeConfig(w io.Writer, format Format, changedOnly bool) error {
+	root := &configTable{}
+	for _, f := range b.flagsBuilt {
+		if f.isHidden() || f.skipsConfig() || (changedOnly && !b.isSet(f)) {
+			continue
+		}
+		if !root.add(strings.Split(f.configKey(), "."), f.flagUsage(), configValue(f)) {
+			return fmt.Errorf("fluentflag: config path %s of --%s conflicts with another flag", f.configKey(), f.names()[0])
+		}
+	}
+	var buf bytes.Buffer
+	switch format {
+	case FormatJSON:
+		root.writeJSON(&buf, "")
+		buf.WriteString("\n")
+	case FormatYAML:
+		root.writeYAML(&buf, "")
+	case FormatTOML:
+		root.writeTOML(&buf, "")
+	default:
+		return fmt.Errorf("fluentflag: unsupported config format %q", format)
+	}
+	_, err := w.Write(buf.Bytes())
+	return err
+}
+
+// skipsConfig reports whether WriteConfig leaves the flag out.
+func (self *FluentFlag[T]) skipsConfig() bool {
+	return self.noConfig
+}
+
+// configTable is a mapping in a config file being written, with its keys in
+// the order they were added.
+type configTable struct {
+	keys    []string
+	entries map[string]*configEntry
+}
+
+// configEntry is a value in a config file being written: a configScalar, a
+// []configScalar, or a *configTable.
+type configEntry struct {
+	comment string
+	value   any
+}
+
+// configScalar is a value as written in a config file, quoted if it is a
+// string.
+type configScalar struct {
+	text   string
+	quoted bool
+}
+
+// add places value at path, creating tables along the way. It reports false
+// if path runs through or onto a value already added.
+func (t *configTable) add(path []string, comment string, value any) bool {
+	key := path[0]
+	entry, ok := t.entries[key]
+	if len(path) == 1 {
+		if ok {
+			return false
+		}
+		t.set(key, &configEntry{comment: comment, value: value})
+		return true
+	}
+	if !ok {
+		entry = &configEntry{value: &configTable{}}
+		t.set(key, entry)
+	}
+	child, ok := entry.value.(*configTable)
+	return ok && child.add(path[1:], comment, value)
+}
+
+// set adds entry under key.
+func (t *configTable) set(key string, entry *configEntry) {
+	if t.entries == nil {
+		t.entries = map[string]*configEntry{}
+	}
+	t.keys = append(t.keys, key)
+	t.entries[key] = entry
+}
+
+// configValue returns the flag's current value as it is written in a config
+// file: a table of key=value pairs for map flags, a list for slice flags, or
+// else a scalar.
+func configValue(f builtFlag) any {
+	typ := f.goType()
+	switch {
+	case strings.HasPrefix(typ, "map["):
+		elem := typ[strings.Index(typ, "]")+1:]
+		table := &configTable{}
+		for _, pair := range f.values() {
+			key, val, _ := strings.Cut(pair, "=")
+			table.set(key, &configEntry{value: newConfigScalar(val, elem)})
+		}
+		return table
+	case f.isList():
+		list := []configScalar{}
+		for _, val := range f.values() {
+			list = append(list, newConfigScalar(val, strings.TrimPrefix(typ, "[]")))
+		}
+		return list
+	default:
+		return newConfigScalar(f.values()[0], typ)
+	}
+}
+
+// newConfigScalar returns text as a config value of Go type typ. Bools and
+// numbers are written bare and everything else as a string. NaN and infinite
+// floats are written as strings too, since JSON has no way to write them and
+// YAML and TOML spell them differently than Go; the flag parses them back.
+func newConfigScalar(text, typ string) configScalar {
+	switch typ {
+	case "bool", "int", "int64", "uint", "uint64":
+		return configScalar{text: text}
+	case "float64":
+		if f, err := strconv.ParseFloat(text, 64); err == nil && !math.IsNaN(f) && !math.IsInf(f, 0) {
+			return configScalar{text: text}
+		}
+	}
+	return configScalar{text: text, quoted: true}
+}
+
+// configKeyText returns key as written in YAML or TOML, quoted with quote
+// unless it is made of letters, digits, dashes, and underscores.
+func configKeyText(key string, quote func(string) string) string {
+	for _, c := range key {
+		if !(c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' || c == '-' || c == '_') {
+			return quote(key)
+		}
+	}
+	if key == "" {
+		return `""`
+	}
+	return key
+}
+
+// writeComment writes comment as # lines at indent.
+func writeComment(buf *bytes.Buffer, comment, indent string) {
+	if comment == "" {
+		return
+	}
+	for _, line := range strings.Split(comment, "\n") {
+		buf.WriteString(strings.TrimRight(indent+"# "+line, " ") + "\n")
+	}
+}
+
+// writeYAML writes the table as a YAML mapping at indent. Top-level entries,
+// and nested ones with a comment, are separated by a blank line.
+func (t *configTable) writeYAML(buf *bytes.Buffer, indent string) {
+	for i, key := range t.keys {
+		entry := t.entries[key]
+		if i > 0 && (indent == "" || entry.comment != "") {
+			buf.WriteString("\n")
+		}
+		writeComment(buf, entry.comment, indent)
+		buf.WriteString(indent + configKeyText(key, strconv.Quote) + ":")
+		switch v := entry.value.(type) {
+		case configScalar:
+			buf.WriteString(" " + v.quote(strconv.Quote) + "\n")
+		case []configScalar:
+			if len(v) == 0 {
+				buf.WriteString(" []\n")
+				break
+			}
+			buf.WriteString("\n")
+			for _, item := range v {
+				buf.WriteString(indent + "  - " + item.quote(strconv.Quote) + "\n")
+			}
+		case *configTable:
+			buf.WriteString("\n")
+			v.writeYAML(buf, indent+"  ")
+		}
+	}
+}
+
+// writeTOML writes the table's values, then its tables under [path.key]
+// headers. Headers, and top-level values or nested ones with a comment, are
+// separated by a blank line.
+func (t *configTable) writeTOML(buf *bytes.Buffer, path string) {
+	written := 0
+	for _, key := range t.keys {
+		entry := t.entries[key]
+		var text string
+		switch v := entry.value.(type) {
+		case configScalar:
+			text = v.quote(tomlQuote)
+		case []configScalar:
+			var items []string
+			for _, item := range v {
+				items = append(items, item.quote(tomlQuote))
+			}
+			text = "[" + strings.Join(items, ", ") + "]"
+		default:
+			continue
+		}
+		if written > 0 && (path == "" || entry.comment != "") {
+			buf.WriteString("\n")
+		}
+		writeComment(buf, entry.comment, "")
+		buf.WriteString(configKeyText(key, tomlQuote) + " = " + text + "\n")
+		written++
+	}
+	for _, key := range t.keys {
+		entry := t.entries[key]
+		table, ok := entry.value.(*configTable)
+		if !ok {
+			continue
+		}
+		header := configKeyText(key, tomlQuote)
+		if path != "" {
+			header = path + "." + header
+		}
+		if buf.Len() > 0 {
+			buf.WriteString("\n")
+		}
+		writeComment(buf, entry.comment, "")
+		buf.WriteString("[" + header + "]\n")
+		table.writeTOML(buf, header)
+	}
+}
+
+// writeJSON writes the table as a JSON object whose members are at indent
+// plus two spaces.
+func (t *configTable) writeJSON(buf *bytes.Buffer, indent string) {
+	if len(t.keys) == 0 {
+		buf.WriteString("{}")
+		return
+	}
+	buf.WriteString("{\n")
+	for i, key := range t.keys {
+		buf.WriteString(indent + "  " + jsonQuote(key) + ": ")
+		switch v := t.entries[key].value.(type) {
+		case configScalar:
+			buf.WriteString(v.quote(jsonQuote))
+		case []configScalar:
+			var items []string
+			for _, item := range v {
+				items = append(items, item.quote(jsonQuote))
+			}
+			buf.WriteString("[" + strings.Join(items, ", ") + "]")
+		case *configTable:
+			v.writeJSON(buf, indent+"  ")
+		}
+		if i < len(t.keys)-1 {
+			buf.WriteString(",")
+		}
+		buf.WriteString("\n")
+	}
+	buf.WriteString(indent + "}")
+}
+
+// quote returns the scalar as written, using quote if it is a string.
+func (s configScalar) quote(quote func(string) string) string {
+	if s.quoted {
+		return quote(s.text)
+	}
+	return s.text
+}
+
+// tomlQuote returns s as a TOML basic string. TOML has fewer escapes than Go,
+// so other control characters are written as \uXXXX, and bytes that aren't
+// valid UTF-8, which a TOML string can't hold, as \uFFFD.
+func tomlQuote(s string) string {
+	var sb strings.Builder
+	sb.WriteByte('"')
+	for i := 0; i < len(s); {
+		r, size := utf8.DecodeRuneInString(s[i:])
+		i += size
+		switch {
+		case r == '"' || r == '\\':
+			sb.WriteByte('\\')
+			sb.WriteRune(r)
+		case r == '\b':
+			sb.WriteString(`\b`)
+		case r == '\t':
+			sb.WriteString(`\t`)
+		case r == '\n':
+			sb.WriteString(`\n`)
+		case r == '\f':
+			sb.WriteString(`\f`)
+		case r == '\r':
+			sb.WriteString(`\r`)
+		case r < 0x20 || r == 0x7f || r == utf8.RuneError && size == 1:
+			fmt.Fprintf(&sb, `\u%04X`, r)
+		default:
+			sb.WriteRune(r)
+		}
+	}
+	sb.WriteByte('"')
+	return sb.String()
+}
+
+// jsonQuote returns s as a JSON string.
+func jsonQuote(s string) string {
+	data, _ := json.Marshal(s)
+	return string(data)
+}
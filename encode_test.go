@@ -0,0 +1,185 @@
+//go:build go1.18
+
+package fluentflag
+
+import (
+	"flag"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func newWriteConfigBuilder() *FlagBuilder {
+	b := NewFlagBuilderWithSet(flag.NewFlagSet("test", flag.ContinueOnError))
+	b.EnableHelp()
+	b.StringFlag("name", "Name to greet").Default("foo").BuildVar()
+	b.IntFlag("port", "Port to listen on").Default(8080).ConfigPath("server.port").BuildVar()
+	b.StringFlag("host", "").Default("localhost").ConfigPath("server.host").BuildVar()
+	b.StringFlag("tag", "Tags to apply").BuildSlice()
+	b.IntFlag("limit", "Limits by resource").BuildMap()
+	b.BoolFlag("debug", "Debug mode").BuildVar()
+	b.StringFlag("secret", "internal").Hidden().BuildVar()
+	return b
+}
+
+func TestWriteConfig(t *testing.T) {
+	tests := []struct {
+		format Format
+		want   string
+	}{
+		{FormatYAML, `# Name to greet
+name: "foo"
+
+server:
+  # Port to listen on
+  port: 9000
+  host: "localhost"
+
+# Tags to apply
+tag:
+  - "a"
+  - "b c"
+
+# Limits by resource
+limit:
+  cpu: 2
+
+# Debug mode
+debug: false
+`},
+		{FormatTOML, `# Name to greet
+name = "foo"
+
+# Tags to apply
+tag = ["a", "b c"]
+
+# Debug mode
+debug = false
+
+[server]
+# Port to listen on
+port = 9000
+host = "localhost"
+
+# Limits by resource
+[limit]
+cpu = 2
+`},
+		{FormatJSON, `{
+  "name": "foo",
+  "server": {
+    "port": 9000,
+    "host": "localhost"
+  },
+  "tag": ["a", "b c"],
+  "limit": {
+    "cpu": 2
+  },
+  "debug": false
+}
+`},
+	}
+	for _, tt := range tests {
+		t.Run(string(tt.format), func(t *testing.T) {
+			b := newWriteConfigBuilder()
+			if _, err := b.Parse([]string{"--port=9000", "--tag=a", "--tag=b c", "--limit=cpu=2"}); err != nil {
+				t.Fatalf("Parse failed: %v", err)
+			}
+			var buf strings.Builder
+			if err := b.WriteConfig(&buf, tt.format); err != nil {
+				t.Fatalf("WriteConfig failed: %v", err)
+			}
+			if buf.String() != tt.want {
+				t.Fatalf("Config output mismatch.\nGot:\n%s\nWant:\n%s", buf.String(), tt.want)
+			}
+			loaded := newWriteConfigBuilder()
+			if err := loaded.ConfigReader(strings.NewReader(buf.String()), string(tt.format)); err != nil {
+				t.Fatalf("ConfigReader failed: %v", err)
+			}
+			if _, err := loaded.Parse([]string{}); err != nil {
+				t.Fatalf("Parse failed: %v", err)
+			}
+			if !reflect.DeepEqual(loaded.ToArgs(), b.ToArgs()) {
+				t.Errorf("round trip: expected %q, got %q", b.ToArgs(), loaded.ToArgs())
+			}
+		})
+	}
+}
+
+func TestWriteChangedConfig(t *testing.T) {
+	b := newWriteConfigBuilder()
+	if _, err := b.Parse([]string{"--debug", "--name=bar"}); err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	var buf strings.Builder
+	if err := b.WriteChangedConfig(&buf, FormatYAML); err != nil {
+		t.Fatalf("WriteChangedConfig failed: %v", err)
+	}
+	want := "# Name to greet\nname: \"bar\"\n\n# Debug mode\ndebug: true\n"
+	if buf.String() != want {
+		t.Errorf("expected %q, got %q", want, buf.String())
+	}
+}
+
+func TestWriteConfig_Errors(t *testing.T) {
+	b := newWriteConfigBuilder()
+	if err := b.WriteConfig(&strings.Builder{}, "ini"); err == nil || !strings.Contains(err.Error(), `unsupported config format "ini"`) {
+		t.Errorf("expected unsupported format error, got %v", err)
+	}
+	b.StringFlag("server", "server").BuildVar()
+	if err := b.WriteConfig(&strings.Builder{}, FormatJSON); err == nil || !strings.Contains(err.Error(), "config path server of --server conflicts") {
+		t.Errorf("expected conflict error, got %v", err)
+	}
+}
+
+func TestTOMLQuote(t *testing.T) {
+	tests := []struct {
+		in, want string
+	}{
+		{"plain", `"plain"`},
+		{"say \"hi\"\\", `"say \"hi\"\\"`},
+		{"a\tb\nc", `"a\tb\nc"`},
+		{"bell\a\x1b\x7f", `"bell\u0007\u001B\u007F"`},
+		{"bad\xffbyte", `"bad\uFFFDbyte"`},
+		{"héllo", `"héllo"`},
+	}
+	for _, tt := range tests {
+		if got := tomlQuote(tt.in); got != tt.want {
+			t.Errorf("tomlQuote(%q) = %s, want %s", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestWriteConfig_SpecialValues(t *testing.T) {
+	newBuilder := func() *FlagBuilder {
+		b := NewFlagBuilderWithSet(flag.NewFlagSet("test", flag.ContinueOnError))
+		b.StringFlag("name", "name").BuildVar()
+		b.Float64Flag("ratio", "ratio").BuildVar()
+		return b
+	}
+	for _, format := range []Format{FormatYAML, FormatTOML, FormatJSON} {
+		t.Run(string(format), func(t *testing.T) {
+			b := newBuilder()
+			if _, err := b.Parse([]string{"--name=a\x07b", "--ratio=-Inf"}); err != nil {
+				t.Fatalf("Parse failed: %v", err)
+			}
+			var buf strings.Builder
+			if err := b.WriteConfig(&buf, format); err != nil {
+				t.Fatalf("WriteConfig failed: %v", err)
+			}
+			if !strings.Contains(buf.String(), `"-Inf"`) {
+				t.Errorf("expected -Inf written as a string, got:\n%s", buf.String())
+			}
+			loaded := newBuilder()
+			if err := loaded.ConfigReader(strings.NewReader(buf.String()), string(format)); err != nil {
+				t.Fatalf("ConfigReader failed: %v", err)
+			}
+			if _, err := loaded.Parse([]string{}); err != nil {
+				t.Fatalf("Parse failed: %v", err)
+			}
+			if !reflect.DeepEqual(loaded.ToArgs(), b.ToArgs()) {
+				t.Errorf("round trip: expected %q, got %q", b.ToArgs(), loaded.ToArgs())
+			}
+		})
+	}
+}
@@ -0,0 +1,151 @@
+// env.go
+// Copyright (c) 2025 mattmc3
+// SPDX-License-Identifier: MIT
+// Project home: https://github.com/mattmc3/fluentflag
+
+package fluentflag
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// Env reads the flag from the environment variable name when it isn't given
+// on the command line. The variable is shown in the usage text.
+func (self *FluentFlag[T]) Env(name string) *FluentFlag[T] {
+	self.env = name
+	return self
+}
+
+// envVar returns the environment variable the flag is read from, if any.
+func (self *FluentFlag[T]) envVar() string {
+	if self.env == "" && self.builder.envPrefix != "" {
+		return self.builder.envPrefix + "_" + envName(self.name)
+	}
+	return self.env
+}
+
+// envName converts a flag name like "max-args" to an environment variable name
+// like "MAX_ARGS".
+func envName(name string) string {
+	return strings.ToUpper(strings.NewReplacer("-", "_", ".", "_").Replace(name))
+}
+
+// EnvPrefix reads every flag without its own Env from an environment variable
+// named after it, so with the prefix "MYAPP" --max-args reads MYAPP_MAX_ARGS.
+func (b *FlagBuilder) EnvPrefix(prefix string) {
+	b.envPrefix = prefix
+}
+
+// SetEnvSeparator sets the separator that splits an environment variable into
+// values for slice and map flags. It defaults to ",".
+func (b *FlagBuilder) SetEnvSeparator(sep string) {
+	b.envSeparator = sep
+}
+
+// applyEnv sets flags that weren't set on the command line from their
+// environment variables.
+func (b *FlagBuilder) applyEnv() error {
+	for _, f := range b.flagsBuilt {
+		name := f.envVar()
+		if name == "" || b.isSet(f) {
+			continue
+		}
+		val, ok := b.lookupEnv(name)
+		if !ok {
+			continue
+		}
+		vals := []string{val}
+		if f.isList() && val == "" {
+			vals = nil
+		} else if f.isList() {
+			sep := b.envSeparator
+			if sep == "" {
+				sep = ","
+			}
+			vals = strings.Split(val, sep)
+		}
+		for _, val := range vals {
+			if err := f.set(val); err != nil {
+				return &FlagError{Kind: kindOf(err), Flag: f.names()[0], Value: val, Err: err,
+					msg: fmt.Sprintf("env value %q for flag --%s from $%s: %v", val, f.names()[0], name, err)}
+			}
+		}
+		b.sources[f.names()[0]] = SourceEnv
+	}
+	return nil
+}
+
+// lookupEnv looks up an environment variable, falling back to the variables
+// loaded from .env files.
+func (b *FlagBuilder) lookupEnv(name string) (string, bool) {
+	if val, ok := os.LookupEnv(name); ok {
+		return val, true
+	}
+	val, ok := b.dotenv[name]
+	return val, ok
+}
+
+// LoadDotenv reads KEY=VALUE lines from a .env file and makes them available
+// to Env, EnvPrefix, and RequireConfirmation as if they were environment
+// variables. Variables set in the real environment, or loaded by an earlier
+// call, take precedence. Blank lines, # comments, and an "export" prefix are
+// ignored, and values may be single- or double-quoted.
+func (b *FlagBuilder) LoadDotenv(path string) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	vars, err := parseDotenv(file)
+	if err != nil {
+		return fmt.Errorf("fluentflag: dotenv file %s: %w", path, err)
+	}
+	if b.dotenv == nil {
+		b.dotenv = map[string]string{}
+	}
+	for key, val := range vars {
+		if _, ok := b.dotenv[key]; !ok {
+			b.dotenv[key] = val
+		}
+	}
+	return nil
+}
+
+// parseDotenv parses the KEY=VALUE lines of a .env file.
+func parseDotenv(r io.Reader) (map[string]string, error) {
+	vars := map[string]string{}
+	scanner := bufio.NewScanner(r)
+	for num := 1; scanner.Scan(); num++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || line[0] == '#' {
+			continue
+		}
+		line = strings.TrimPrefix(line, "export ")
+		key, val, ok := strings.Cut(line, "=")
+		key = strings.TrimSpace(key)
+		if !ok || key == "" || strings.ContainsAny(key, " \t") {
+			return nil, fmt.Errorf("line %d: expected KEY=VALUE, got %s", num, line)
+		}
+		val = strings.TrimSpace(stripComment(val))
+		switch {
+		case strings.HasPrefix(val, `"`):
+			unquoted, err := strconv.Unquote(val)
+			if err != nil {
+				return nil, fmt.Errorf("line %d: invalid quoted value %s", num, val)
+			}
+			val = unquoted
+		case strings.HasPrefix(val, "'"):
+			if len(val) < 2 || !strings.HasSuffix(val, "'") {
+				return nil, fmt.Errorf("line %d: invalid quoted value %s", num, val)
+			}
+			val = val[1 : len(val)-1]
+		}
+		vars[key] = val
+	}
+	return vars, scanner.Err()
+}
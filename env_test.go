@@ -0,0 +1,158 @@
+//go:build go1.18
+
+package fluentflag
+
+import (
+	"flag"
+	"strings"
+	"testing"
+)
+
+func TestEnv(t *testing.T) {
+	tests := []struct {
+		name    string
+		args    []string
+		env     string
+		want    int
+		wantErr string
+	}{
+		{"default", []string{}, "", 8080, ""},
+		{"from env", []string{}, "9090", 9090, ""},
+		{"command line wins", []string{"--port=7070"}, "9090", 7070, ""},
+		{"invalid", []string{}, "high", 0, `env value "high" for flag --port from $MY_APP_PORT`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.env != "" {
+				t.Setenv("MY_APP_PORT", tt.env)
+			}
+			b := NewFlagBuilderWithSet(flag.NewFlagSet("test", flag.ContinueOnError))
+			port := b.IntFlag("port", "listen port").Default(8080).Env("MY_APP_PORT").BuildVar()
+			_, err := b.Parse(tt.args)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Errorf("expected error containing %q, got %v", tt.wantErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if *port != tt.want {
+				t.Errorf("expected %d, got %d", tt.want, *port)
+			}
+		})
+	}
+}
+
+func TestEnv_BeforeConfig(t *testing.T) {
+	t.Setenv("MY_APP_PORT", "9090")
+	b := NewFlagBuilderWithSet(flag.NewFlagSet("test", flag.ContinueOnError))
+	port := b.IntFlag("port", "listen port").Env("MY_APP_PORT").Required().BuildVar()
+	if err := b.ConfigFile(writeConfig(t, "config.json", `{"port": 6060}`)); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := b.Parse([]string{}); err != nil {
+		t.Fatal(err)
+	}
+	if *port != 9090 {
+		t.Errorf("expected the env value 9090, got %d", *port)
+	}
+}
+
+func TestEnv_Usage(t *testing.T) {
+	b := NewFlagBuilderWithSet(flag.NewFlagSet("test", flag.ContinueOnError))
+	b.IntFlag("port", "listen port").Default(8080).Env("MY_APP_PORT").BuildVar()
+	var out strings.Builder
+	b.printUsage(&out)
+	if want := "listen port (default 8080) [env: MY_APP_PORT]\n"; !strings.HasSuffix(out.String(), want) {
+		t.Errorf("expected usage ending in %q, got %q", want, out.String())
+	}
+}
+
+func TestEnvPrefix(t *testing.T) {
+	t.Setenv("MYAPP_MAX_ARGS", "3")
+	t.Setenv("MYAPP_TAG", "a;b;c")
+	t.Setenv("MYAPP_LABEL", "env=prod;team=core")
+	t.Setenv("MYAPP_EMPTY", "")
+	t.Setenv("OTHER_NAME", "own")
+	b := NewFlagBuilderWithSet(flag.NewFlagSet("test", flag.ContinueOnError))
+	b.EnvPrefix("MYAPP")
+	b.SetEnvSeparator(";")
+	maxArgs := b.IntFlag("max-args", "maximum arguments").BuildVar()
+	tags := b.StringFlag("tag", "tags").BuildSlice()
+	labels := b.StringFlag("label", "labels").BuildMap()
+	empty := b.IntFlag("empty", "empty list").BuildSlice()
+	name := b.StringFlag("name", "name").Env("OTHER_NAME").BuildVar()
+	if _, err := b.Parse([]string{}); err != nil {
+		t.Fatal(err)
+	}
+	if *maxArgs != 3 {
+		t.Errorf("expected max-args 3, got %d", *maxArgs)
+	}
+	if strings.Join(*tags, ",") != "a,b,c" {
+		t.Errorf("expected tags a,b,c, got %v", *tags)
+	}
+	if len(*labels) != 2 || (*labels)["team"] != "core" {
+		t.Errorf("unexpected labels %v", *labels)
+	}
+	if len(*empty) != 0 {
+		t.Errorf("expected no values from an empty variable, got %v", *empty)
+	}
+	if *name != "own" {
+		t.Errorf("expected the flag's own Env to win over the prefix, got %q", *name)
+	}
+	if got := b.lookup("max-args").Usage(); !strings.HasSuffix(got, "[env: MYAPP_MAX_ARGS]") {
+		t.Errorf("expected derived env var in usage, got %q", got)
+	}
+}
+
+func TestLoadDotenv(t *testing.T) {
+	t.Setenv("MYAPP_HOST", "from-env")
+	path := writeConfig(t, ".env", `# local settings
+MYAPP_PORT=9090
+export MYAPP_HOST=from-dotenv
+MYAPP_NAME="my app\tv2"
+MYAPP_TAG='a,b' # tags
+MYAPP_MODE=dev # comment
+`)
+	b := NewFlagBuilderWithSet(flag.NewFlagSet("test", flag.ContinueOnError))
+	b.EnvPrefix("MYAPP")
+	port := b.IntFlag("port", "listen port").BuildVar()
+	host := b.StringFlag("host", "listen host").BuildVar()
+	name := b.StringFlag("name", "app name").BuildVar()
+	tags := b.StringFlag("tag", "tags").BuildSlice()
+	mode := b.StringFlag("mode", "run mode").BuildVar()
+	if err := b.LoadDotenv(path); err != nil {
+		t.Fatal(err)
+	}
+	if err := b.LoadDotenv(writeConfig(t, ".env", "MYAPP_PORT=1\n")); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := b.Parse([]string{}); err != nil {
+		t.Fatal(err)
+	}
+	if *port != 9090 {
+		t.Errorf("expected the first file's port 9090, got %d", *port)
+	}
+	if *host != "from-env" {
+		t.Errorf("expected the real environment to win, got %q", *host)
+	}
+	if *name != "my app\tv2" {
+		t.Errorf("expected unescaped name, got %q", *name)
+	}
+	if strings.Join(*tags, "|") != "a|b" {
+		t.Errorf("expected tags a|b, got %v", *tags)
+	}
+	if *mode != "dev" {
+		t.Errorf("expected dev without the comment, got %q", *mode)
+	}
+}
+
+func TestLoadDotenv_Invalid(t *testing.T) {
+	b := NewFlagBuilderWithSet(flag.NewFlagSet("test", flag.ContinueOnError))
+	err := b.LoadDotenv(writeConfig(t, ".env", "A=1\nnot a pair\n"))
+	if err == nil || !strings.Contains(err.Error(), "line 2: expected KEY=VALUE") {
+		t.Errorf("expected line error, got %v", err)
+	}
+}
@@ -0,0 +1,110 @@
+// errors.go
+// Copyright (c) 2025 mattmc3
+// SPDX-License-Identifier: MIT
+// Project home: https://github.com/mattmc3/fluentflag
+
+package fluentflag
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// The kinds of FlagError, for use with errors.Is.
+var (
+	ErrUnknownFlag        = errors.New("unknown flag")
+	ErrInvalidValue       = errors.New("invalid value")
+	ErrMissingRequired    = errors.New("missing required flag")
+	ErrConstraintViolated = errors.New("constraint violated")
+)
+
+// FlagError is an error about a particular flag or argument. errors.Is
+// matches it against its Kind, and errors.As can extract it to inspect the
+// flag and value:
+//
+//	var fe *fluentflag.FlagError
+//	if errors.As(err, &fe) && errors.Is(fe, fluentflag.ErrInvalidValue) {
+//		fmt.Printf("bad --%s: %q\n", fe.Flag, fe.Value)
+//	}
+type FlagError struct {
+	Kind  error  // ErrUnknownFlag, ErrInvalidValue, ErrMissingRequired, or ErrConstraintViolated
+	Flag  string // name of the flag or argument, without dashes
+	Value string // offending value, if any
+	Err   error  // underlying error, if any
+	msg   string // message, if not Err's
+}
+
+// newFlagError returns a FlagError with a formatted message.
+func newFlagError(kind error, flag, value, format string, args ...any) *FlagError {
+	return &FlagError{Kind: kind, Flag: flag, Value: value, msg: fmt.Sprintf(format, args...)}
+}
+
+// Error returns the error message.
+func (e *FlagError) Error() string {
+	if e.msg == "" && e.Err != nil {
+		return e.Err.Error()
+	}
+	return e.msg
+}
+
+// Unwrap returns the underlying error.
+func (e *FlagError) Unwrap() error {
+	return e.Err
+}
+
+// Is reports whether target is the error's Kind.
+func (e *FlagError) Is(target error) bool {
+	return target == e.Kind
+}
+
+// rejected returns a FlagError of the given kind for a value the flag can't
+// accept, remembering it so flagSetError can recover its kind.
+func (self *FluentFlag[T]) rejected(kind error, value string, err error) error {
+	fe := &FlagError{Kind: kind, Flag: self.name, Value: value, Err: err}
+	self.builder.setErr = fe
+	return fe
+}
+
+// kindOf returns the kind of a FlagError in err's chain, or ErrInvalidValue.
+func kindOf(err error) error {
+	var fe *FlagError
+	if errors.As(err, &fe) {
+		return fe.Kind
+	}
+	return ErrInvalidValue
+}
+
+// flagSetError converts an error from flag.FlagSet.Parse into a FlagError,
+// keeping its message. A value rejected by a fluent flag keeps the kind the
+// flag gave it; otherwise the flag and value are read from the message.
+func (b *FlagBuilder) flagSetError(err error) error {
+	msg := err.Error()
+	switch {
+	case strings.HasPrefix(msg, "flag provided but not defined: "):
+		name := strings.TrimLeft(strings.TrimPrefix(msg, "flag provided but not defined: "), "-")
+		return &FlagError{Kind: ErrUnknownFlag, Flag: name, msg: msg}
+	case strings.HasPrefix(msg, "flag needs an argument: "):
+		name := strings.TrimLeft(strings.TrimPrefix(msg, "flag needs an argument: "), "-")
+		return &FlagError{Kind: ErrInvalidValue, Flag: name, msg: msg}
+	case strings.HasPrefix(msg, "invalid value ") || strings.HasPrefix(msg, "invalid boolean value "):
+		if fe := b.setErr; fe != nil {
+			return &FlagError{Kind: fe.Kind, Flag: fe.Flag, Value: fe.Value, Err: fe, msg: msg}
+		}
+		fe := &FlagError{Kind: ErrInvalidValue, msg: msg}
+		if i := strings.Index(msg, `"`); i != -1 {
+			rest := msg[i:]
+			quoted, err := strconv.QuotedPrefix(rest)
+			if err != nil {
+				return fe
+			}
+			fe.Value, _ = strconv.Unquote(quoted)
+			rest = strings.TrimPrefix(rest[len(quoted):], " for")
+			rest = strings.TrimPrefix(rest, " flag")
+			fe.Flag, _, _ = strings.Cut(strings.TrimLeft(rest, " -"), ":")
+		}
+		return fe
+	}
+	return err
+}
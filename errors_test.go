@@ -0,0 +1,54 @@
+//go:build go1.18
+
+package fluentflag
+
+import (
+	"errors"
+	"flag"
+	"io"
+	"testing"
+)
+
+func TestFlagError(t *testing.T) {
+	tests := []struct {
+		name      string
+		args      []string
+		kind      error
+		wantFlag  string
+		wantValue string
+	}{
+		{"unknown flag", []string{"--colour=red"}, ErrUnknownFlag, "colour", ""},
+		{"unparseable", []string{"--port=http"}, ErrInvalidValue, "port", "http"},
+		{"unparseable alias", []string{"-p", "http"}, ErrInvalidValue, "port", "http"},
+		{"out of range", []string{"--port=0"}, ErrConstraintViolated, "port", "0"},
+		{"bad choice", []string{"--port=80", "--mode=fast"}, ErrConstraintViolated, "mode", "fast"},
+		{"missing value", []string{"--port"}, ErrInvalidValue, "port", ""},
+		{"required", []string{}, ErrMissingRequired, "port", ""},
+		{"requires", []string{"--port=80", "--cert=a.pem"}, ErrConstraintViolated, "cert", ""},
+		{"other value", []string{"--port=80", "--weight=x"}, ErrInvalidValue, "weight", "x"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fs := flag.NewFlagSet("test", flag.ContinueOnError)
+			fs.SetOutput(io.Discard)
+			b := NewFlagBuilderWithSet(fs)
+			b.SetOutput(io.Discard)
+			b.IntFlag("port", "port").Alias('p').Required().Min(1).BuildVar()
+			b.StringFlag("mode", "mode").Choices("safe", "slow").BuildVar()
+			b.StringFlag("cert", "certificate").Requires("key").BuildVar()
+			b.StringFlag("key", "key").BuildVar()
+			b.BoolFlag("weight", "weight").BuildCounter()
+			_, err := b.Parse(tt.args)
+			if !errors.Is(err, tt.kind) {
+				t.Fatalf("expected %v, got %v", tt.kind, err)
+			}
+			var fe *FlagError
+			if !errors.As(err, &fe) {
+				t.Fatalf("expected a *FlagError, got %T", err)
+			}
+			if fe.Flag != tt.wantFlag || fe.Value != tt.wantValue {
+				t.Errorf("expected flag %q value %q, got flag %q value %q", tt.wantFlag, tt.wantValue, fe.Flag, fe.Value)
+			}
+		})
+	}
+}
@@ -13,6 +13,9 @@ import (
 	"os"
 	"strconv"
 	"strings"
+	"text/template"
+	"time"
+	"unicode/utf8"
 )
 
 // FlagType is a type constraint for the basic flag data types supported by FlagBuilder.
@@ -20,8 +23,79 @@ type FlagType interface {
 	~bool | ~string | ~int | ~int64 | ~float64 | ~uint | ~uint64
 }
 
+// flagValue implements flag.Value for a single value of T.
+type flagValue[T FlagType] struct {
+	flag   *FluentFlag[T]
+	target *T
+}
+
+// String returns the string representation of the value.
+func (self *flagValue[T]) String() string {
+	if self.target == nil {
+		return ""
+	}
+	return fmt.Sprint(*self.target)
+}
+
+// Set parses and validates val and stores it.
+func (self *flagValue[T]) Set(val string) error {
+	parsed, err := self.flag.parse(val)
+	if err != nil {
+		return err
+	}
+	*self.target = parsed
+	return nil
+}
+
+// Get returns the value.
+func (self *flagValue[T]) Get() any {
+	return *self.target
+}
+
+// optionalValue returns the value used when the flag is given without one.
+func (self *flagValue[T]) optionalValue() (string, bool) {
+	return self.flag.optionalValue()
+}
+
+// list returns the value formatted as a one-element list.
+func (self *flagValue[T]) list() []string {
+	return []string{self.String()}
+}
+
+// setZero sets the value to the zero value of T.
+func (self *flagValue[T]) setZero() {
+	var zero T
+	*self.target = zero
+}
+
+// cloneFor returns new storage set to the default of f, a clone of its flag.
+func (self *flagValue[T]) cloneFor(f *FluentFlag[T]) fluentValue {
+	target := new(T) // allocate on heap
+	*target = f.defaultVal
+	return &flagValue[T]{flag: f, target: target}
+}
+
+// shareWith returns a value for f, a copy of its flag under another name,
+// bound to the same storage.
+func (self *flagValue[T]) shareWith(f *FluentFlag[T]) fluentValue {
+	return &flagValue[T]{flag: f, target: self.target}
+}
+
+// goType returns the Go type of the stored value.
+func (self *flagValue[T]) goType() string {
+	return fmt.Sprintf("%T", *new(T))
+}
+
+// IsBoolFlag lets the flag package accept bool flags without a value.
+func (self *flagValue[T]) IsBoolFlag() bool {
+	var zero T
+	_, ok := any(zero).(bool)
+	return ok
+}
+
 // accumValues implements flag.Value for accumulating values into a slice.
 type accumValues[T FlagType] struct {
+	flag   *FluentFlag[T]
 	target *[]T
 }
 
@@ -35,7 +109,13 @@ func (self *accumValues[T]) String() string {
 
 // Set appends a new value to the slice.
 func (self *accumValues[T]) Set(val string) error {
-	parsed, err := parse[T](val)
+	var parsed T
+	var err error
+	if self.flag != nil {
+		parsed, err = self.flag.parse(val)
+	} else {
+		parsed, err = parse[T](val)
+	}
 	if err != nil {
 		return err
 	}
@@ -43,6 +123,84 @@ func (self *accumValues[T]) Set(val string) error {
 	return nil
 }
 
+// Get returns the accumulated slice.
+func (self *accumValues[T]) Get() any {
+	return *self.target
+}
+
+// optionalValue returns the value used when the flag is given without one.
+func (self *accumValues[T]) optionalValue() (string, bool) {
+	if self.flag == nil {
+		return "", false
+	}
+	return self.flag.optionalValue()
+}
+
+// list returns the accumulated values formatted as strings.
+func (self *accumValues[T]) list() []string {
+	var vals []string
+	for _, v := range *self.target {
+		vals = append(vals, fmt.Sprint(v))
+	}
+	return vals
+}
+
+// setZero empties the slice.
+func (self *accumValues[T]) setZero() {
+	*self.target = []T{}
+}
+
+// cloneFor returns a new, empty slice for the clone f of its flag.
+func (self *accumValues[T]) cloneFor(f *FluentFlag[T]) fluentValue {
+	clone := &accumValues[T]{target: &[]T{}}
+	if self.flag != nil {
+		clone.flag = f
+	}
+	return clone
+}
+
+// shareWith returns a value for f, a copy of its flag under another name,
+// bound to the same slice.
+func (self *accumValues[T]) shareWith(f *FluentFlag[T]) fluentValue {
+	shared := &accumValues[T]{target: self.target}
+	if self.flag != nil {
+		shared.flag = f
+	}
+	return shared
+}
+
+// isList reports that the slice collects a value each time it is set.
+func (self *accumValues[T]) isList() bool {
+	return true
+}
+
+// goType returns the Go type of the accumulated slice.
+func (self *accumValues[T]) goType() string {
+	return fmt.Sprintf("[]%T", *new(T))
+}
+
+// isBoolValue reports whether v is a boolean flag.Value that takes no argument.
+func isBoolValue(v flag.Value) bool {
+	bv, ok := v.(interface{ IsBoolFlag() bool })
+	return ok && bv.IsBoolFlag()
+}
+
+// isListValue reports whether v collects a value each time it is set, like
+// slice and map flags.
+func isListValue(v flag.Value) bool {
+	lv, ok := v.(interface{ isList() bool })
+	return ok && lv.isList()
+}
+
+// fluentValue is a flag.Value that can describe its contents. Get returns
+// the current value, as its Go type, like flag.Getter.
+type fluentValue interface {
+	flag.Getter
+	list() []string
+	goType() string
+	setZero()
+}
+
 // Opt is a CLI option
 type FluentFlag[T FlagType] struct {
 	builder    *FlagBuilder
@@ -50,11 +208,98 @@ type FluentFlag[T FlagType] struct {
 	alias      rune
 	defaultVal T
 	usage      string
+
+	value       fluentValue                                    // storage bound by one of the Build methods
+	transforms  []func(*FluentFlag[T], string) (string, error) // rewrites applied to raw values before parsing
+	parsers     []func(string) (T, error)                      // custom parsers tried in order, if any
+	checks      []func(*FluentFlag[T], T) error                // validators run as each value is set, passed the flag being set
+	choices     []T                                            // allowed values, if restricted
+	countBytes  bool                                           // MaxLen and MinLen count bytes, not runes
+	minCount    int                                            // fewest values a slice flag accepts
+	maxCount    int                                            // most values a slice flag accepts, if positive
+	confirm     *confirmation                                  // confirmation required to use the flag, if any
+	choicesFrom string                                         // name of the flag whose values constrain this one
+
+	dynamicChoices func() []string // completion candidates computed at completion time
+	completeWith   completeFunc    // completion candidates for a prefix, computed at completion time
+	pathKind       string          // "file" or "dir" if the flag's values complete as paths
+	pathGlobs      []string        // patterns that file completion is limited to
+	group          string          // title of the usage section the flag belongs to
+	configPath     string          // dotted path of the flag's value in a config file
+	layout         string          // time.Parse layout of a time flag, empty for other flags
+	required       bool            // whether the flag must be set
+	persistent     bool            // whether subcommands inherit the flag
+	negatable      bool            // whether a --no-<name> form is registered
+	optional       T               // value of the flag when given without one
+	hasOptional    bool            // whether the flag may be given without a value
+	minVal, maxVal *T              // range allowed by Min and Max, if any
+	minExclusive   bool            // whether minVal itself is rejected, as for Positive
+	env            string          // environment variable the flag is read from
+	requires       []string        // flags that must be set when this one is
+	hidden         bool            // whether the flag is left out of usage and completions
+	deprecated     string          // deprecation message, if the flag is deprecated
+	renamedFrom    []string        // former names still accepted, with a warning
+	aliases        []string        // short and long aliases beyond the first short alias
+	placeholder    string          // name for the value in usage, in place of its type
+	defaultText    string          // default as shown in usage, in place of the value
+	hideDefault    bool            // whether usage leaves out the default
+	initial        []string        // values the flag was built with, before parsing
+	noConfig       bool            // whether WriteConfig leaves the flag out, like --help
+	rebuild        rebuildFunc     // defines a built-in flag like --help on a clone
 }
 
-// Alias sets a short flag (eg: -f) alias for the standard long flag.
-func (self *FluentFlag[T]) Alias(alias rune) *FluentFlag[T] {
-	self.alias = alias
+// Alias sets a short flag (eg: -f) alias for the standard long flag. Calling
+// it again, or with several runes, adds further aliases (eg: -o and -O).
+func (self *FluentFlag[T]) Alias(aliases ...rune) *FluentFlag[T] {
+	for _, alias := range aliases {
+		if self.alias == 0 {
+			self.alias = alias
+		} else {
+			self.aliases = append(self.aliases, string(alias))
+		}
+	}
+	return self
+}
+
+// LongAlias adds a long name for the flag (eg: --colour for --color), bound to
+// the same value and listed after the flag's name in usage.
+func (self *FluentFlag[T]) LongAlias(names ...string) *FluentFlag[T] {
+	self.aliases = append(self.aliases, names...)
+	return self
+}
+
+// addAliases adds aliases given by name, as short aliases if they are one
+// character long and as long aliases otherwise.
+func (self *FluentFlag[T]) addAliases(names ...string) {
+	for _, name := range names {
+		if isShortName(name) {
+			r, _ := utf8.DecodeRuneInString(name)
+			self.Alias(r)
+		} else {
+			self.LongAlias(name)
+		}
+	}
+}
+
+// DefaultText sets how the default is shown in usage, as in
+// "(default $HOME/.config/app)", without changing the default value. It suits
+// defaults computed at run time.
+func (self *FluentFlag[T]) DefaultText(text string) *FluentFlag[T] {
+	self.defaultText = text
+	return self
+}
+
+// HideDefault leaves the default out of usage and generated docs, for
+// defaults that are sensitive, like tokens, or noisy, like long paths.
+func (self *FluentFlag[T]) HideDefault() *FluentFlag[T] {
+	self.hideDefault = true
+	return self
+}
+
+// Placeholder sets the name shown for the flag's value in usage, as in
+// "-o, --output FILE", in place of its type.
+func (self *FluentFlag[T]) Placeholder(name string) *FluentFlag[T] {
+	self.placeholder = name
 	return self
 }
 
@@ -64,49 +309,41 @@ func (self *FluentFlag[T]) Default(defaultVal T) *FluentFlag[T] {
 	return self
 }
 
+// OptionalValue lets the flag be given to Parse without a value, as in
+// --color, to set it to v, while --color=never still sets an explicit value.
+// A value in the next argument is not consumed, so it must be attached with
+// "=". Usage shows the flag as --color string[="auto"].
+func (self *FluentFlag[T]) OptionalValue(v T) *FluentFlag[T] {
+	self.optional = v
+	self.hasOptional = true
+	return self
+}
+
+// optionalValue returns the value used when the flag is given without one.
+func (self *FluentFlag[T]) optionalValue() (string, bool) {
+	return self.format(self.optional), self.hasOptional
+}
+
 // Build registers the flag with the standard library flag package using the provided pointer.
 func (self *FluentFlag[T]) Build(ptr *T) {
-	self.builder.flagsBuilt = append(self.builder.flagsBuilt, self)
-	self.builder.building = nil
+	self.builder.fail(self.TryBuild(ptr))
+}
+
+// TryBuild is like Build, but returns an error for a misconfigured flag, such
+// as one whose name is already defined, rather than panicking.
+func (self *FluentFlag[T]) TryBuild(ptr *T) error {
 	switch any(self.defaultVal).(type) {
-	case bool:
-		self.builder.flagSet.BoolVar(any(ptr).(*bool), self.name, any(self.defaultVal).(bool), self.usage)
-		if self.alias != 0 {
-			self.builder.flagSet.BoolVar(any(ptr).(*bool), string(self.alias), any(self.defaultVal).(bool), "")
-		}
-	case int:
-		self.builder.flagSet.IntVar(any(ptr).(*int), self.name, any(self.defaultVal).(int), self.usage)
-		if self.alias != 0 {
-			self.builder.flagSet.IntVar(any(ptr).(*int), string(self.alias), any(self.defaultVal).(int), "")
-		}
-	case int64:
-		self.builder.flagSet.Int64Var(any(ptr).(*int64), self.name, any(self.defaultVal).(int64), self.usage)
-		if self.alias != 0 {
-			self.builder.flagSet.Int64Var(any(ptr).(*int64), string(self.alias), any(self.defaultVal).(int64), "")
-		}
-	case float64:
-		self.builder.flagSet.Float64Var(any(ptr).(*float64), self.name, any(self.defaultVal).(float64), self.usage)
-		if self.alias != 0 {
-			self.builder.flagSet.Float64Var(any(ptr).(*float64), string(self.alias), any(self.defaultVal).(float64), "")
-		}
-	case string:
-		self.builder.flagSet.StringVar(any(ptr).(*string), self.name, any(self.defaultVal).(string), self.usage)
-		if self.alias != 0 {
-			self.builder.flagSet.StringVar(any(ptr).(*string), string(self.alias), any(self.defaultVal).(string), "")
-		}
-	case uint:
-		self.builder.flagSet.UintVar(any(ptr).(*uint), self.name, any(self.defaultVal).(uint), self.usage)
-		if self.alias != 0 {
-			self.builder.flagSet.UintVar(any(ptr).(*uint), string(self.alias), any(self.defaultVal).(uint), "")
-		}
-	case uint64:
-		self.builder.flagSet.Uint64Var(any(ptr).(*uint64), self.name, any(self.defaultVal).(uint64), self.usage)
-		if self.alias != 0 {
-			self.builder.flagSet.Uint64Var(any(ptr).(*uint64), string(self.alias), any(self.defaultVal).(uint64), "")
-		}
+	case bool, int, int64, float64, string, uint, uint64, time.Duration:
 	default:
-		panic("unsupported flag type")
+		self.builder.building = nil
+		return fmt.Errorf("fluentflag: unsupported flag type %T (--%s)", self.defaultVal, self.name)
 	}
+	if err := self.checkSingle(); err != nil {
+		self.builder.building = nil
+		return err
+	}
+	*ptr = self.defaultVal
+	return self.register(&flagValue[T]{flag: self, target: ptr})
 }
 
 // BuildVar registers the flag and returns a pointer to the storage variable.
@@ -119,67 +356,350 @@ func (self *FluentFlag[T]) BuildVar() *T {
 // BuildSlice registers a flag that accumulates values into a slice of T.
 // Returns a pointer to the slice ([]T) that the user can use directly.
 func (self *FluentFlag[T]) BuildSlice() *[]T {
-	self.builder.flagsBuilt = append(self.builder.flagsBuilt, self)
-	self.builder.building = nil
 	slice := new([]T) // allocate on heap
 	*slice = []T{}
-	val := &accumValues[T]{target: slice}
+	self.builder.fail(self.register(&accumValues[T]{flag: self, target: slice}))
+	return slice
+}
+
+// register binds the flag to val and registers it and its aliases with the
+// flag set, unless one of its names is already defined.
+func (self *FluentFlag[T]) register(val fluentValue) error {
+	self.builder.building = nil
+	if err := self.checkNames(); err != nil {
+		return err
+	}
+	self.builder.flagsBuilt = append(self.builder.flagsBuilt, self)
+	self.value = val
+	self.initial = val.list()
 	self.builder.flagSet.Var(val, self.name, self.usage)
-	if self.alias != 0 {
-		self.builder.flagSet.Var(val, string(self.alias), "")
+	for _, alias := range self.names()[1:] {
+		self.builder.flagSet.Var(&aliasValue{Value: val, of: self.name}, alias, "")
 	}
-	return slice
+	if neg := self.negatedName(); neg != "" {
+		self.builder.flagSet.Var(&aliasValue{Value: &negatedValue{target: val}, of: self.name}, neg, "")
+	}
+	for _, old := range self.renamedFrom {
+		self.builder.flagSet.Var(&aliasValue{Value: val, of: self.name}, old, "")
+	}
+	return nil
+}
+
+// checkNames returns an error if any of the flag's names, aliases, negated
+// form, or former names is given twice or is already defined on the flag set,
+// naming the flag that defined it first. Both definitions are shown with their
+// usage text, to tell them apart when they share a name.
+func (self *FluentFlag[T]) checkNames() error {
+	names := append(self.names(), self.oldNames()...)
+	if neg := self.negatedName(); neg != "" {
+		names = append(names, neg)
+	}
+	seen := map[string]bool{}
+	for _, name := range names {
+		if seen[name] {
+			return fmt.Errorf("fluentflag: %s is given twice for %s", dashed(name), dashed(self.name))
+		}
+		seen[name] = true
+		if self.builder.flagSet.Lookup(name) == nil {
+			continue
+		}
+		for _, f := range self.builder.flagsBuilt {
+			if f == builtFlag(self) {
+				return fmt.Errorf("fluentflag: flag %s is already built", dashed(self.name))
+			}
+			if hasName(f, name) {
+				return fmt.Errorf("fluentflag: %s is already defined by %s", self.describeName(name), describeFlag(f.names()[0], f.flagUsage()))
+			}
+		}
+		return fmt.Errorf("fluentflag: %s is already defined on the flag set", self.describeName(name))
+	}
+	return nil
+}
+
+// describeName names the flag for an error about one of its names, like
+// `-o for --output "output file"`, or just `--output "output file"` for the
+// flag's own name.
+func (self *FluentFlag[T]) describeName(name string) string {
+	if name == self.name {
+		return describeFlag(self.name, self.usage)
+	}
+	return dashed(name) + " for " + describeFlag(self.name, self.usage)
+}
+
+// describeFlag returns the flag's name followed by its quoted usage text, if
+// it has any.
+func describeFlag(name, usage string) string {
+	if usage == "" {
+		return dashed(name)
+	}
+	return dashed(name) + " " + strconv.Quote(usage)
 }
 
 // FluentFlag provides usage/help string for the option.
 func (self *FluentFlag[T]) Usage() string {
+	return self.usageLine(0)
+}
+
+// usageLine renders the flag's usage with its description wrapped to width
+// columns, or unwrapped if width is zero.
+func (self *FluentFlag[T]) usageLine(width int) string {
+	typeStr := self.typeString()
+	choices := self.choiceStrings()
+
+	theme := self.builder.activeTheme
+	if typeStr != "" {
+		typeStr = " " + theme.typ(typeStr[1:])
+	}
+	def := ""
+	if str := self.defaultString(); str != "" {
+		def = " (default " + theme.defaultValue(str) + ")"
+	}
+
+	desc := self.usage
+	if len(choices) > 0 && !self.builder.choicesInType {
+		desc += " (choices: " + strings.Join(choices, ", ") + ")"
+	}
+	if r := self.rangeString(); r != "" {
+		desc += " (" + r + ")"
+	}
+	if self.required {
+		desc += " (required)"
+	}
+	if self.deprecated != "" {
+		desc += " (deprecated: " + self.deprecated + ")"
+	}
+	if len(self.requires) > 0 {
+		desc += " (requires " + flagList(self.requires) + ")"
+	}
+	for _, note := range self.builder.constraintNotes(self.name) {
+		desc += " (" + note + ")"
+	}
+	if env := self.envVar(); env != "" {
+		def += " [env: " + env + "]"
+	}
+
+	var shorts, longs []string
+	for i, name := range self.names() {
+		if isShortName(name) {
+			shorts = append(shorts, theme.name("-"+name))
+			continue
+		}
+		if i == 0 && self.negatable {
+			name = "[no-]" + name
+		}
+		longs = append(longs, theme.name("--"+name))
+	}
+	names := strings.Join(append(shorts, longs...), ", ")
+	if len(shorts) == 0 {
+		names = "    " + names
+	}
+	line := fmt.Sprintf("%s%s", names, typeStr)
+	return formatUsageLine(line, desc+def, width)
+}
+
+// typeString returns the value type as shown after the flag's names in
+// usage, like " string" or the placeholder, or "" for a bool flag.
+func (self *FluentFlag[T]) typeString() string {
 	typeStr := fmt.Sprintf("%T", self.defaultVal)
 	if dot := strings.LastIndex(typeStr, "."); dot != -1 {
 		typeStr = typeStr[dot+1:]
 	}
-	if typeStr == "bool" {
+	if self.placeholder != "" {
+		typeStr = " " + self.placeholder
+	} else if choices := self.choiceStrings(); len(choices) > 0 && self.builder.choicesInType {
+		typeStr = " {" + strings.Join(choices, "|") + "}"
+	} else if typeStr == "bool" {
 		typeStr = ""
+	} else if self.layout != "" {
+		typeStr = " time"
+	} else if typeStr == "Duration" {
+		typeStr = " duration"
 	} else {
 		typeStr = " " + typeStr
 	}
+	if self.hasOptional {
+		typeStr += "[=" + self.quote(self.optional) + "]"
+	}
+	return typeStr
+}
 
-	def := ""
+// defaultString returns the default value as shown in usage, or "" when it
+// is hidden, or is the zero value and SetAlwaysShowDefault is off.
+func (self *FluentFlag[T]) defaultString() string {
+	if self.hideDefault {
+		return ""
+	} else if self.defaultText != "" {
+		return self.defaultText
+	}
 	var zero T
-	switch val := any(self.defaultVal).(type) {
-	case bool:
-		if val {
-			def = " (default true)"
-		}
-	case string:
-		if val != "" {
-			def = fmt.Sprintf(" (default %q)", val)
-		}
-	default:
-		if self.defaultVal != zero {
-			def = fmt.Sprintf(" (default %v)", val)
-		}
+	if self.defaultVal == zero && !self.builder.alwaysShowDefault {
+		return ""
+	}
+	return self.quote(self.defaultVal)
+}
+
+// quote returns v as shown in usage, with strings other than times quoted.
+func (self *FluentFlag[T]) quote(v T) string {
+	if s, ok := any(v).(string); ok && self.layout == "" {
+		return strconv.Quote(s)
 	}
+	return self.format(v)
+}
+
+// format returns v as shown in usage and listings.
+func (self *FluentFlag[T]) format(v T) string {
+	return fmt.Sprint(v)
+}
+
+// flagUsage returns the usage description of the flag.
+func (self *FluentFlag[T]) flagUsage() string {
+	return self.usage
+}
+
+// set sets the flag's value from s, as if given on the command line.
+func (self *FluentFlag[T]) set(s string) error {
+	return self.value.Set(s)
+}
 
-	names := ""
+// names returns the long name followed by the short alias, if any.
+func (self *FluentFlag[T]) names() []string {
+	names := []string{self.name}
 	if self.alias != 0 {
-		names = fmt.Sprintf("-%c, --%s", self.alias, self.name)
-	} else {
-		names = fmt.Sprintf("    --%s", self.name)
+		names = append(names, string(self.alias))
 	}
-	line := fmt.Sprintf("%s%s", names, typeStr)
-	const maxLen = 25
-	if len(line) >= maxLen {
-		return fmt.Sprintf("  %-*s\n  %-*s%s%s", maxLen, line, maxLen, "", self.usage, def)
+	return append(names, self.aliases...)
+}
+
+// isShortName reports whether name is a single-character flag name, written
+// with one dash.
+func isShortName(name string) bool {
+	return utf8.RuneCountInString(name) == 1
+}
+
+// dashed returns name as written on the command line, like -v or --verbose.
+func dashed(name string) string {
+	if isShortName(name) {
+		return "-" + name
 	}
-	return fmt.Sprintf("  %-*s%s%s", maxLen, line, self.usage, def)
+	return "--" + name
+}
+
+// values returns the current value(s) of a built flag formatted as strings.
+func (self *FluentFlag[T]) values() []string {
+	return self.value.list()
+}
+
+// goType returns the Go type of the flag's storage (eg: "int" or "[]string").
+func (self *FluentFlag[T]) goType() string {
+	return self.value.goType()
+}
+
+// takesValue reports whether the flag expects a value on the command line.
+func (self *FluentFlag[T]) takesValue() bool {
+	return !isBoolValue(self.value)
+}
+
+// boundValue returns the flag.Value the flag was built with.
+func (self *FluentFlag[T]) boundValue() fluentValue {
+	return self.value
+}
+
+// isList reports whether the flag collects a value each time it is given.
+func (self *FluentFlag[T]) isList() bool {
+	return isListValue(self.value)
+}
+
+// builtFlag is the type-erased view of a FluentFlag used by FlagBuilder.
+type builtFlag interface {
+	Usage() string
+	usageLine(width int) string
+	names() []string
+	flagUsage() string
+	goType() string
+	takesValue() bool
+	isList() bool
+	isPersistent() bool
+	boundValue() fluentValue
+	markSet()
+	negatedName() string
+	values() []string
+	toArgs() []string
+	completions(prefix string) []string
+	hasDynamicChoices() bool
+	pathCompletion() (kind string, globs []string)
+	groupTitle() string
+	isHidden() bool
+	skipsConfig() bool
+	usageFlag(width int) UsageFlag
+	defaultString() string
+	typeString() string
+	deprecation() string
+	oldNames() []string
+	configKey() string
+	envVar() string
+	set(s string) error
+	scale(factor float64) error
+	setZero()
+	reset()
+	cloneTo(b *FlagBuilder) error
+	addTo(b *FlagBuilder, prefix string) error
+	validate() error
 }
 
 // FlagBuilder provides a fluent API for building and registering command-line flags.
 type FlagBuilder struct {
 	flagSet    *flag.FlagSet
-	flagsBuilt []any     // store built flags
-	building   any       // store the currently building flag
-	output     io.Writer // optional output writer for usage
+	flagsBuilt []builtFlag // store built flags
+	building   any         // store the currently building flag
+	output     io.Writer   // optional output writer for usage
+	helpOutput io.Writer   // optional output writer for requested help
+
+	parseTimeout  time.Duration     // limit for Parse, if any
+	abandoned     chan error        // result of a parse ParseContext gave up on, until received
+	choicesInType bool              // render choices in place of the type label
+	postParse     []parseHook       // hooks run after a successful Parse
+	groups        []*usageGroup     // usage sections in order of declaration
+	usageWidth    int               // width to wrap usage text to
+	exitFunc      func(int)         // exits the program; os.Exit if nil
+	requireUsage  bool              // fail when a flag is declared without usage text
+	errorHandling ErrorHandling     // how misconfiguration is reported
+	configErrs    []error           // misconfiguration recorded under ReturnErrors
+	setErr        *FlagError        // the last value a flag rejected while parsing
+	abbreviations bool              // accept unambiguous prefixes of long flags
+	reserved      map[string]string // abbreviations pinned to a flag
+
+	alwaysShowDefault  bool                     // show zero-valued defaults in usage
+	collectAssignments bool                     // collect KEY=VALUE arguments during Parse
+	assignments        map[string]string        // KEY=VALUE arguments from the last Parse
+	rest               []string                 // non-flag arguments from the last Parse
+	config             map[string]any           // values loaded from a config file
+	decoders           map[string]ConfigDecoder // config decoders by format, added to the defaults
+	sources            map[string]Source        // where flags not set on the command line got their value
+	sourceOrder        []Source                 // sources Resolve applies, highest precedence first
+	envPrefix          string                   // prefix of the environment variables flags are read from
+	envSeparator       string                   // separator of list values in environment variables
+	dotenv             map[string]string        // variables loaded from .env files
+	commands           []*Command               // subcommands, listed in usage
+	parent             *FlagBuilder             // builder of the parent command, whose persistent flags are inherited
+	setOnSubcommand    map[string]bool          // persistent flags set on a subcommand's command line
+	positionals        []*PositionalArg         // named positional arguments, in order
+	argCount           int                      // number of non-flag arguments in the last Parse
+	minArgs, maxArgs   int                      // bounds on the number of non-flag arguments
+	limitArgs          bool                     // whether maxArgs applies
+	stopOnNonFlag      bool                     // whether parsing stops at the first non-flag argument
+	interspersed       bool                     // whether flags may follow positional arguments
+	unknownMode        UnknownFlagMode          // how Parse handles undefined flags
+	unknown            []string                 // undefined flags collected by the last Parse
+	usageTemplate      *template.Template       // renders the usage text, if set
+	theme              *Theme                   // colors for usage text, if set
+	activeTheme        *Theme                   // colors for the usage being written, if enabled
+	sortFlags          bool                     // list flags alphabetically in usage
+	examples           []usageExample           // example command lines shown in usage
+	usageHeader        string                   // text printed at the top of usage
+	usageFooter        string                   // text printed at the bottom of usage
+	helpEnabled        bool                     // whether -h, --help is registered, for subcommands too
+	warnedRenames      map[string]bool          // former flag names already warned about
+	constraints        []flagConstraint         // constraints across flags, checked by Validate
 }
 
 // SetOutput sets the output writer for usage/help text.
@@ -187,17 +707,102 @@ func (b *FlagBuilder) SetOutput(w io.Writer) {
 	b.output = w
 }
 
+// SetHelpOutput sets the output writer for usage requested with -h or --help
+// during Parse. It defaults to os.Stdout so help can be piped, while usage
+// printed for parse errors goes to the SetOutput writer (os.Stderr by default).
+func (b *FlagBuilder) SetHelpOutput(w io.Writer) {
+	b.helpOutput = w
+}
+
+// outputWriter returns the writer for usage and diagnostics.
+func (b *FlagBuilder) outputWriter() io.Writer {
+	if b.output != nil {
+		return b.output
+	}
+	return os.Stderr
+}
+
+// helpWriter returns the writer for requested help.
+func (b *FlagBuilder) helpWriter() io.Writer {
+	if b.helpOutput != nil {
+		return b.helpOutput
+	}
+	return os.Stdout
+}
+
+// RequireUsageText makes declaring a flag with empty usage text fail, so that
+// every flag is documented. It is off by default.
+func (b *FlagBuilder) RequireUsageText(enabled bool) {
+	b.requireUsage = enabled
+}
+
+// SetAlwaysShowDefault shows every flag's default in usage, including zero
+// values like 0, false, and "" that are omitted by default.
+func (b *FlagBuilder) SetAlwaysShowDefault(enabled bool) {
+	b.alwaysShowDefault = enabled
+}
+
+// SetChoicesInType renders a flag's choices in place of its type label in
+// usage (eg: --level {debug|info|warn}) rather than after its description.
+func (b *FlagBuilder) SetChoicesInType(enabled bool) {
+	b.choicesInType = enabled
+}
+
+// ErrorHandling defines how a FlagBuilder reports misconfiguration, such as a
+// flag name defined twice or a validator applied to the wrong type.
+type ErrorHandling int
+
+const (
+	// PanicOnError panics with the error, which suits programs whose flags
+	// are fixed at compile time. It is the default.
+	PanicOnError ErrorHandling = iota
+	// ReturnErrors records the error, skips the misconfigured definition, and
+	// makes Parse return the error. Err reports it before parsing.
+	ReturnErrors
+)
+
+// SetErrorHandling sets how misconfiguration is reported. Libraries that
+// build flags from user-supplied definitions should use ReturnErrors.
+func (b *FlagBuilder) SetErrorHandling(h ErrorHandling) {
+	b.errorHandling = h
+}
+
+// Err returns the misconfiguration recorded under ReturnErrors, or nil. If
+// there is more than one problem, the error lists each on its own line.
+func (b *FlagBuilder) Err() error {
+	return ValidationErrors(b.configErrs).err()
+}
+
+// fail reports a misconfiguration according to the error handling, if err is
+// not nil.
+func (b *FlagBuilder) fail(err error) {
+	if err == nil {
+		return
+	}
+	if b.errorHandling == ReturnErrors {
+		b.configErrs = append(b.configErrs, err)
+		return
+	}
+	panic(err.Error())
+}
+
 // NewFlagBuilder creates a new FlagBuilder using flag.CommandLine.
 func NewFlagBuilder() *FlagBuilder {
-	return &FlagBuilder{flagSet: flag.CommandLine}
+	return NewFlagBuilderWithSet(flag.CommandLine)
 }
 
-// NewFlagBuilderForSet creates a new FlagBuilder with a custom FlagSet.
+// NewFlagBuilderForSet creates a new FlagBuilder with a custom FlagSet. The
+// flag set's Usage is replaced with one that prints the builder's help, so
+// parsing it directly shows the same help as Parse.
 func NewFlagBuilderWithSet(flagSet *flag.FlagSet) *FlagBuilder {
 	if flagSet == nil {
 		flagSet = flag.CommandLine
 	}
-	return &FlagBuilder{flagSet: flagSet}
+	b := &FlagBuilder{flagSet: flagSet}
+	flagSet.Usage = func() {
+		b.printHelp(b.outputWriter())
+	}
+	return b
 }
 
 // BoolFlag defines a boolean flag
@@ -235,10 +840,27 @@ func (self *FlagBuilder) Uint64Flag(name, usage string) *FluentFlag[uint64] {
 	return newFlag[uint64](self, name, usage)
 }
 
+// TimeFlag defines a time flag, parsed as RFC 3339 unless a Layout is set.
+// Its Default is given as text in the layout, and it is built with BuildTime
+// or BuildTimes.
+func (self *FlagBuilder) TimeFlag(name, usage string) *FluentFlag[string] {
+	f := newFlag[string](self, name, usage)
+	f.layout = time.RFC3339
+	return f
+}
+
+// DurationFlag defines a time.Duration flag, parsed with time.ParseDuration
+func (self *FlagBuilder) DurationFlag(name, usage string) *FluentFlag[time.Duration] {
+	return newFlag[time.Duration](self, name, usage)
+}
+
 // NewFlagBuilder creates a new FlagBuilder for the given flag name and usage description.
 func newFlag[T FlagType](builder *FlagBuilder, name, usage string) *FluentFlag[T] {
 	if builder.building != nil {
-		panic("fluentflag: previous flag not built (call Build, BuildVar, or BuildSlice)")
+		builder.fail(errors.New("fluentflag: previous flag not built (call Build, BuildVar, or BuildSlice)"))
+	}
+	if builder.requireUsage && usage == "" {
+		builder.fail(fmt.Errorf("fluentflag: flag --%s has no usage text", name))
 	}
 	flag := &FluentFlag[T]{
 		builder: builder,
@@ -249,6 +871,35 @@ func newFlag[T FlagType](builder *FlagBuilder, name, usage string) *FluentFlag[T
 	return flag
 }
 
+// parse applies the flag's transforms to s, converts it to T, and runs the
+// flag's validators on the result.
+func (self *FluentFlag[T]) parse(s string) (T, error) {
+	raw := s
+	for _, transform := range self.transforms {
+		var err error
+		if s, err = transform(self, s); err != nil {
+			var zero T
+			return zero, self.rejected(ErrInvalidValue, raw, err)
+		}
+	}
+	var v T
+	var err error
+	if len(self.parsers) > 0 {
+		v, err = self.parseAny(s)
+	} else {
+		v, err = parse[T](s)
+	}
+	if err != nil {
+		return v, self.rejected(ErrInvalidValue, raw, err)
+	}
+	for _, check := range self.checks {
+		if err := check(self, v); err != nil {
+			return v, self.rejected(ErrConstraintViolated, raw, err)
+		}
+	}
+	return v, nil
+}
+
 // Parse turns a string into the data type for a flag
 func parse[T FlagType](s string) (T, error) {
 	var v T
@@ -259,16 +910,19 @@ func parse[T FlagType](s string) (T, error) {
 	case string:
 		return any(s).(T), nil
 	case int:
-		v, err := strconv.Atoi(s)
-		return any(v).(T), err
+		v, err := strconv.ParseInt(s, 10, strconv.IntSize)
+		return any(int(v)).(T), err
 	case int64:
 		v, err := strconv.ParseInt(s, 10, 64)
 		return any(v).(T), err
+	case time.Duration:
+		v, err := time.ParseDuration(s)
+		return any(v).(T), err
 	case float64:
 		v, err := strconv.ParseFloat(s, 64)
 		return any(v).(T), err
 	case uint:
-		v, err := strconv.ParseUint(s, 10, 0)
+		v, err := strconv.ParseUint(s, 10, strconv.IntSize)
 		return any(uint(v)).(T), err
 	case uint64:
 		v, err := strconv.ParseUint(s, 10, 64)
@@ -278,15 +932,8 @@ func parse[T FlagType](s string) (T, error) {
 	}
 }
 
-// PrintUsage prints usage for all built flags.
+// PrintUsage prints usage for all built flags, without the "Usage:" line
+// printed for -h and parse errors.
 func (b *FlagBuilder) PrintUsage() {
-	w := b.output
-	if w == nil {
-		w = os.Stderr
-	}
-	for _, f := range b.flagsBuilt {
-		if u, ok := f.(interface{ Usage() string }); ok {
-			fmt.Fprintln(w, u.Usage())
-		}
-	}
+	b.printUsage(b.outputWriter())
 }
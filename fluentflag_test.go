@@ -5,10 +5,12 @@ package fluentflag
 import (
 	"flag"
 	"fmt"
+	"io"
 	"os"
 	"reflect"
 	"strings"
 	"testing"
+	"time"
 )
 
 func resetFlags() {
@@ -78,6 +80,59 @@ func TestFlagBuilder_FluentAPI(t *testing.T) {
 	}
 }
 
+func TestFlagBuilder_MultipleAliases(t *testing.T) {
+	b := NewFlagBuilderWithSet(flag.NewFlagSet("test", flag.ContinueOnError))
+	f := b.StringFlag("output", "output file").Alias('o').Alias('O', 'p')
+	out := f.BuildVar()
+	for _, arg := range []string{"-o=a", "-O=b", "-p=c", "--output=d"} {
+		if _, err := b.Parse([]string{arg}); err != nil {
+			t.Fatalf("%s: unexpected error: %v", arg, err)
+		}
+		if want := arg[strings.Index(arg, "=")+1:]; *out != want {
+			t.Errorf("%s: expected %q, got %q", arg, want, *out)
+		}
+	}
+	if got, want := f.Usage(), "  -o, -O, -p, --output string\n"+strings.Repeat(" ", 27)+"output file"; got != want {
+		t.Errorf("expected usage %q, got %q", want, got)
+	}
+}
+
+func TestFlagBuilder_LongAlias(t *testing.T) {
+	b := NewFlagBuilderWithSet(flag.NewFlagSet("test", flag.ContinueOnError))
+	f := b.StringFlag("color", "when to color").LongAlias("colour").LongAlias("colours")
+	color := f.BuildVar()
+	if _, err := b.Parse([]string{"--colour=never"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if *color != "never" {
+		t.Errorf("expected %q, got %q", "never", *color)
+	}
+	if got, want := f.Usage(), "      --color, --colour, --colours string\n"+strings.Repeat(" ", 27)+"when to color"; got != want {
+		t.Errorf("expected usage %q, got %q", want, got)
+	}
+	if got, want := b.StringFlag("x", "").LongAlias("ex").Usage(), "  -x, --ex string          "; got != want {
+		t.Errorf("expected usage %q, got %q", want, got)
+	}
+}
+
+func TestFlagBuilder_ShortOnly(t *testing.T) {
+	b := NewFlagBuilderWithSet(flag.NewFlagSet("test", flag.ContinueOnError))
+	f := b.BoolFlag("x", "trace commands")
+	x := f.BuildVar()
+	if _, err := b.Parse([]string{"-x"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !*x {
+		t.Error("expected -x to be set")
+	}
+	if got, want := f.Usage(), "  -x                       trace commands"; got != want {
+		t.Errorf("expected usage %q, got %q", want, got)
+	}
+	if got, want := b.IntFlag("n", "count").Alias('N').Usage(), "  -n, -N int               count"; got != want {
+		t.Errorf("expected usage %q, got %q", want, got)
+	}
+}
+
 func TestFlagBuilder_Build_Bool(t *testing.T) {
 	resetFlags()
 	var val bool
@@ -160,6 +215,18 @@ func TestParse_InvalidValue(t *testing.T) {
 	}
 }
 
+func TestParse_DecimalIntegers(t *testing.T) {
+	if v, err := parse[int]("08"); err != nil || v != 8 {
+		t.Errorf("expected 08 to parse as 8, got %v, %v", v, err)
+	}
+	if v, err := parse[uint64]("010"); err != nil || v != 10 {
+		t.Errorf("expected 010 to parse as 10, got %v, %v", v, err)
+	}
+	if _, err := parse[int64]("0x10"); err == nil {
+		t.Error("expected error for hex int")
+	}
+}
+
 func TestNewFlagBuilderWithSet(t *testing.T) {
 	resetFlags()
 	customSet := flag.NewFlagSet("custom", flag.ContinueOnError)
@@ -276,6 +343,93 @@ func TestFlagBuilder_Build_Uint64(t *testing.T) {
 	}
 }
 
+func TestFlagBuilder_Build_Duration(t *testing.T) {
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+	b := NewFlagBuilderWithSet(fs)
+	var timeout time.Duration
+	f := b.DurationFlag("timeout", "request timeout").Default(30 * time.Second)
+	f.Build(&timeout)
+	if timeout != 30*time.Second {
+		t.Errorf("expected default 30s, got %v", timeout)
+	}
+	if got := f.Usage(); got != "      --timeout duration   request timeout (default 30s)" {
+		t.Errorf("unexpected usage: %q", got)
+	}
+	if err := fs.Parse([]string{"--timeout=1m30s"}); err != nil || timeout != 90*time.Second {
+		t.Errorf("expected 1m30s, got %v (err %v)", timeout, err)
+	}
+	if err := fs.Parse([]string{"--timeout=30"}); err == nil {
+		t.Error("expected error for a duration without a unit")
+	}
+}
+
+func TestFlagBuilder_TimeFlag(t *testing.T) {
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+	b := NewFlagBuilderWithSet(fs)
+	since := b.TimeFlag("since", "start time").Alias('s').BuildTime()
+	until := b.TimeFlag("until", "end time").Layout("2006-01-02").Default("2025-12-31").BuildTime()
+	days := b.TimeFlag("day", "days to report").Layout("2006-01-02").BuildTimes()
+	err := fs.Parse([]string{"-s", "2025-03-01T12:00:00Z", "--day=2025-03-01", "--day=2025-03-02"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC); !since.Equal(want) {
+		t.Errorf("expected %v, got %v", want, *since)
+	}
+	if want := time.Date(2025, 12, 31, 0, 0, 0, 0, time.UTC); !until.Equal(want) {
+		t.Errorf("expected default %v, got %v", want, *until)
+	}
+	if len(*days) != 2 || (*days)[1].Day() != 2 {
+		t.Errorf("unexpected days: %v", *days)
+	}
+	if got := b.lookup("day").values(); !reflect.DeepEqual(got, []string{"2025-03-01", "2025-03-02"}) {
+		t.Errorf("expected values in layout, got %v", got)
+	}
+	if err := fs.Parse([]string{"--day=03/01/2025"}); err == nil {
+		t.Error("expected error for a time not matching the layout")
+	}
+	b.Reset()
+	if !since.IsZero() || until.Day() != 31 || len(*days) != 0 {
+		t.Errorf("expected defaults after Reset, got %v %v %v", *since, *until, *days)
+	}
+	if got, err := Get[time.Time](b, "until"); err != nil || !got.Equal(*until) {
+		t.Errorf("expected Get to return the time, got %v, %v", got, err)
+	}
+}
+
+func TestFlagBuilder_TimeFlagUsage(t *testing.T) {
+	b := NewFlagBuilderWithSet(flag.NewFlagSet("test", flag.ContinueOnError))
+	b.TimeFlag("until", "end time").Layout("2006-01-02").Default("2025-12-31").BuildTime()
+	got := b.lookup("until").usageLine(0)
+	if !strings.Contains(got, "--until time") || !strings.Contains(got, "(default 2025-12-31)") {
+		t.Errorf("expected a time type and unquoted default, got %q", got)
+	}
+}
+
+func TestFlagBuilder_BuildTimeErrors(t *testing.T) {
+	b := NewFlagBuilderWithSet(flag.NewFlagSet("test", flag.ContinueOnError))
+	b.SetErrorHandling(ReturnErrors)
+	b.StringFlag("since", "start time").BuildTime()
+	b.TimeFlag("until", "end time").Default("tomorrow").BuildTime()
+	want := "fluentflag: BuildTime requires a time flag (--since)\n" +
+		"fluentflag: --until has an invalid default \"tomorrow\": "
+	if err := b.Err(); err == nil || !strings.HasPrefix(err.Error(), want) {
+		t.Errorf("expected errors starting %q, got %v", want, err)
+	}
+}
+
+func TestFlagBuilder_LayoutNonTimePanics(t *testing.T) {
+	defer func() {
+		if r := recover(); r == nil {
+			t.Error("expected panic for Layout on a non-time flag")
+		}
+	}()
+	b := NewFlagBuilderWithSet(flag.NewFlagSet("test", flag.ContinueOnError))
+	b.StringFlag("since", "start time").Layout(time.RFC3339)
+}
+
 func TestFlagBuilder_UsageString(t *testing.T) {
 	resetFlags()
 	b := NewFlagBuilder()
@@ -479,3 +633,169 @@ func TestFlagBuilder_UsageFormatting(t *testing.T) {
 		t.Errorf("Usage output mismatch.\nGot:\n%s\nWant:\n%s", actual, expected)
 	}
 }
+
+func TestFlagBuilder_SetChoicesInType(t *testing.T) {
+	tests := []struct {
+		name     string
+		inType   bool
+		expected string
+	}{
+		{"in type", true, `  -l, --level {debug|info|warn}
+                           Log level (default "info")`},
+		{"appended", false, `  -l, --level string       Log level (choices: debug, info, warn) (default "info")`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b := NewFlagBuilderWithSet(flag.NewFlagSet("test", flag.ContinueOnError))
+			b.SetChoicesInType(tt.inType)
+			f := b.StringFlag("level", "Log level").Alias('l').Default("info").Choices("debug", "info", "warn")
+			f.BuildVar()
+			if actual := f.Usage(); actual != tt.expected {
+				t.Errorf("Usage mismatch.\nGot:\n%s\nWant:\n%s", actual, tt.expected)
+			}
+		})
+	}
+}
+
+func TestFlagBuilder_RequireUsageText(t *testing.T) {
+	tests := []struct {
+		name      string
+		enabled   bool
+		wantPanic bool
+	}{
+		{"enabled", true, true},
+		{"disabled", false, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b := NewFlagBuilderWithSet(flag.NewFlagSet("test", flag.ContinueOnError))
+			b.RequireUsageText(tt.enabled)
+			defer func() {
+				if r := recover(); (r != nil) != tt.wantPanic {
+					t.Errorf("expected panic %v, got %v", tt.wantPanic, r)
+				}
+			}()
+			b.StringFlag("undocumented", "").BuildVar()
+		})
+	}
+}
+
+func TestFlagBuilder_SetAlwaysShowDefault(t *testing.T) {
+	tests := []struct {
+		name     string
+		enabled  bool
+		expected string
+	}{
+		{"enabled", true, `  -w, --workers int        Number of workers (default 0)
+  -d, --dry-run            Don't do anything (default false)
+  -o, --output string      Output file (default "")
+`},
+		{"disabled", false, `  -w, --workers int        Number of workers
+  -d, --dry-run            Don't do anything
+  -o, --output string      Output file
+`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b := NewFlagBuilderWithSet(flag.NewFlagSet("test", flag.ContinueOnError))
+			b.SetAlwaysShowDefault(tt.enabled)
+			b.IntFlag("workers", "Number of workers").Alias('w').BuildVar()
+			b.BoolFlag("dry-run", "Don't do anything").Alias('d').BuildVar()
+			b.StringFlag("output", "Output file").Alias('o').BuildVar()
+			var buf strings.Builder
+			b.SetOutput(&buf)
+			b.PrintUsage()
+			if buf.String() != tt.expected {
+				t.Errorf("Usage output mismatch.\nGot:\n%s\nWant:\n%s", buf.String(), tt.expected)
+			}
+		})
+	}
+}
+
+func TestFlagBuilder_NameCollisions(t *testing.T) {
+	tests := []struct {
+		name  string
+		build func(b *FlagBuilder)
+		want  string
+	}{
+		{"long name", func(b *FlagBuilder) {
+			b.StringFlag("output", "output file").BuildVar()
+			b.StringFlag("output", "output dir").BuildVar()
+		}, "fluentflag: --output \"output dir\" is already defined by --output \"output file\""},
+		{"short alias", func(b *FlagBuilder) {
+			b.StringFlag("output", "output file").Alias('o').BuildVar()
+			b.BoolFlag("overwrite", "replace files").Alias('o').BuildVar()
+		}, "fluentflag: -o for --overwrite \"replace files\" is already defined by --output \"output file\""},
+		{"long alias", func(b *FlagBuilder) {
+			b.StringFlag("color", "when to color").BuildVar()
+			b.StringFlag("colour", "when to colour").LongAlias("color").BuildVar()
+		}, "fluentflag: --color for --colour \"when to colour\" is already defined by --color \"when to color\""},
+		{"negated name", func(b *FlagBuilder) {
+			b.BoolFlag("cache", "use the cache").Negatable().BuildVar()
+			b.BoolFlag("no-cache", "skip the cache").BuildVar()
+		}, "fluentflag: --no-cache \"skip the cache\" is already defined by --cache \"use the cache\""},
+		{"own alias twice", func(b *FlagBuilder) {
+			b.StringFlag("output", "output file").Alias('o', 'o').BuildVar()
+		}, "fluentflag: -o is given twice for --output"},
+		{"flag set", func(b *FlagBuilder) {
+			b.flagSet.String("debug", "", "debug mode")
+			b.BoolFlag("debug", "debug mode").BuildVar()
+		}, "fluentflag: --debug \"debug mode\" is already defined on the flag set"},
+		{"built twice", func(b *FlagBuilder) {
+			f := b.IntFlag("num", "number")
+			f.BuildVar()
+			f.BuildVar()
+		}, "fluentflag: flag --num is already built"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			defer func() {
+				if r := recover(); r != tt.want {
+					t.Errorf("expected panic %q, got %v", tt.want, r)
+				}
+			}()
+			tt.build(NewFlagBuilderWithSet(flag.NewFlagSet("test", flag.ContinueOnError)))
+		})
+	}
+}
+
+func TestFlagBuilder_ReturnErrors(t *testing.T) {
+	b := NewFlagBuilderWithSet(flag.NewFlagSet("test", flag.ContinueOnError))
+	b.SetErrorHandling(ReturnErrors)
+	out := b.StringFlag("output", "output file").BuildVar()
+	b.StringFlag("output", "output dir").BuildVar()
+	b.StringFlag("name", "your name").Positive().BuildVar()
+	b.IntFlag("count", "how many").Layout(time.RFC3339).BuildVar()
+
+	want := "fluentflag: --output \"output dir\" is already defined by --output \"output file\"\n" +
+		"fluentflag: Positive requires a numeric flag (--name)\n" +
+		"fluentflag: Layout requires a time flag (--count)"
+	if err := b.Err(); err == nil || err.Error() != want {
+		t.Fatalf("expected errors %q, got %v", want, err)
+	}
+	if _, err := b.Parse([]string{"--output=a"}); err == nil || err.Error() != want {
+		t.Errorf("expected Parse to return %q, got %v", want, err)
+	}
+	if *out != "" {
+		t.Errorf("expected no parsing, got --output %q", *out)
+	}
+}
+
+func TestFluentFlag_TryBuild(t *testing.T) {
+	b := NewFlagBuilderWithSet(flag.NewFlagSet("test", flag.ContinueOnError))
+	var first, second string
+	if err := b.StringFlag("output", "output file").TryBuild(&first); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	err := b.StringFlag("output", "output dir").Alias('o').TryBuild(&second)
+	if want := "fluentflag: --output \"output dir\" is already defined by --output \"output file\""; err == nil || err.Error() != want {
+		t.Errorf("expected error %q, got %v", want, err)
+	}
+	if b.Err() != nil {
+		t.Errorf("expected TryBuild not to record errors, got %v", b.Err())
+	}
+	b.BoolFlag("verbose", "more output").BuildVar() // builder is usable again
+	if _, err := b.Parse([]string{"--output=a", "--verbose"}); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
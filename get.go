@@ -0,0 +1,30 @@
+// get.go
+// Copyright (c) 2025 mattmc3
+// SPDX-License-Identifier: MIT
+// Project home: https://github.com/mattmc3/fluentflag
+
+package fluentflag
+
+import "fmt"
+
+// Get returns the value of the flag with the given name, or one of its
+// aliases, so code far from where the flag is defined can read it without a
+// pointer being passed along. T must be the type the flag holds: T for
+// single values, []T for slice flags, map[string]T for map flags, and int for
+// counters. It returns a FlagError of kind ErrUnknownFlag if there is no such
+// flag, or an error if the flag holds another type.
+//
+//	port, err := fluentflag.Get[int](builder, "port")
+func Get[T any](b *FlagBuilder, name string) (T, error) {
+	var zero T
+	f := b.lookup(name)
+	if f == nil {
+		return zero, newFlagError(ErrUnknownFlag, name, "", "fluentflag: no flag --%s", name)
+	}
+	val := f.boundValue().Get()
+	v, ok := val.(T)
+	if !ok {
+		return zero, fmt.Errorf("fluentflag: --%s is %T, not %T", f.names()[0], val, zero)
+	}
+	return v, nil
+}
@@ -0,0 +1,51 @@
+//go:build go1.18
+
+package fluentflag
+
+import (
+	"errors"
+	"flag"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestGet(t *testing.T) {
+	b := NewFlagBuilderWithSet(flag.NewFlagSet("test", flag.ContinueOnError))
+	b.IntFlag("port", "port").Alias('p').Default(8080).BuildVar()
+	b.DurationFlag("timeout", "timeout").BuildVar()
+	b.StringFlag("tag", "tags").BuildSlice()
+	b.StringFlag("label", "labels").BuildMap()
+	if _, err := b.Parse([]string{"-p", "9000", "--timeout=2s", "--tag=a", "--label=k=v"}); err != nil {
+		t.Fatalf("Parse returned error: %v", err)
+	}
+
+	if port, err := Get[int](b, "port"); err != nil || port != 9000 {
+		t.Errorf("Get port = %v, %v; want 9000", port, err)
+	}
+	if port, err := Get[int](b, "p"); err != nil || port != 9000 {
+		t.Errorf("Get by alias = %v, %v; want 9000", port, err)
+	}
+	if timeout, err := Get[time.Duration](b, "timeout"); err != nil || timeout != 2*time.Second {
+		t.Errorf("Get timeout = %v, %v; want 2s", timeout, err)
+	}
+	if tags, err := Get[[]string](b, "tag"); err != nil || !reflect.DeepEqual(tags, []string{"a"}) {
+		t.Errorf("Get tag = %v, %v; want [a]", tags, err)
+	}
+	if labels, err := Get[map[string]string](b, "label"); err != nil || labels["k"] != "v" {
+		t.Errorf("Get label = %v, %v; want map[k:v]", labels, err)
+	}
+}
+
+func TestGet_Errors(t *testing.T) {
+	b := NewFlagBuilderWithSet(flag.NewFlagSet("test", flag.ContinueOnError))
+	b.IntFlag("port", "port").BuildVar()
+
+	if _, err := Get[string](b, "port"); err == nil || err.Error() != "fluentflag: --port is int, not string" {
+		t.Errorf("expected a type mismatch error, got %v", err)
+	}
+	_, err := Get[int](b, "host")
+	if !errors.Is(err, ErrUnknownFlag) || err.Error() != "fluentflag: no flag --host" {
+		t.Errorf("expected an unknown flag error, got %v", err)
+	}
+}
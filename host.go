@@ -0,0 +1,73 @@
+// host.go
+// Copyright (c) 2025 mattmc3
+// SPDX-License-Identifier: MIT
+// Project home: https://github.com/mattmc3/fluentflag
+
+package fluentflag
+
+import (
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+)
+
+// HostFlag defines a string flag that must be a valid hostname or IP address.
+// The stored value is lowercased and has any trailing dot removed.
+func (b *FlagBuilder) HostFlag(name, usage string) *FluentFlag[string] {
+	f := newFlag[string](b, name, usage)
+	f.transforms = append(f.transforms, func(f *FluentFlag[string], s string) (string, error) {
+		host, ok := normalizeHost(s)
+		if !ok {
+			return "", fmt.Errorf("--%s has an invalid host %q", f.name, s)
+		}
+		return host, nil
+	})
+	return f
+}
+
+// HostPortFlag defines a string flag that must be a host:port address with a
+// valid hostname or IP address and a port from 1 to 65535. The stored value is
+// normalized as with HostFlag.
+func (b *FlagBuilder) HostPortFlag(name, usage string) *FluentFlag[string] {
+	f := newFlag[string](b, name, usage)
+	f.transforms = append(f.transforms, func(f *FluentFlag[string], s string) (string, error) {
+		rawHost, rawPort, err := net.SplitHostPort(s)
+		if err != nil {
+			return "", fmt.Errorf("--%s must be host:port: %v", f.name, err)
+		}
+		host, ok := normalizeHost(rawHost)
+		if !ok {
+			return "", fmt.Errorf("--%s has an invalid host %q", f.name, rawHost)
+		}
+		port, err := strconv.ParseUint(rawPort, 10, 16)
+		if err != nil || port == 0 {
+			return "", fmt.Errorf("--%s has an invalid port %q (must be 1-65535)", f.name, rawPort)
+		}
+		return net.JoinHostPort(host, rawPort), nil
+	})
+	return f
+}
+
+// normalizeHost validates s as an IP address or RFC 1123 hostname and returns
+// it in canonical form.
+func normalizeHost(s string) (string, bool) {
+	if ip := net.ParseIP(s); ip != nil {
+		return ip.String(), true
+	}
+	host := strings.ToLower(strings.TrimSuffix(s, "."))
+	if host == "" || len(host) > 253 {
+		return "", false
+	}
+	for _, label := range strings.Split(host, ".") {
+		if len(label) == 0 || len(label) > 63 || label[0] == '-' || label[len(label)-1] == '-' {
+			return "", false
+		}
+		for _, r := range label {
+			if (r < 'a' || r > 'z') && (r < '0' || r > '9') && r != '-' {
+				return "", false
+			}
+		}
+	}
+	return host, true
+}
@@ -0,0 +1,70 @@
+//go:build go1.18
+
+package fluentflag
+
+import (
+	"flag"
+	"io"
+	"strings"
+	"testing"
+)
+
+func TestHostFlag(t *testing.T) {
+	tests := []struct {
+		arg     string
+		want    string
+		wantErr string
+	}{
+		{"Example.COM.", "example.com", ""},
+		{"10.0.0.1", "10.0.0.1", ""},
+		{"bad_host", "", `--host has an invalid host "bad_host"`},
+		{"-leading.example.com", "", `--host has an invalid host "-leading.example.com"`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.arg, func(t *testing.T) {
+			fs := flag.NewFlagSet("test", flag.ContinueOnError)
+			fs.SetOutput(io.Discard)
+			b := NewFlagBuilderWithSet(fs)
+			host := b.HostFlag("host", "server host").BuildVar()
+			err := fs.Parse([]string{"--host", tt.arg})
+			if tt.wantErr == "" {
+				if err != nil || *host != tt.want {
+					t.Errorf("expected %q, got %q (err %v)", tt.want, *host, err)
+				}
+			} else if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("expected error containing %q, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestHostPortFlag(t *testing.T) {
+	tests := []struct {
+		arg     string
+		want    string
+		wantErr string
+	}{
+		{"example.com:8080", "example.com:8080", ""},
+		{"[::1]:443", "[::1]:443", ""},
+		{"example.com:99999", "", `--addr has an invalid port "99999" (must be 1-65535)`},
+		{"example.com:0", "", `--addr has an invalid port "0" (must be 1-65535)`},
+		{"exa mple.com:80", "", `--addr has an invalid host "exa mple.com"`},
+		{"example.com", "", "--addr must be host:port"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.arg, func(t *testing.T) {
+			fs := flag.NewFlagSet("test", flag.ContinueOnError)
+			fs.SetOutput(io.Discard)
+			b := NewFlagBuilderWithSet(fs)
+			addr := b.HostPortFlag("addr", "listen address").BuildVar()
+			err := fs.Parse([]string{"--addr", tt.arg})
+			if tt.wantErr == "" {
+				if err != nil || *addr != tt.want {
+					t.Errorf("expected %q, got %q (err %v)", tt.want, *addr, err)
+				}
+			} else if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("expected error containing %q, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,80 @@
+// jsonflag.go
+// Copyright (c) 2025 mattmc3
+// SPDX-License-Identifier: MIT
+// Project home: https://github.com/mattmc3/fluentflag
+
+package fluentflag
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// jsonValue implements flag.Value by unmarshaling JSON into a T.
+type jsonValue[T any] struct {
+	name   string
+	target *T
+}
+
+// String returns the value marshaled as JSON.
+func (self *jsonValue[T]) String() string {
+	if self.target == nil {
+		return ""
+	}
+	data, err := json.Marshal(*self.target)
+	if err != nil {
+		return ""
+	}
+	return string(data)
+}
+
+// Set unmarshals val into the target.
+func (self *jsonValue[T]) Set(val string) error {
+	var v T
+	if err := json.Unmarshal([]byte(val), &v); err != nil {
+		return fmt.Errorf("--%s has invalid JSON: %v", self.name, err)
+	}
+	*self.target = v
+	return nil
+}
+
+// Get returns the unmarshaled value.
+func (self *jsonValue[T]) Get() any {
+	return *self.target
+}
+
+// list returns the value formatted as a one-element list.
+func (self *jsonValue[T]) list() []string {
+	return []string{self.String()}
+}
+
+// setZero sets the target to the zero value of T.
+func (self *jsonValue[T]) setZero() {
+	var zero T
+	*self.target = zero
+}
+
+// cloneFor returns a new target for the clone f of its flag.
+func (self *jsonValue[T]) cloneFor(f *FluentFlag[string]) fluentValue {
+	return &jsonValue[T]{name: self.name, target: new(T)}
+}
+
+// shareWith returns a value for f, a copy of its flag under another name,
+// bound to the same target.
+func (self *jsonValue[T]) shareWith(f *FluentFlag[string]) fluentValue {
+	return &jsonValue[T]{name: f.name, target: self.target}
+}
+
+// goType returns the Go type of the target.
+func (self *jsonValue[T]) goType() string {
+	return fmt.Sprintf("%T", *new(T))
+}
+
+// JSONFlag defines a flag whose value is JSON, such as --opts='{"a":1}', and
+// returns a pointer to the T it is unmarshaled into.
+func JSONFlag[T any](b *FlagBuilder, name, usage string) *T {
+	target := new(T)
+	f := newFlag[string](b, name, usage)
+	b.fail(f.register(&jsonValue[T]{name: name, target: target}))
+	return target
+}
@@ -0,0 +1,37 @@
+//go:build go1.18
+
+package fluentflag
+
+import (
+	"flag"
+	"io"
+	"strings"
+	"testing"
+)
+
+func TestJSONFlag(t *testing.T) {
+	type opts struct {
+		A int    `json:"a"`
+		B string `json:"b"`
+	}
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	b := NewFlagBuilderWithSet(fs)
+	o := JSONFlag[opts](b, "opts", "options as JSON")
+	if err := fs.Parse([]string{`--opts={"a":1,"b":"x"}`}); err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	if o.A != 1 || o.B != "x" {
+		t.Errorf("expected {1 x}, got %+v", *o)
+	}
+}
+
+func TestJSONFlag_Invalid(t *testing.T) {
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+	b := NewFlagBuilderWithSet(fs)
+	JSONFlag[map[string]int](b, "opts", "options as JSON")
+	err := fs.Parse([]string{`--opts={"a":`})
+	if err == nil || !strings.Contains(err.Error(), "--opts has invalid JSON") {
+		t.Errorf("expected invalid JSON error, got %v", err)
+	}
+}
@@ -0,0 +1,77 @@
+// list.go
+// Copyright (c) 2025 mattmc3
+// SPDX-License-Identifier: MIT
+// Project home: https://github.com/mattmc3/fluentflag
+
+package fluentflag
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// flagListing is one flag as written by ListFlags, taken from its UsageFlag.
+type flagListing struct {
+	Name     string   `json:"name"`
+	Alias    string   `json:"alias,omitempty"`
+	Aliases  []string `json:"aliases,omitempty"` // any aliases after the first
+	Type     string   `json:"type"`
+	HasValue bool     `json:"has_value"`
+	Usage    string   `json:"usage"`
+	Env      string   `json:"env,omitempty"`
+}
+
+// ListFlags writes a machine-parseable listing of the visible flags to w. The
+// "tsv" format writes one name, type, has_value, and usage line per flag, with
+// tabs, newlines, and backslashes in the usage escaped. The "json" format
+// writes an array of objects, which also name any environment variable a flag
+// is read from.
+func (b *FlagBuilder) ListFlags(w io.Writer, format string) error {
+	var listings []flagListing
+	for _, f := range b.visibleFlags() {
+		u := f.usageFlag(0)
+		listing := flagListing{
+			Name:     u.Name,
+			Type:     u.GoType,
+			HasValue: u.TakesValue,
+			Usage:    u.Usage,
+			Env:      u.Env,
+		}
+		if len(u.Aliases) > 0 {
+			listing.Alias = u.Aliases[0]
+			listing.Aliases = u.Aliases[1:]
+		}
+		listings = append(listings, listing)
+	}
+
+	switch format {
+	case "tsv":
+		for _, l := range listings {
+			_, err := fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", l.Name, l.Type, strconv.FormatBool(l.HasValue), escapeTSV(l.Usage))
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	case "json":
+		if listings == nil {
+			listings = []flagListing{}
+		}
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(listings)
+	default:
+		return fmt.Errorf("fluentflag: unsupported list format %q", format)
+	}
+}
+
+// tsvEscaper escapes characters that would break a TSV field.
+var tsvEscaper = strings.NewReplacer(`\`, `\\`, "\t", `\t`, "\n", `\n`, "\r", `\r`)
+
+// escapeTSV escapes s for use as a TSV field.
+func escapeTSV(s string) string {
+	return tsvEscaper.Replace(s)
+}
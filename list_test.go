@@ -0,0 +1,75 @@
+//go:build go1.18
+
+package fluentflag
+
+import (
+	"encoding/json"
+	"flag"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func newListBuilder() *FlagBuilder {
+	b := NewFlagBuilderWithSet(flag.NewFlagSet("test", flag.ContinueOnError))
+	b.StringFlag("name", "Command name").Alias('n').BuildVar()
+	b.BoolFlag("verbose", "Be\tverbose\nand chatty").BuildVar()
+	b.IntFlag("level", "Levels").BuildSlice()
+	return b
+}
+
+func TestListFlags_TSV(t *testing.T) {
+	var buf strings.Builder
+	if err := newListBuilder().ListFlags(&buf, "tsv"); err != nil {
+		t.Fatalf("ListFlags failed: %v", err)
+	}
+	expected := "name\tstring\ttrue\tCommand name\n" +
+		"verbose\tbool\tfalse\tBe\\tverbose\\nand chatty\n" +
+		"level\t[]int\ttrue\tLevels\n"
+	if buf.String() != expected {
+		t.Errorf("TSV mismatch.\nGot:\n%q\nWant:\n%q", buf.String(), expected)
+	}
+}
+
+func TestListFlags_JSON(t *testing.T) {
+	var buf strings.Builder
+	if err := newListBuilder().ListFlags(&buf, "json"); err != nil {
+		t.Fatalf("ListFlags failed: %v", err)
+	}
+	var actual []map[string]any
+	if err := json.Unmarshal([]byte(buf.String()), &actual); err != nil {
+		t.Fatalf("invalid JSON: %v", err)
+	}
+	expected := []map[string]any{
+		{"name": "name", "alias": "n", "type": "string", "has_value": true, "usage": "Command name"},
+		{"name": "verbose", "type": "bool", "has_value": false, "usage": "Be\tverbose\nand chatty"},
+		{"name": "level", "type": "[]int", "has_value": true, "usage": "Levels"},
+	}
+	if !reflect.DeepEqual(actual, expected) {
+		t.Errorf("JSON mismatch.\nGot:\n%v\nWant:\n%v", actual, expected)
+	}
+}
+
+func TestListFlags_UnsupportedFormat(t *testing.T) {
+	if err := newListBuilder().ListFlags(&strings.Builder{}, "xml"); err == nil {
+		t.Error("expected error for unsupported format")
+	}
+}
+
+func TestListFlags_Env(t *testing.T) {
+	b := NewFlagBuilderWithSet(flag.NewFlagSet("test", flag.ContinueOnError))
+	b.EnvPrefix("MYAPP")
+	b.IntFlag("port", "Listen port").BuildVar()
+	b.StringFlag("token", "API token").Env("API_TOKEN").BuildVar()
+	var buf strings.Builder
+	if err := b.ListFlags(&buf, "json"); err != nil {
+		t.Fatalf("ListFlags failed: %v", err)
+	}
+	var actual []flagListing
+	if err := json.Unmarshal([]byte(buf.String()), &actual); err != nil {
+		t.Fatalf("invalid JSON: %v", err)
+	}
+	if len(actual) != 2 || actual[0].Env != "MYAPP_PORT" || actual[1].Env != "API_TOKEN" {
+		t.Errorf("expected env vars MYAPP_PORT and API_TOKEN, got %+v", actual)
+	}
+}
@@ -0,0 +1,98 @@
+// lookup.go
+// Copyright (c) 2025 mattmc3
+// SPDX-License-Identifier: MIT
+// Project home: https://github.com/mattmc3/fluentflag
+
+package fluentflag
+
+// Flag describes a built flag, for code that inspects a builder's flags.
+type Flag interface {
+	Name() string      // name of the flag, like "output"
+	Aliases() []string // short and long aliases, like "o"
+	Usage() string     // usage text
+	Type() string      // Go type of the value, like "int" or "[]string"
+	Default() string   // default value, or "" for slice and map flags
+	Value() any        // current value, as its Go type
+	IsSet() bool       // whether the flag was set rather than left at its default
+}
+
+// builtFlagInfo implements Flag for a built flag.
+type builtFlagInfo struct {
+	b *FlagBuilder
+	f builtFlag
+}
+
+// describe returns the flag's description, shared with usage and Schema.
+func (self builtFlagInfo) describe() UsageFlag {
+	return self.f.usageFlag(0)
+}
+
+// Name returns the name of the flag.
+func (self builtFlagInfo) Name() string {
+	return self.describe().Name
+}
+
+// Aliases returns the flag's aliases.
+func (self builtFlagInfo) Aliases() []string {
+	return append([]string{}, self.describe().Aliases...)
+}
+
+// Usage returns the flag's usage text.
+func (self builtFlagInfo) Usage() string {
+	return self.describe().Usage
+}
+
+// Type returns the Go type of the flag's value.
+func (self builtFlagInfo) Type() string {
+	return self.describe().GoType
+}
+
+// Default returns the flag's default value formatted as a string.
+func (self builtFlagInfo) Default() string {
+	return self.describe().DefaultValue
+}
+
+// Value returns the flag's current value.
+func (self builtFlagInfo) Value() any {
+	return self.f.boundValue().Get()
+}
+
+// IsSet reports whether the flag was set during parsing, as Changed does.
+func (self builtFlagInfo) IsSet() bool {
+	return self.b.isSet(self.f)
+}
+
+// Lookup returns the built flag with the given name or alias.
+func (b *FlagBuilder) Lookup(name string) (Flag, bool) {
+	f := b.lookup(name)
+	if f == nil {
+		return nil, false
+	}
+	return builtFlagInfo{b, f}, true
+}
+
+// Changed reports whether the flag with the given name or alias was set
+// during parsing, on the command line, from the environment, or from a config
+// file, rather than left at its default. A flag set to its default value, as
+// in --workers=0, counts as changed. It is false for unknown flags.
+func (b *FlagBuilder) Changed(name string) bool {
+	f := b.lookup(name)
+	return f != nil && b.isSet(f)
+}
+
+// Flags returns the built flags, including hidden ones, in definition order.
+func (b *FlagBuilder) Flags() []Flag {
+	flags := make([]Flag, 0, len(b.flagsBuilt))
+	for _, f := range b.flagsBuilt {
+		flags = append(flags, builtFlagInfo{b, f})
+	}
+	return flags
+}
+
+// Visit calls fn for each built flag in definition order. Unlike
+// flag.FlagSet.Visit, it visits every flag, whether or not it was set.
+func (b *FlagBuilder) Visit(fn func(Flag)) {
+	for _, f := range b.flagsBuilt {
+		fn(builtFlagInfo{b, f})
+	}
+}
@@ -0,0 +1,91 @@
+//go:build go1.18
+
+package fluentflag
+
+import (
+	"flag"
+	"reflect"
+	"testing"
+)
+
+func TestLookup(t *testing.T) {
+	b := NewFlagBuilderWithSet(flag.NewFlagSet("test", flag.ContinueOnError))
+	b.IntFlag("port", "Port to listen on").Alias('p').Default(8080).BuildVar()
+	if _, err := b.Parse([]string{"-p", "9000"}); err != nil {
+		t.Fatalf("Parse returned error: %v", err)
+	}
+
+	for _, name := range []string{"port", "p"} {
+		f, ok := b.Lookup(name)
+		if !ok {
+			t.Fatalf("Lookup(%q) found nothing", name)
+		}
+		actual := []any{f.Name(), f.Aliases(), f.Usage(), f.Type(), f.Default(), f.Value()}
+		expected := []any{"port", []string{"p"}, "Port to listen on", "int", "8080", 9000}
+		if !reflect.DeepEqual(actual, expected) {
+			t.Errorf("Lookup(%q) = %v, want %v", name, actual, expected)
+		}
+	}
+	if _, ok := b.Lookup("host"); ok {
+		t.Error("expected Lookup of an unknown flag to fail")
+	}
+}
+
+func TestFlagsAndVisit(t *testing.T) {
+	b := NewFlagBuilderWithSet(flag.NewFlagSet("test", flag.ContinueOnError))
+	b.StringFlag("name", "Name").BuildVar()
+	b.BoolFlag("debug", "Debug").Hidden().BuildVar()
+	b.StringFlag("tag", "Tags").BuildSlice()
+
+	var visited []string
+	b.Visit(func(f Flag) {
+		visited = append(visited, f.Name()+" "+f.Type())
+	})
+	var listed []string
+	for _, f := range b.Flags() {
+		listed = append(listed, f.Name()+" "+f.Type())
+	}
+	expected := []string{"name string", "debug bool", "tag []string"}
+	if !reflect.DeepEqual(visited, expected) {
+		t.Errorf("Visit = %v, want %v", visited, expected)
+	}
+	if !reflect.DeepEqual(listed, expected) {
+		t.Errorf("Flags = %v, want %v", listed, expected)
+	}
+}
+
+func TestChanged(t *testing.T) {
+	tests := []struct {
+		name string
+		args []string
+		env  string
+		want map[string]bool
+	}{
+		{"defaults", nil, "", map[string]bool{"workers": false, "w": false, "host": false}},
+		{"set to default", []string{"--workers=0"}, "", map[string]bool{"workers": true, "w": true, "host": false}},
+		{"alias", []string{"-w", "3"}, "", map[string]bool{"workers": true, "host": false}},
+		{"env", nil, "example.com", map[string]bool{"workers": false, "host": true}},
+		{"unknown", nil, "", map[string]bool{"bogus": false}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.env != "" {
+				t.Setenv("CHANGED_TEST_HOST", tt.env)
+			}
+			b := NewFlagBuilderWithSet(flag.NewFlagSet("test", flag.ContinueOnError))
+			b.IntFlag("workers", "Workers").Alias('w').BuildVar()
+			b.StringFlag("host", "Host").Env("CHANGED_TEST_HOST").BuildVar()
+			if _, err := b.Parse(tt.args); err != nil {
+				t.Fatalf("Parse returned error: %v", err)
+			}
+			for name, want := range tt.want {
+				if got := b.Changed(name); got != want {
+					t.Errorf("Changed(%q) = %v, want %v", name, got, want)
+				}
+				if f, ok := b.Lookup(name); ok && f.IsSet() != want {
+					t.Errorf("Lookup(%q).IsSet() = %v, want %v", name, f.IsSet(), want)
+				}
+			}
+		})
+	}
+}
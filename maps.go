@@ -0,0 +1,114 @@
+// maps.go
+// Copyright (c) 2025 mattmc3
+// SPDX-License-Identifier: MIT
+// Project home: https://github.com/mattmc3/fluentflag
+
+package fluentflag
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// mapValues implements flag.Value for accumulating key=value pairs into a map.
+type mapValues[K comparable, V FlagType] struct {
+	flag     *FluentFlag[V]
+	parseKey func(string) (K, error)
+	target   *map[K]V
+	count    int // number of pairs given, to report which one failed
+}
+
+// String returns the string representation of the map.
+func (self *mapValues[K, V]) String() string {
+	if self.target == nil {
+		return "map[]"
+	}
+	return fmt.Sprintf("%v", *self.target)
+}
+
+// Set parses a key=value pair and adds it to the map.
+func (self *mapValues[K, V]) Set(val string) error {
+	self.count++
+	rawKey, rawVal, ok := strings.Cut(val, "=")
+	if !ok {
+		return fmt.Errorf("--%s expects key=value, got %q (occurrence %d)", self.flag.name, val, self.count)
+	}
+	key, err := self.parseKey(rawKey)
+	if err != nil {
+		return fmt.Errorf("--%s has an invalid key in %q (occurrence %d): %v", self.flag.name, val, self.count, err)
+	}
+	parsed, err := self.flag.parse(rawVal)
+	if err != nil {
+		return fmt.Errorf("--%s has an invalid value in %q (occurrence %d): %v", self.flag.name, val, self.count, err)
+	}
+	(*self.target)[key] = parsed
+	return nil
+}
+
+// Get returns the map.
+func (self *mapValues[K, V]) Get() any {
+	return *self.target
+}
+
+// list returns the pairs formatted as key=value strings in sorted order.
+func (self *mapValues[K, V]) list() []string {
+	var pairs []string
+	for k, v := range *self.target {
+		pairs = append(pairs, fmt.Sprintf("%v=%v", k, v))
+	}
+	sort.Strings(pairs)
+	return pairs
+}
+
+// setZero empties the map.
+func (self *mapValues[K, V]) setZero() {
+	*self.target = map[K]V{}
+	self.count = 0
+}
+
+// cloneFor returns a new, empty map for the clone f of its flag.
+func (self *mapValues[K, V]) cloneFor(f *FluentFlag[V]) fluentValue {
+	return &mapValues[K, V]{flag: f, parseKey: self.parseKey, target: &map[K]V{}}
+}
+
+// shareWith returns a value for f, a copy of its flag under another name,
+// bound to the same map.
+func (self *mapValues[K, V]) shareWith(f *FluentFlag[V]) fluentValue {
+	return &mapValues[K, V]{flag: f, parseKey: self.parseKey, target: self.target}
+}
+
+// isList reports that the map collects a pair each time it is set.
+func (self *mapValues[K, V]) isList() bool {
+	return true
+}
+
+// goType returns the Go type of the map.
+func (self *mapValues[K, V]) goType() string {
+	return fmt.Sprintf("map[%T]%T", *new(K), *new(V))
+}
+
+// BuildKeyedMap registers a flag that accumulates key=value pairs into a map.
+// Keys are converted with parseKey and values are parsed as the flag's type,
+// so --weight 1=high --weight 2=low can fill a map[int]string.
+func BuildKeyedMap[K comparable, V FlagType](f *FluentFlag[V], parseKey func(string) (K, error)) *map[K]V {
+	m := new(map[K]V) // allocate on heap
+	*m = map[K]V{}
+	f.builder.fail(f.register(&mapValues[K, V]{flag: f, parseKey: parseKey, target: m}))
+	return m
+}
+
+// BuildMap registers a flag that accumulates key=value pairs into a map with
+// string keys, so --label env=prod --label team=core fills a map[string]string.
+func (self *FluentFlag[T]) BuildMap() *map[string]T {
+	return BuildKeyedMap(self, parseMapKey)
+}
+
+// parseMapKey accepts any non-empty string as a map key.
+func parseMapKey(key string) (string, error) {
+	if key == "" {
+		return "", errors.New("key is empty")
+	}
+	return key, nil
+}
@@ -0,0 +1,84 @@
+//go:build go1.18
+
+package fluentflag
+
+import (
+	"flag"
+	"io"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestBuildKeyedMap(t *testing.T) {
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+	b := NewFlagBuilderWithSet(fs)
+	weights := BuildKeyedMap(b.StringFlag("weight", "weights").Alias('w'), strconv.Atoi)
+	if err := fs.Parse([]string{"--weight", "1=high", "-w", "2=low"}); err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	want := map[int]string{1: "high", 2: "low"}
+	if !reflect.DeepEqual(*weights, want) {
+		t.Errorf("expected %v, got %v", want, *weights)
+	}
+}
+
+func TestBuildKeyedMap_Errors(t *testing.T) {
+	tests := []struct {
+		name    string
+		arg     string
+		wantErr string
+	}{
+		{"malformed key", "one=high", `--weight has an invalid key in "one=high"`},
+		{"missing separator", "high", `--weight expects key=value, got "high"`},
+		{"bad value", "1=heavy", `--weight has an invalid value in "1=heavy"`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fs := flag.NewFlagSet("test", flag.ContinueOnError)
+			fs.SetOutput(io.Discard)
+			b := NewFlagBuilderWithSet(fs)
+			BuildKeyedMap(b.StringFlag("weight", "weights").Choices("high", "low"), strconv.Atoi)
+			err := fs.Parse([]string{"--weight", tt.arg})
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("expected error containing %q, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestBuildMap(t *testing.T) {
+	tests := []struct {
+		name    string
+		args    []string
+		want    map[string]int
+		wantErr string
+	}{
+		{"pairs", []string{"--limit", "cpu=2", "--limit", "mem=512"}, map[string]int{"cpu": 2, "mem": 512}, ""},
+		{"later wins", []string{"--limit", "cpu=2", "--limit", "cpu=4"}, map[string]int{"cpu": 4}, ""},
+		{"bad value", []string{"--limit", "cpu=2", "--limit", "mem=lots"}, nil, `--limit has an invalid value in "mem=lots" (occurrence 2)`},
+		{"missing separator", []string{"--limit", "cpu"}, nil, `--limit expects key=value, got "cpu" (occurrence 1)`},
+		{"empty key", []string{"--limit", "=2"}, nil, `--limit has an invalid key in "=2" (occurrence 1): key is empty`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fs := flag.NewFlagSet("test", flag.ContinueOnError)
+			fs.SetOutput(io.Discard)
+			b := NewFlagBuilderWithSet(fs)
+			limits := b.IntFlag("limit", "resource limits").BuildMap()
+			err := fs.Parse(tt.args)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("Parse failed: %v", err)
+				}
+				if !reflect.DeepEqual(*limits, tt.want) {
+					t.Errorf("expected %v, got %v", tt.want, *limits)
+				}
+			} else if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("expected error containing %q, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
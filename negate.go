@@ -0,0 +1,65 @@
+// negate.go
+// Copyright (c) 2025 mattmc3
+// SPDX-License-Identifier: MIT
+// Project home: https://github.com/mattmc3/fluentflag
+
+package fluentflag
+
+import (
+	"flag"
+	"fmt"
+	"strconv"
+)
+
+// negatedValue implements flag.Value for the --no-<name> form of a bool flag,
+// setting the flag to the opposite of the given value.
+type negatedValue struct {
+	target flag.Value
+}
+
+// String returns the negation of the flag's value.
+func (self *negatedValue) String() string {
+	if self.target == nil {
+		return ""
+	}
+	on, err := strconv.ParseBool(self.target.String())
+	if err != nil {
+		return ""
+	}
+	return strconv.FormatBool(!on)
+}
+
+// Set sets the flag to the negation of val.
+func (self *negatedValue) Set(val string) error {
+	on, err := strconv.ParseBool(val)
+	if err != nil {
+		return err
+	}
+	return self.target.Set(strconv.FormatBool(!on))
+}
+
+// IsBoolFlag lets the flag package accept --no-<name> without a value.
+func (self *negatedValue) IsBoolFlag() bool {
+	return true
+}
+
+// Negatable also registers a --no-<name> form of a bool flag that sets it to
+// false, which is how a flag that defaults to true gets turned off. Usage
+// shows both forms as --[no-]name.
+func (self *FluentFlag[T]) Negatable() *FluentFlag[T] {
+	if _, ok := any(self.defaultVal).(bool); !ok {
+		self.builder.fail(fmt.Errorf("fluentflag: Negatable requires a bool flag (--%s)", self.name))
+		return self
+	}
+	self.negatable = true
+	return self
+}
+
+// negatedName returns the name of the flag's --no-<name> form, or "" if it
+// isn't negatable.
+func (self *FluentFlag[T]) negatedName() string {
+	if !self.negatable {
+		return ""
+	}
+	return "no-" + self.name
+}
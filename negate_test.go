@@ -0,0 +1,60 @@
+//go:build go1.18
+
+package fluentflag
+
+import (
+	"flag"
+	"testing"
+)
+
+func TestNegatable(t *testing.T) {
+	tests := []struct {
+		name string
+		args []string
+		want bool
+	}{
+		{"default", []string{}, true},
+		{"negated", []string{"--no-color"}, false},
+		{"negated false", []string{"--no-color=false"}, true},
+		{"last wins", []string{"--no-color", "-c"}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b := NewFlagBuilderWithSet(flag.NewFlagSet("test", flag.ContinueOnError))
+			color := b.BoolFlag("color", "colorize output").Alias('c').Default(true).Negatable().BuildVar()
+			if _, err := b.Parse(tt.args); err != nil {
+				t.Fatal(err)
+			}
+			if *color != tt.want {
+				t.Errorf("expected %v, got %v", tt.want, *color)
+			}
+		})
+	}
+}
+
+func TestNegatable_CountsAsSet(t *testing.T) {
+	b := NewFlagBuilderWithSet(flag.NewFlagSet("test", flag.ContinueOnError))
+	b.BoolFlag("color", "colorize output").Default(true).Negatable().Required().BuildVar()
+	if _, err := b.Parse([]string{"--no-color"}); err != nil {
+		t.Errorf("expected --no-color to satisfy Required, got %v", err)
+	}
+}
+
+func TestNegatable_Usage(t *testing.T) {
+	b := NewFlagBuilderWithSet(flag.NewFlagSet("test", flag.ContinueOnError))
+	f := b.BoolFlag("color", "colorize output").Alias('c').Default(true).Negatable()
+	f.BuildVar()
+	if got, want := f.Usage(), "  -c, --[no-]color         colorize output (default true)"; got != want {
+		t.Errorf("expected %q, got %q", want, got)
+	}
+}
+
+func TestNegatable_NonBoolPanics(t *testing.T) {
+	defer func() {
+		if r := recover(); r == nil {
+			t.Error("expected panic for a non-bool flag")
+		}
+	}()
+	b := NewFlagBuilderWithSet(flag.NewFlagSet("test", flag.ContinueOnError))
+	b.StringFlag("color", "color mode").Negatable()
+}
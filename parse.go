@@ -0,0 +1,443 @@
+// parse.go
+// Copyright (c) 2025 mattmc3
+// SPDX-License-Identifier: MIT
+// Project home: https://github.com/mattmc3/fluentflag
+
+package fluentflag
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+)
+
+// SetParseTimeout limits how long Parse and ParseContext may run. A zero
+// duration (the default) disables the timeout.
+func (b *FlagBuilder) SetParseTimeout(d time.Duration) {
+	b.parseTimeout = d
+}
+
+// SetExitFunc sets the function used to exit the program, for example after
+// printing help. It defaults to os.Exit and is mainly useful in tests.
+func (b *FlagBuilder) SetExitFunc(fn func(code int)) {
+	b.exitFunc = fn
+}
+
+// exit exits the program with code using the exit function.
+func (b *FlagBuilder) exit(code int) {
+	if b.exitFunc != nil {
+		b.exitFunc(code)
+		return
+	}
+	os.Exit(code)
+}
+
+// Parse parses args with the builder's flag set, validates the result, and
+// returns the remaining non-flag arguments. When several flags fail
+// validation, such as a missing required flag and an unlisted choice, the
+// error is a ValidationErrors reporting all of them.
+func (b *FlagBuilder) Parse(args []string) ([]string, error) {
+	return b.ParseContext(context.Background(), args)
+}
+
+// ParseContext is like Parse, but gives up when ctx is done or the parse
+// timeout expires. A flag value setter that is still running when parsing is
+// abandoned is left to finish in the background, still writing to the flags,
+// so their values must not be read after an aborted parse. Until it finishes,
+// Parse and ParseContext fail, and Reset waits for it before restoring the
+// defaults, which makes the builder usable again.
+func (b *FlagBuilder) ParseContext(ctx context.Context, args []string) ([]string, error) {
+	if b.parseRunning() {
+		return nil, errParseRunning
+	}
+	if b.parseTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, b.parseTimeout)
+		defer cancel()
+	}
+	if ctx.Done() == nil {
+		if err := b.parse(args); err != nil {
+			return nil, err
+		}
+		return b.rest, nil
+	}
+
+	done := make(chan error, 1)
+	go func() {
+		done <- b.parse(args)
+	}()
+	select {
+	case err := <-done:
+		if err != nil {
+			return nil, err
+		}
+		return b.rest, nil
+	case <-ctx.Done():
+		b.abandoned = done
+		if errors.Is(ctx.Err(), context.DeadlineExceeded) && b.parseTimeout > 0 {
+			return nil, fmt.Errorf("fluentflag: parsing timed out after %s: %w", b.parseTimeout, ctx.Err())
+		}
+		return nil, fmt.Errorf("fluentflag: parsing aborted: %w", ctx.Err())
+	}
+}
+
+// errParseRunning is returned by Parse, ParseContext, and Dispatch while a
+// parse abandoned by ParseContext is still running.
+var errParseRunning = errors.New("fluentflag: an aborted parse is still running; call Reset to wait for it")
+
+// parseRunning reports whether a parse abandoned by ParseContext is still
+// running in the background.
+func (b *FlagBuilder) parseRunning() bool {
+	if b.abandoned == nil {
+		return false
+	}
+	select {
+	case <-b.abandoned:
+		b.abandoned = nil
+		return false
+	default:
+		return true
+	}
+}
+
+// waitForParse waits for a parse abandoned by ParseContext to finish.
+func (b *FlagBuilder) waitForParse() {
+	if b.abandoned != nil {
+		<-b.abandoned
+		b.abandoned = nil
+	}
+}
+
+// parse runs the underlying flag set parse followed by validation and the
+// post-parse hooks.
+func (b *FlagBuilder) parse(args []string) error {
+	if served, err := b.serveCompleteArgs(args); served {
+		return err
+	}
+	if err := b.parseArgs(args); err != nil {
+		return err
+	}
+	return b.finishParse()
+}
+
+// parseArgs parses the command-line arguments into the flag set and collects
+// the arguments left over.
+func (b *FlagBuilder) parseArgs(args []string) error {
+	if err := b.Err(); err != nil {
+		return err
+	}
+	b.setOnSubcommand = nil
+	args, err := b.rewriteArgs(args)
+	if err != nil {
+		fmt.Fprintln(b.flagSet.Output(), err)
+	} else {
+		err = b.parseFlagSet(args)
+	}
+	if err != nil {
+		return b.parseFailed(err)
+	}
+	b.collectArgs()
+	b.warnDeprecated()
+	return nil
+}
+
+// finishParse fills in flags from other sources, validates them, and runs
+// the post-parse hooks.
+func (b *FlagBuilder) finishParse() error {
+	if err := b.assignPositionals(); err != nil {
+		return err
+	}
+	if err := b.Resolve(); err != nil {
+		return err
+	}
+	if err := b.Validate(); err != nil {
+		return err
+	}
+	for _, fn := range b.postParse {
+		if err := fn(b); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// collectArgs sorts the arguments left after parsing into positionals and,
+// if enabled, KEY=VALUE assignments.
+func (b *FlagBuilder) collectArgs() {
+	b.rest = []string{}
+	b.assignments = map[string]string{}
+	for _, arg := range b.flagSet.Args() {
+		if key, value, ok := strings.Cut(arg, "="); ok && b.collectAssignments && key != "" && key[0] != '-' && !(b.stopOnNonFlag && len(b.rest) > 0) {
+			b.assignments[key] = value
+			continue
+		}
+		b.rest = append(b.rest, arg)
+	}
+	b.argCount = len(b.rest)
+}
+
+// StopOnNonFlag keeps the first non-flag argument and everything after it
+// untouched, for wrapper commands like "mytool run cmd --cmd-flag" where the
+// wrapped command's arguments must be passed through. Parse already stops at
+// the first non-flag argument by default, so this only matters with other
+// options: it overrides Interspersed, and with CollectAssignments, KEY=VALUE
+// assignments are only collected before the first other argument, the way env
+// does.
+func (b *FlagBuilder) StopOnNonFlag() {
+	b.stopOnNonFlag = true
+}
+
+// CollectAssignments makes Parse collect KEY=VALUE arguments that follow the
+// flags, as accepted by tools like env and make, instead of returning them
+// with the other non-flag arguments. They are available from Assignments.
+func (b *FlagBuilder) CollectAssignments(enabled bool) {
+	b.collectAssignments = enabled
+}
+
+// Assignments returns the KEY=VALUE arguments collected by the last Parse.
+func (b *FlagBuilder) Assignments() map[string]string {
+	return b.assignments
+}
+
+// parseFlagSet parses args with the flag set. Usage is printed by the builder
+// rather than the flag set, so that requested help and parse errors can go to
+// different writers.
+func (b *FlagBuilder) parseFlagSet(args []string) error {
+	fs := b.flagSet
+	handling, usage := fs.ErrorHandling(), fs.Usage
+	fs.Init(fs.Name(), flag.ContinueOnError)
+	fs.Usage = func() {}
+	defer func() {
+		fs.Init(fs.Name(), handling)
+		fs.Usage = usage
+	}()
+	b.setErr = nil
+	if err := fs.Parse(args); err != nil {
+		return b.flagSetError(err)
+	}
+	return nil
+}
+
+// parseFailed prints usage for a failed parse and then handles err as the flag
+// set's error handling mode dictates.
+func (b *FlagBuilder) parseFailed(err error) error {
+	if errors.Is(err, flag.ErrHelp) {
+		b.printHelp(b.helpWriter())
+	} else {
+		b.printHelp(b.outputWriter())
+	}
+	switch b.flagSet.ErrorHandling() {
+	case flag.ExitOnError:
+		if errors.Is(err, flag.ErrHelp) {
+			b.exit(0)
+		} else {
+			b.exit(2)
+		}
+	case flag.PanicOnError:
+		panic(err)
+	}
+	return err
+}
+
+// rewriteArgs rewrites command-line arguments into the form the flag set
+// expects, for features the flag package doesn't support itself.
+func (b *FlagBuilder) rewriteArgs(args []string) ([]string, error) {
+	out := make([]string, 0, len(args)+1)
+	var positionals []string
+	b.unknown = []string{}
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		if arg != "--" && (len(arg) < 2 || arg[0] != '-') && b.interspersed && !b.stopOnNonFlag {
+			positionals = append(positionals, arg)
+			continue
+		}
+		if arg == "--" || len(arg) < 2 || arg[0] != '-' {
+			return withPositionals(out, positionals, args[i:]), nil
+		}
+		if strings.HasPrefix(arg, "--") && b.abbreviations {
+			name, value, hasValue := strings.Cut(arg[2:], "=")
+			full, err := b.expandAbbreviation(name)
+			if err != nil {
+				return nil, err
+			}
+			arg = "--" + full
+			if hasValue {
+				arg += "=" + value
+			}
+		}
+		if b.unknownMode != ErrorOnUnknown && b.isUnknownFlag(arg) {
+			if b.unknownMode == CollectUnknown {
+				b.unknown = append(b.unknown, arg)
+			}
+			continue
+		}
+		expanded := b.expandShortCluster(arg)
+		arg = b.addOptionalValue(expanded[len(expanded)-1])
+		expanded[len(expanded)-1] = arg
+		out = append(out, expanded...)
+		if f := b.flagSet.Lookup(flagArgName(arg)); f != nil && !strings.Contains(arg, "=") && !isBoolValue(f.Value) && i+1 < len(args) {
+			i++
+			out = append(out, args[i])
+		}
+	}
+	return withPositionals(out, positionals, nil), nil
+}
+
+// withPositionals appends the positional arguments moved out from between the
+// flags in interspersed mode, followed by the rest of the arguments. A "--"
+// keeps the flag set from parsing them as flags.
+func withPositionals(out, positionals, rest []string) []string {
+	if len(positionals) == 0 {
+		return append(out, rest...)
+	}
+	if len(rest) > 0 && rest[0] == "--" {
+		rest = rest[1:]
+	}
+	out = append(out, "--")
+	out = append(out, positionals...)
+	return append(out, rest...)
+}
+
+// UnknownFlagMode is how Parse handles flags that weren't defined.
+type UnknownFlagMode int
+
+const (
+	ErrorOnUnknown UnknownFlagMode = iota // fail the parse, the default
+	IgnoreUnknown                         // skip unknown flags
+	CollectUnknown                        // skip unknown flags and keep them for UnknownArgs
+)
+
+// UnknownFlags sets how Parse handles flags that weren't defined. Ignored and
+// collected flags don't consume the next argument, so they should carry their
+// values as --name=value.
+func (b *FlagBuilder) UnknownFlags(mode UnknownFlagMode) {
+	b.unknownMode = mode
+}
+
+// UnknownArgs returns the unknown flags collected by the last Parse in
+// CollectUnknown mode, in order, for passing through to another program.
+func (b *FlagBuilder) UnknownArgs() []string {
+	return b.unknown
+}
+
+// isUnknownFlag reports whether the flag argument arg names no defined flag,
+// either alone or as a cluster of short flags. The help flags are known, so
+// they still request help.
+func (b *FlagBuilder) isUnknownFlag(arg string) bool {
+	name := flagArgName(arg)
+	if name == "help" || name == "h" || b.flagSet.Lookup(name) != nil {
+		return false
+	}
+	expanded := b.expandShortCluster(arg)
+	return len(expanded) == 1 && expanded[0] == arg
+}
+
+// Interspersed lets flags follow positional arguments, as in
+// "tool file.txt --verbose", the way GNU getopt permutes arguments. The
+// positional arguments keep their order, and "--" still ends the flags. It is
+// off by default, and StopOnNonFlag overrides it. Commands with subcommands
+// shouldn't enable it, since the subcommand's flags would be parsed as the
+// parent's.
+func (b *FlagBuilder) Interspersed(enabled bool) {
+	b.interspersed = enabled
+}
+
+// expandShortCluster splits a cluster of short flags like "-vqf" into "-v",
+// "-q", and "-f". Characters after a flag that takes a value are its value, so
+// "-vffile" is "-v" and "-f=file". Arguments that name a flag themselves, or
+// that contain a character that isn't a short flag, are returned as is.
+func (b *FlagBuilder) expandShortCluster(arg string) []string {
+	if len(arg) < 3 || arg[0] != '-' || arg[1] == '-' || strings.Contains(arg, "=") {
+		return []string{arg}
+	}
+	name := arg[1:]
+	if b.flagSet.Lookup(name) != nil {
+		return []string{arg}
+	}
+	var out []string
+	for i, r := range name {
+		short := string(r)
+		f := b.flagSet.Lookup(short)
+		if f == nil {
+			return []string{arg}
+		}
+		if isBoolValue(f.Value) {
+			out = append(out, "-"+short)
+			continue
+		}
+		if value := name[i+len(short):]; value != "" {
+			return append(out, "-"+short+"="+value)
+		}
+		return append(out, "-"+short)
+	}
+	return out
+}
+
+// addOptionalValue adds the optional value to an argument like "--color" for
+// a flag given without a value, making it "--color=auto".
+func (b *FlagBuilder) addOptionalValue(arg string) string {
+	if strings.Contains(arg, "=") {
+		return arg
+	}
+	f := b.flagSet.Lookup(flagArgName(arg))
+	if f == nil {
+		return arg
+	}
+	if ov, ok := f.Value.(interface{ optionalValue() (string, bool) }); ok {
+		if val, ok := ov.optionalValue(); ok {
+			return arg + "=" + val
+		}
+	}
+	return arg
+}
+
+// flagArgName returns the flag name in a command-line argument like "--name=x".
+func flagArgName(arg string) string {
+	name, _, _ := strings.Cut(strings.TrimLeft(arg, "-"), "=")
+	return name
+}
+
+// EnableAbbreviations lets Parse accept any unambiguous prefix of a long flag
+// name, so --verb means --verbose unless another flag also starts with "verb".
+func (b *FlagBuilder) EnableAbbreviations(enabled bool) {
+	b.abbreviations = enabled
+}
+
+// ReserveAbbreviation pins an abbreviation to a flag when abbreviations are
+// enabled, even if the prefix also matches other flags. This keeps established
+// short forms working as new flags are added.
+func (b *FlagBuilder) ReserveAbbreviation(prefix, flagName string) {
+	if b.reserved == nil {
+		b.reserved = map[string]string{}
+	}
+	b.reserved[prefix] = flagName
+}
+
+// expandAbbreviation returns the full flag name that name abbreviates. Names
+// that match no flag are returned unchanged for the flag set to report.
+func (b *FlagBuilder) expandAbbreviation(name string) (string, error) {
+	if b.flagSet.Lookup(name) != nil {
+		return name, nil
+	}
+	if full, ok := b.reserved[name]; ok {
+		return full, nil
+	}
+	var matches []string
+	b.flagSet.VisitAll(func(f *flag.Flag) {
+		if len(f.Name) > 1 && strings.HasPrefix(f.Name, name) {
+			matches = append(matches, "--"+f.Name)
+		}
+	})
+	switch len(matches) {
+	case 0:
+		return name, nil
+	case 1:
+		return matches[0][2:], nil
+	default:
+		return "", newFlagError(ErrUnknownFlag, name, "", "ambiguous flag --%s could be %s", name, strings.Join(matches, ", "))
+	}
+}
@@ -0,0 +1,508 @@
+//go:build go1.18
+
+package fluentflag
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"io"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+// slowValue is a flag.Value whose Set takes a while.
+type slowValue struct {
+	delay time.Duration
+	val   string
+}
+
+func (v *slowValue) String() string { return v.val }
+func (v *slowValue) Set(s string) error {
+	time.Sleep(v.delay)
+	v.val = s
+	return nil
+}
+
+func TestFlagBuilder_Parse(t *testing.T) {
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	b := NewFlagBuilderWithSet(fs)
+	name := b.StringFlag("name", "name").BuildVar()
+	rest, err := b.Parse([]string{"--name=foo", "a", "b"})
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	if *name != "foo" {
+		t.Errorf("expected 'foo', got %q", *name)
+	}
+	if !reflect.DeepEqual(rest, []string{"a", "b"}) {
+		t.Errorf("expected [a b], got %v", rest)
+	}
+}
+
+func TestFlagBuilder_Parse_CombinedErrors(t *testing.T) {
+	b := NewFlagBuilderWithSet(flag.NewFlagSet("test", flag.ContinueOnError))
+	b.StringFlag("token", "API token").Required().BuildVar()
+	b.StringFlag("host", "hosts").MinCount(1).BuildSlice()
+	b.StringFlag("name", "name").BuildVar()
+	_, err := b.Parse([]string{"--name=foo"})
+	want := "--token is required\n--host requires at least 1 value, got 0"
+	if err == nil || err.Error() != want {
+		t.Fatalf("expected error %q, got %v", want, err)
+	}
+	var errs ValidationErrors
+	if !errors.As(err, &errs) || len(errs) != 2 {
+		t.Errorf("expected two ValidationErrors, got %#v", err)
+	}
+	// Is and As match the wrapped errors without Unwrap() []error, which
+	// errors.Is and errors.As only use from Go 1.20.
+	if !errs.Is(ErrMissingRequired) || !errs.Is(ErrConstraintViolated) || errs.Is(ErrUnknownFlag) {
+		t.Errorf("expected Is to match the wrapped errors, got %#v", errs)
+	}
+	var fe *FlagError
+	if !errs.As(&fe) || fe.Flag != "token" {
+		t.Errorf("expected As to find --token's FlagError, got %#v", fe)
+	}
+}
+
+func TestSetParseTimeout(t *testing.T) {
+	tests := []struct {
+		name    string
+		delay   time.Duration
+		wantErr bool
+	}{
+		{"slow", 500 * time.Millisecond, true},
+		{"fast", 0, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fs := flag.NewFlagSet("test", flag.ContinueOnError)
+			fs.SetOutput(io.Discard)
+			b := NewFlagBuilderWithSet(fs)
+			b.SetParseTimeout(50 * time.Millisecond)
+			fs.Var(&slowValue{delay: tt.delay}, "source", "slow value source")
+			_, err := b.Parse([]string{"--source=x"})
+			if tt.wantErr {
+				if err == nil || !strings.Contains(err.Error(), "timed out") {
+					t.Errorf("expected timeout error, got %v", err)
+				}
+				if !errors.Is(err, context.DeadlineExceeded) {
+					t.Errorf("expected error to wrap context.DeadlineExceeded, got %v", err)
+				}
+			} else if err != nil {
+				t.Errorf("unexpected error: %v", err)
+			}
+		})
+	}
+}
+
+func TestParseContext_Canceled(t *testing.T) {
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	b := NewFlagBuilderWithSet(fs)
+	fs.Var(&slowValue{delay: 500 * time.Millisecond}, "source", "slow value source")
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err := b.ParseContext(ctx, []string{"--source=x"})
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("expected context.Canceled, got %v", err)
+	}
+}
+
+func TestParseContext_ReuseAfterAbort(t *testing.T) {
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	b := NewFlagBuilderWithSet(fs)
+	source := &slowValue{delay: 200 * time.Millisecond}
+	fs.Var(source, "source", "slow value source")
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := b.ParseContext(ctx, []string{"--source=x"}); !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected context.Canceled, got %v", err)
+	}
+	if _, err := b.Parse(nil); err == nil || !strings.Contains(err.Error(), "aborted parse is still running") {
+		t.Errorf("expected Parse to fail while the aborted parse runs, got %v", err)
+	}
+	b.Reset()
+	if _, err := b.Parse(nil); err != nil {
+		t.Errorf("expected Parse to work after Reset, got %v", err)
+	}
+}
+
+func TestParseContext_DispatchAfterAbort(t *testing.T) {
+	fs := flag.NewFlagSet("app", flag.ContinueOnError)
+	app := NewAppWithSet(fs)
+	fs.Var(&slowValue{delay: 200 * time.Millisecond}, "source", "slow value source")
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := app.ParseContext(ctx, []string{"--source=x"}); !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected context.Canceled, got %v", err)
+	}
+	if _, _, err := app.Dispatch(nil); err == nil || !strings.Contains(err.Error(), "aborted parse is still running") {
+		t.Errorf("expected Dispatch to fail while the aborted parse runs, got %v", err)
+	}
+	app.Reset()
+	if _, _, err := app.Dispatch(nil); err != nil {
+		t.Errorf("expected Dispatch to work after Reset, got %v", err)
+	}
+}
+
+func TestSetHelpOutput(t *testing.T) {
+	tests := []struct {
+		name     string
+		args     []string
+		wantHelp bool
+	}{
+		{"help", []string{"--help"}, true},
+		{"error", []string{"--bogus"}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fs := flag.NewFlagSet("test", flag.ContinueOnError)
+			fs.SetOutput(io.Discard)
+			b := NewFlagBuilderWithSet(fs)
+			b.StringFlag("name", "Command name").Alias('n').BuildVar()
+			var helpBuf, errBuf strings.Builder
+			b.SetHelpOutput(&helpBuf)
+			b.SetOutput(&errBuf)
+			_, err := b.Parse(tt.args)
+			if err == nil {
+				t.Fatal("expected an error")
+			}
+			if errors.Is(err, flag.ErrHelp) != tt.wantHelp {
+				t.Errorf("unexpected error: %v", err)
+			}
+			written, empty := &helpBuf, &errBuf
+			if !tt.wantHelp {
+				written, empty = &errBuf, &helpBuf
+			}
+			if !strings.Contains(written.String(), "--name string") {
+				t.Errorf("expected usage, got %q", written.String())
+			}
+			if empty.String() != "" {
+				t.Errorf("expected no output, got %q", empty.String())
+			}
+		})
+	}
+}
+
+func TestParse_ExitOnError(t *testing.T) {
+	tests := []struct {
+		name string
+		args []string
+		want []int
+	}{
+		{"help", []string{"-h"}, []int{0}},
+		{"error", []string{"--bogus"}, []int{2}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fs := flag.NewFlagSet("test", flag.ExitOnError)
+			fs.SetOutput(io.Discard)
+			b := NewFlagBuilderWithSet(fs)
+			b.SetOutput(io.Discard)
+			b.SetHelpOutput(io.Discard)
+			b.StringFlag("name", "name").BuildVar()
+			var codes []int
+			b.SetExitFunc(func(code int) { codes = append(codes, code) })
+			b.Parse(tt.args)
+			if !reflect.DeepEqual(codes, tt.want) {
+				t.Errorf("expected exit codes %v, got %v", tt.want, codes)
+			}
+		})
+	}
+}
+
+func newAbbrevBuilder() (*FlagBuilder, *bool, *bool, *string) {
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+	b := NewFlagBuilderWithSet(fs)
+	b.SetOutput(io.Discard)
+	b.EnableAbbreviations(true)
+	verbose := b.BoolFlag("verbose", "be verbose").BuildVar()
+	version := b.BoolFlag("version", "print version").BuildVar()
+	name := b.StringFlag("name", "name").Alias('n').BuildVar()
+	return b, verbose, version, name
+}
+
+func TestEnableAbbreviations(t *testing.T) {
+	b, verbose, _, name := newAbbrevBuilder()
+	rest, err := b.Parse([]string{"--verb", "--na", "--version", "x"})
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	if !*verbose || *name != "--version" || !reflect.DeepEqual(rest, []string{"x"}) {
+		t.Errorf("unexpected result: verbose=%v name=%q rest=%v", *verbose, *name, rest)
+	}
+
+	b, _, _, _ = newAbbrevBuilder()
+	_, err = b.Parse([]string{"--ver"})
+	if err == nil || !strings.Contains(err.Error(), "ambiguous flag --ver could be --verbose, --version") {
+		t.Errorf("expected ambiguity error, got %v", err)
+	}
+}
+
+func TestReserveAbbreviation(t *testing.T) {
+	b, verbose, version, _ := newAbbrevBuilder()
+	b.ReserveAbbreviation("ver", "version")
+	if _, err := b.Parse([]string{"--ver"}); err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	if !*version || *verbose {
+		t.Errorf("expected --ver to set --version only, got version=%v verbose=%v", *version, *verbose)
+	}
+
+	b, _, _, _ = newAbbrevBuilder()
+	b.ReserveAbbreviation("ver", "version")
+	_, err := b.Parse([]string{"--v"})
+	if err == nil || !strings.Contains(err.Error(), "ambiguous flag --v") {
+		t.Errorf("expected ambiguity error for unreserved prefix, got %v", err)
+	}
+}
+
+func TestCollectAssignments(t *testing.T) {
+	tests := []struct {
+		name            string
+		enabled         bool
+		wantAssignments map[string]string
+		wantRest        []string
+	}{
+		{"enabled", true, map[string]string{"FOO": "bar", "EMPTY": ""}, []string{"baz"}},
+		{"disabled", false, map[string]string{}, []string{"FOO=bar", "baz", "EMPTY="}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b := NewFlagBuilderWithSet(flag.NewFlagSet("test", flag.ContinueOnError))
+			b.BoolFlag("verbose", "be verbose").BuildVar()
+			b.CollectAssignments(tt.enabled)
+			rest, err := b.Parse([]string{"--verbose", "FOO=bar", "baz", "EMPTY="})
+			if err != nil {
+				t.Fatalf("Parse failed: %v", err)
+			}
+			if !reflect.DeepEqual(b.Assignments(), tt.wantAssignments) {
+				t.Errorf("expected assignments %v, got %v", tt.wantAssignments, b.Assignments())
+			}
+			if !reflect.DeepEqual(rest, tt.wantRest) {
+				t.Errorf("expected positionals %v, got %v", tt.wantRest, rest)
+			}
+		})
+	}
+}
+
+func TestShortFlagClusters(t *testing.T) {
+	tests := []struct {
+		name     string
+		args     []string
+		wantV    bool
+		wantQ    bool
+		wantFile string
+		wantRest []string
+		wantErr  string
+	}{
+		{"bools", []string{"-vq"}, true, true, "", []string{}, ""},
+		{"value next", []string{"-vqf", "out.txt", "x"}, true, true, "out.txt", []string{"x"}, ""},
+		{"value attached", []string{"-vfout.txt"}, true, false, "out.txt", []string{}, ""},
+		{"long flag name", []string{"-vq", "-qv", "--fq=a"}, true, true, "", []string{}, ""},
+		{"unknown short", []string{"-vx"}, false, false, "", nil, "flag provided but not defined: -vx"},
+		{"after terminator", []string{"--", "-vq"}, false, false, "", []string{"-vq"}, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fs := flag.NewFlagSet("test", flag.ContinueOnError)
+			fs.SetOutput(io.Discard)
+			b := NewFlagBuilderWithSet(fs)
+			b.SetOutput(io.Discard)
+			verbose := b.BoolFlag("verbose", "more output").Alias('v').BuildVar()
+			quiet := b.BoolFlag("quiet", "less output").Alias('q').BuildVar()
+			file := b.StringFlag("file", "output file").Alias('f').BuildVar()
+			b.StringFlag("fq", "a flag named like a cluster").BuildVar()
+			rest, err := b.Parse(tt.args)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Errorf("expected error containing %q, got %v", tt.wantErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if *verbose != tt.wantV || *quiet != tt.wantQ || *file != tt.wantFile {
+				t.Errorf("expected v=%v q=%v file=%q, got v=%v q=%v file=%q", tt.wantV, tt.wantQ, tt.wantFile, *verbose, *quiet, *file)
+			}
+			if !reflect.DeepEqual(rest, tt.wantRest) {
+				t.Errorf("expected rest %v, got %v", tt.wantRest, rest)
+			}
+		})
+	}
+}
+
+func TestOptionalValue(t *testing.T) {
+	tests := []struct {
+		name      string
+		args      []string
+		wantColor string
+		wantLevel int
+		wantRest  []string
+	}{
+		{"unset", []string{}, "never", 0, []string{}},
+		{"bare", []string{"--color", "file"}, "auto", 0, []string{"file"}},
+		{"explicit", []string{"--color=always"}, "always", 0, []string{}},
+		{"short bare", []string{"-c", "-l"}, "auto", 3, []string{}},
+		{"short attached", []string{"-l5"}, "never", 5, []string{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b := NewFlagBuilderWithSet(flag.NewFlagSet("test", flag.ContinueOnError))
+			color := b.StringFlag("color", "colorize output").Alias('c').Default("never").OptionalValue("auto").BuildVar()
+			level := b.IntFlag("level", "compression level").Alias('l').OptionalValue(3).BuildVar()
+			rest, err := b.Parse(tt.args)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if *color != tt.wantColor || *level != tt.wantLevel {
+				t.Errorf("expected color=%q level=%d, got color=%q level=%d", tt.wantColor, tt.wantLevel, *color, *level)
+			}
+			if !reflect.DeepEqual(rest, tt.wantRest) {
+				t.Errorf("expected rest %v, got %v", tt.wantRest, rest)
+			}
+		})
+	}
+}
+
+func TestOptionalValue_Usage(t *testing.T) {
+	b := NewFlagBuilderWithSet(flag.NewFlagSet("test", flag.ContinueOnError))
+	f := b.StringFlag("color", "colorize output").Default("never").OptionalValue("auto")
+	f.BuildVar()
+	want := `      --color string[="auto"]` + "\n" + strings.Repeat(" ", 27) + `colorize output (default "never")`
+	if got := f.Usage(); got != want {
+		t.Errorf("expected %q, got %q", want, got)
+	}
+}
+
+func TestStopOnNonFlag(t *testing.T) {
+	tests := []struct {
+		name     string
+		stop     bool
+		args     []string
+		wantRest []string
+		wantEnv  map[string]string
+	}{
+		{"wrapped command", true, []string{"-v", "FOO=1", "ls", "-la", "BAR=2"}, []string{"ls", "-la", "BAR=2"}, map[string]string{"FOO": "1"}},
+		{"terminator", true, []string{"--", "-v", "x"}, []string{"-v", "x"}, map[string]string{}},
+		{"collect everywhere", false, []string{"-v", "FOO=1", "ls", "BAR=2"}, []string{"ls"}, map[string]string{"FOO": "1", "BAR": "2"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b := NewFlagBuilderWithSet(flag.NewFlagSet("test", flag.ContinueOnError))
+			b.BoolFlag("verbose", "more output").Alias('v').BuildVar()
+			b.CollectAssignments(true)
+			if tt.stop {
+				b.StopOnNonFlag()
+			}
+			rest, err := b.Parse(tt.args)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(rest, tt.wantRest) {
+				t.Errorf("expected rest %v, got %v", tt.wantRest, rest)
+			}
+			if !reflect.DeepEqual(b.Assignments(), tt.wantEnv) {
+				t.Errorf("expected assignments %v, got %v", tt.wantEnv, b.Assignments())
+			}
+		})
+	}
+}
+
+func TestStopOnNonFlag_Interspersed(t *testing.T) {
+	b := NewFlagBuilderWithSet(flag.NewFlagSet("test", flag.ContinueOnError))
+	verbose := b.BoolFlag("verbose", "more output").Alias('v').BuildVar()
+	b.Interspersed(true)
+	b.StopOnNonFlag()
+	rest, err := b.Parse([]string{"-v", "ls", "--verbose", "-la"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !*verbose {
+		t.Error("expected --verbose before the command to be parsed")
+	}
+	if want := []string{"ls", "--verbose", "-la"}; !reflect.DeepEqual(rest, want) {
+		t.Errorf("expected rest %v, got %v", want, rest)
+	}
+}
+
+func TestInterspersed(t *testing.T) {
+	tests := []struct {
+		name        string
+		enabled     bool
+		args        []string
+		wantVerbose bool
+		wantOut     string
+		wantRest    []string
+	}{
+		{"flags after", true, []string{"a.txt", "--verbose", "b.txt", "-o", "out"}, true, "out", []string{"a.txt", "b.txt"}},
+		{"terminator", true, []string{"a.txt", "--", "--verbose"}, false, "", []string{"a.txt", "--verbose"}},
+		{"dash", true, []string{"-", "-v"}, true, "", []string{"-"}},
+		{"disabled", false, []string{"a.txt", "--verbose"}, false, "", []string{"a.txt", "--verbose"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b := NewFlagBuilderWithSet(flag.NewFlagSet("test", flag.ContinueOnError))
+			verbose := b.BoolFlag("verbose", "more output").Alias('v').BuildVar()
+			out := b.StringFlag("output", "output file").Alias('o').BuildVar()
+			b.Interspersed(tt.enabled)
+			rest, err := b.Parse(tt.args)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if *verbose != tt.wantVerbose || *out != tt.wantOut {
+				t.Errorf("expected verbose=%v output=%q, got verbose=%v output=%q", tt.wantVerbose, tt.wantOut, *verbose, *out)
+			}
+			if !reflect.DeepEqual(rest, tt.wantRest) {
+				t.Errorf("expected rest %v, got %v", tt.wantRest, rest)
+			}
+		})
+	}
+}
+
+func TestUnknownFlags(t *testing.T) {
+	tests := []struct {
+		name        string
+		mode        UnknownFlagMode
+		args        []string
+		wantUnknown []string
+		wantRest    []string
+		wantErr     string
+	}{
+		{"error", ErrorOnUnknown, []string{"--depth=2", "-v"}, nil, nil, "flag provided but not defined: -depth"},
+		{"ignore", IgnoreUnknown, []string{"--depth=2", "-v", "-xz", "file"}, []string{}, []string{"file"}, ""},
+		{"collect", CollectUnknown, []string{"--depth=2", "-v", "-xz", "--", "--keep"}, []string{"--depth=2", "-xz"}, []string{"--keep"}, ""},
+		{"help still works", CollectUnknown, []string{"--help"}, nil, nil, flag.ErrHelp.Error()},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fs := flag.NewFlagSet("test", flag.ContinueOnError)
+			fs.SetOutput(io.Discard)
+			b := NewFlagBuilderWithSet(fs)
+			b.SetOutput(io.Discard)
+			b.SetHelpOutput(io.Discard)
+			verbose := b.BoolFlag("verbose", "more output").Alias('v').BuildVar()
+			b.UnknownFlags(tt.mode)
+			rest, err := b.Parse(tt.args)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Errorf("expected error containing %q, got %v", tt.wantErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !*verbose {
+				t.Error("expected the known flag to be parsed")
+			}
+			if !reflect.DeepEqual(b.UnknownArgs(), tt.wantUnknown) || !reflect.DeepEqual(rest, tt.wantRest) {
+				t.Errorf("expected unknown %v rest %v, got unknown %v rest %v", tt.wantUnknown, tt.wantRest, b.UnknownArgs(), rest)
+			}
+		})
+	}
+}
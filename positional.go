@@ -0,0 +1,205 @@
+// positional.go
+// Copyright (c) 2025 mattmc3
+// SPDX-License-Identifier: MIT
+// Project home: https://github.com/mattmc3/fluentflag
+
+package fluentflag
+
+import "fmt"
+
+// PositionalArg builds a named positional argument, which is parsed from the
+// arguments left after the flags, in the order the arguments are built.
+type PositionalArg struct {
+	builder    *FlagBuilder
+	name       string
+	usage      string
+	required   bool
+	defaultVal string
+	variadic   bool               // whether the argument takes all remaining values
+	set        func(string) error // parses and stores one value
+	reset      func()             // restores the default value
+	rebuild    rebuildFunc        // defines the argument on a clone
+	given      bool               // whether the last Parse supplied a value
+}
+
+// Positional starts building a positional argument. Finish it with one of the
+// Build methods, such as BuildString.
+func (b *FlagBuilder) Positional(name, usage string) *PositionalArg {
+	return &PositionalArg{builder: b, name: name, usage: usage}
+}
+
+// Required makes Parse fail if the argument is missing.
+func (self *PositionalArg) Required() *PositionalArg {
+	self.required = true
+	return self
+}
+
+// Default sets the value used when the argument is missing. It is parsed as
+// the argument's type when the argument is built.
+func (self *PositionalArg) Default(value string) *PositionalArg {
+	self.defaultVal = value
+	return self
+}
+
+// BuildString registers a string argument and returns a pointer to its value.
+func (self *PositionalArg) BuildString() *string {
+	return buildPositional[string](self)
+}
+
+// BuildInt registers an int argument and returns a pointer to its value.
+func (self *PositionalArg) BuildInt() *int {
+	return buildPositional[int](self)
+}
+
+// BuildFloat64 registers a float64 argument and returns a pointer to its value.
+func (self *PositionalArg) BuildFloat64() *float64 {
+	return buildPositional[float64](self)
+}
+
+// BuildBool registers a bool argument and returns a pointer to its value.
+func (self *PositionalArg) BuildBool() *bool {
+	return buildPositional[bool](self)
+}
+
+// BuildStrings registers an argument that takes all remaining arguments, like
+// the files of "cat file...". It must be the last positional argument.
+func (self *PositionalArg) BuildStrings() *[]string {
+	vals := new([]string) // allocate on heap
+	*vals = []string{}
+	self.variadic = true
+	self.set = func(s string) error {
+		*vals = append(*vals, s)
+		return nil
+	}
+	self.reset = func() {
+		*vals = []string{}
+	}
+	self.rebuild = func(b *FlagBuilder) {
+		clone := *self
+		clone.builder, clone.given = b, false
+		clone.BuildStrings()
+	}
+	self.register()
+	return vals
+}
+
+// buildPositional registers a positional argument of type T.
+func buildPositional[T FlagType](p *PositionalArg) *T {
+	v := new(T) // allocate on heap
+	var def T
+	if p.defaultVal != "" {
+		var err error
+		if def, err = parse[T](p.defaultVal); err != nil {
+			p.builder.fail(fmt.Errorf("fluentflag: invalid default %q for argument <%s>: %v", p.defaultVal, p.name, err))
+			return v
+		}
+		*v = def
+	}
+	p.set = func(s string) error {
+		parsed, err := parse[T](s)
+		if err != nil {
+			return err
+		}
+		*v = parsed
+		return nil
+	}
+	p.reset = func() {
+		*v = def
+	}
+	p.rebuild = func(b *FlagBuilder) {
+		clone := *p
+		clone.builder, clone.given = b, false
+		buildPositional[T](&clone)
+	}
+	p.register()
+	return v
+}
+
+// register adds the argument to the builder, checking that it can follow the
+// arguments built before it.
+func (self *PositionalArg) register() {
+	if n := len(self.builder.positionals); n > 0 {
+		prev := self.builder.positionals[n-1]
+		if prev.variadic {
+			self.builder.fail(fmt.Errorf("fluentflag: argument <%s> follows variadic argument <%s>", self.name, prev.name))
+			return
+		}
+		if self.required && !prev.required {
+			self.builder.fail(fmt.Errorf("fluentflag: required argument <%s> follows optional argument <%s>", self.name, prev.name))
+			return
+		}
+	}
+	self.builder.positionals = append(self.builder.positionals, self)
+}
+
+// usageLine renders the argument's usage line.
+func (self *PositionalArg) usageLine(width int) string {
+	name := self.name
+	if self.variadic {
+		name += "..."
+	}
+	desc := self.usage
+	if self.required {
+		desc += " (required)"
+	}
+	if self.defaultVal != "" {
+		desc += " (default " + self.defaultVal + ")"
+	}
+	return formatUsageLine(name, desc, width)
+}
+
+// assignPositionals parses the declared positional arguments from the
+// arguments left after the flags, leaving the rest for Args.
+func (b *FlagBuilder) assignPositionals() error {
+	for _, p := range b.positionals {
+		p.given = false
+		for len(b.rest) > 0 {
+			if err := p.set(b.rest[0]); err != nil {
+				return &FlagError{Kind: ErrInvalidValue, Flag: p.name, Value: b.rest[0], Err: err,
+					msg: fmt.Sprintf("invalid value %q for argument <%s>: %v", b.rest[0], p.name, err)}
+			}
+			b.rest = b.rest[1:]
+			p.given = true
+			if !p.variadic {
+				break
+			}
+		}
+	}
+	return nil
+}
+
+// MinArgs makes Parse fail unless at least n non-flag arguments are given,
+// counting declared positional arguments.
+func (b *FlagBuilder) MinArgs(n int) {
+	b.minArgs = n
+}
+
+// MaxArgs makes Parse fail if more than n non-flag arguments are given,
+// counting declared positional arguments.
+func (b *FlagBuilder) MaxArgs(n int) {
+	b.maxArgs = n
+	b.limitArgs = true
+}
+
+// validatePositionals returns an error for each missing required argument and
+// for a number of arguments outside the MinArgs and MaxArgs bounds.
+func (b *FlagBuilder) validatePositionals() []error {
+	var errs []error
+	if b.argCount < b.minArgs {
+		errs = append(errs, newFlagError(ErrConstraintViolated, "", "", "expected at least %d %s, got %d", b.minArgs, plural(b.minArgs, "argument"), b.argCount))
+	} else if b.limitArgs && b.argCount > b.maxArgs {
+		errs = append(errs, newFlagError(ErrConstraintViolated, "", "", "expected at most %d %s, got %d", b.maxArgs, plural(b.maxArgs, "argument"), b.argCount))
+	}
+	for _, p := range b.positionals {
+		if p.required && !p.given {
+			errs = append(errs, newFlagError(ErrMissingRequired, p.name, "", "missing argument <%s>", p.name))
+		}
+	}
+	return errs
+}
+
+// Args returns the arguments left by the last Parse after the flags and the
+// declared positional arguments.
+func (b *FlagBuilder) Args() []string {
+	return b.rest
+}
@@ -0,0 +1,131 @@
+//go:build go1.18
+
+package fluentflag
+
+import (
+	"flag"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestPositional(t *testing.T) {
+	tests := []struct {
+		name      string
+		args      []string
+		wantFile  string
+		wantCount int
+		wantRest  []string
+		wantErr   string
+	}{
+		{"all", []string{"in.txt", "3", "x"}, "in.txt", 3, []string{"x"}, ""},
+		{"default", []string{"in.txt"}, "in.txt", 1, []string{}, ""},
+		{"after flags", []string{"--verbose", "in.txt", "2"}, "in.txt", 2, []string{}, ""},
+		{"missing", []string{}, "", 1, nil, "missing argument <file>"},
+		{"invalid", []string{"in.txt", "many"}, "", 0, nil, `invalid value "many" for argument <count>`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b := NewFlagBuilderWithSet(flag.NewFlagSet("test", flag.ContinueOnError))
+			b.BoolFlag("verbose", "more output").BuildVar()
+			file := b.Positional("file", "input file").Required().BuildString()
+			count := b.Positional("count", "number of copies").Default("1").BuildInt()
+			rest, err := b.Parse(tt.args)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Errorf("expected error containing %q, got %v", tt.wantErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if *file != tt.wantFile || *count != tt.wantCount {
+				t.Errorf("expected %q %d, got %q %d", tt.wantFile, tt.wantCount, *file, *count)
+			}
+			if !reflect.DeepEqual(rest, tt.wantRest) || !reflect.DeepEqual(b.Args(), tt.wantRest) {
+				t.Errorf("expected rest %v, got %v and Args %v", tt.wantRest, rest, b.Args())
+			}
+		})
+	}
+}
+
+func TestPositional_Variadic(t *testing.T) {
+	b := NewFlagBuilderWithSet(flag.NewFlagSet("test", flag.ContinueOnError))
+	dest := b.Positional("dest", "destination").Required().BuildString()
+	files := b.Positional("file", "files to copy").BuildStrings()
+	if _, err := b.Parse([]string{"out", "a", "b"}); err != nil {
+		t.Fatal(err)
+	}
+	if *dest != "out" || !reflect.DeepEqual(*files, []string{"a", "b"}) || len(b.Args()) != 0 {
+		t.Errorf("unexpected values %q %v %v", *dest, *files, b.Args())
+	}
+	want := `Arguments:
+  dest                     destination (required)
+  file...                  files to copy
+`
+	if got := b.UsageStringWidth(80); got != want {
+		t.Errorf("expected usage:\n%s\ngot:\n%s", want, got)
+	}
+}
+
+func TestPositional_OrderPanics(t *testing.T) {
+	tests := []struct {
+		name  string
+		build func(b *FlagBuilder)
+	}{
+		{"required after optional", func(b *FlagBuilder) {
+			b.Positional("a", "a").BuildString()
+			b.Positional("b", "b").Required().BuildString()
+		}},
+		{"after variadic", func(b *FlagBuilder) {
+			b.Positional("a", "a").BuildStrings()
+			b.Positional("b", "b").BuildString()
+		}},
+		{"invalid default", func(b *FlagBuilder) {
+			b.Positional("a", "a").Default("x").BuildInt()
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			defer func() {
+				if r := recover(); r == nil {
+					t.Error("expected panic")
+				}
+			}()
+			tt.build(NewFlagBuilderWithSet(flag.NewFlagSet("test", flag.ContinueOnError)))
+		})
+	}
+}
+
+func TestMinArgsAndMaxArgs(t *testing.T) {
+	tests := []struct {
+		name    string
+		min     int
+		max     int
+		args    []string
+		wantErr string
+	}{
+		{"within", 1, 2, []string{"a", "b"}, ""},
+		{"too few", 1, 2, []string{"--verbose"}, "expected at least 1 argument, got 0"},
+		{"too many", 1, 2, []string{"a", "b", "c"}, "expected at most 2 arguments, got 3"},
+		{"none allowed", 0, 0, []string{"a"}, "expected at most 0 arguments, got 1"},
+		{"after terminator", 0, 1, []string{"--", "-a"}, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b := NewFlagBuilderWithSet(flag.NewFlagSet("test", flag.ContinueOnError))
+			b.BoolFlag("verbose", "more output").BuildVar()
+			b.MinArgs(tt.min)
+			b.MaxArgs(tt.max)
+			_, err := b.Parse(tt.args)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("unexpected error: %v", err)
+				}
+			} else if err == nil || err.Error() != tt.wantErr {
+				t.Errorf("expected error %q, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
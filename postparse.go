@@ -0,0 +1,118 @@
+// postparse.go
+// Copyright (c) 2025 mattmc3
+// SPDX-License-Identifier: MIT
+// Project home: https://github.com/mattmc3/fluentflag
+
+package fluentflag
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// PostParse registers fn to run after Parse has parsed and validated the
+// flags. Hooks run in the order they were registered, and the first error
+// stops Parse.
+func (b *FlagBuilder) PostParse(fn func() error) {
+	b.postParse = append(b.postParse, func(*FlagBuilder) error {
+		return fn()
+	})
+}
+
+// parseHook is run after Parse with the builder that was parsed, which is a
+// clone of the one it was added to after Clone.
+type parseHook func(b *FlagBuilder) error
+
+// ScaleByUnit multiplies the numeric value of valueFlag by the factor that
+// units maps the value of unitFlag to, after parsing. For example, with units
+// {"seconds": 1, "minutes": 60}, --value=5 --unit=minutes stores 300. Integer
+// flags are rounded to the nearest whole number.
+func (b *FlagBuilder) ScaleByUnit(valueFlag, unitFlag string, units map[string]float64) {
+	b.postParse = append(b.postParse, func(b *FlagBuilder) error {
+		val, unit := b.lookup(valueFlag), b.lookup(unitFlag)
+		if val == nil {
+			return fmt.Errorf("fluentflag: cannot scale unknown flag --%s", valueFlag)
+		}
+		if unit == nil {
+			return fmt.Errorf("fluentflag: cannot scale --%s by unknown flag --%s", valueFlag, unitFlag)
+		}
+		name := strings.Join(unit.values(), ",")
+		factor, ok := units[name]
+		if !ok {
+			var known []string
+			for u := range units {
+				known = append(known, u)
+			}
+			sort.Strings(known)
+			return fmt.Errorf("--%s has an unknown unit %q (want one of [%s])", unitFlag, name, strings.Join(known, " "))
+		}
+		return val.scale(factor)
+	})
+}
+
+// Disables forces the named flags to their zero value after parsing whenever
+// this flag is set, as for a --minimal mode that turns features off. A warning
+// is written to the output (see SetOutput) for each disabled flag that was
+// also set explicitly.
+func (self *FluentFlag[T]) Disables(names ...string) *FluentFlag[T] {
+	flagName := self.name
+	self.builder.postParse = append(self.builder.postParse, func(b *FlagBuilder) error {
+		if on := b.lookup(flagName); on == nil || !b.isOn(on) {
+			return nil
+		}
+		for _, name := range names {
+			f := b.lookup(name)
+			if f == nil {
+				return fmt.Errorf("fluentflag: --%s disables unknown flag --%s", flagName, name)
+			}
+			if b.isSet(f) {
+				fmt.Fprintf(b.outputWriter(), "warning: --%s is ignored because --%s is set\n", name, flagName)
+			}
+			f.setZero()
+		}
+		return nil
+	})
+	return self
+}
+
+// isOn reports whether f was set to something other than false.
+func (b *FlagBuilder) isOn(f builtFlag) bool {
+	return b.isSet(f) && strings.Join(f.values(), ",") != "false"
+}
+
+// setZero sets the flag's storage to its zero value.
+func (self *FluentFlag[T]) setZero() {
+	self.value.setZero()
+}
+
+// scale multiplies the flag's numeric value by factor.
+func (self *FluentFlag[T]) scale(factor float64) error {
+	fv, ok := self.value.(*flagValue[T])
+	if !ok {
+		return fmt.Errorf("fluentflag: cannot scale --%s, it holds more than one value", self.name)
+	}
+	n, ok := toFloat64(*fv.target)
+	if !ok {
+		return fmt.Errorf("fluentflag: cannot scale --%s, it is not numeric", self.name)
+	}
+	*fv.target = fromFloat64[T](n * factor)
+	return nil
+}
+
+// fromFloat64 converts n to the numeric type T, rounding for integer types.
+func fromFloat64[T FlagType](n float64) T {
+	var v T
+	rv := reflect.ValueOf(&v).Elem()
+	switch {
+	case rv.CanInt():
+		rv.SetInt(int64(math.Round(n)))
+	case rv.CanUint():
+		rv.SetUint(uint64(math.Round(n)))
+	case rv.CanFloat():
+		rv.SetFloat(n)
+	}
+	return v
+}
@@ -0,0 +1,86 @@
+//go:build go1.18
+
+package fluentflag
+
+import (
+	"errors"
+	"flag"
+	"strings"
+	"testing"
+)
+
+func TestPostParse(t *testing.T) {
+	b := NewFlagBuilderWithSet(flag.NewFlagSet("test", flag.ContinueOnError))
+	var calls []string
+	b.PostParse(func() error { calls = append(calls, "first"); return nil })
+	b.PostParse(func() error { calls = append(calls, "second"); return errors.New("stop") })
+	b.PostParse(func() error { calls = append(calls, "third"); return nil })
+	_, err := b.Parse(nil)
+	if err == nil || err.Error() != "stop" {
+		t.Errorf("expected hook error, got %v", err)
+	}
+	if strings.Join(calls, ",") != "first,second" {
+		t.Errorf("unexpected hook calls: %v", calls)
+	}
+}
+
+func TestScaleByUnit(t *testing.T) {
+	units := map[string]float64{"seconds": 1, "minutes": 60, "hours": 3600}
+	tests := []struct {
+		name    string
+		args    []string
+		want    int
+		wantErr string
+	}{
+		{"minutes", []string{"--value=5", "--unit=minutes"}, 300, ""},
+		{"default unit", []string{"--value=5"}, 5, ""},
+		{"unknown unit", []string{"--value=5", "--unit=fortnights"}, 0, `--unit has an unknown unit "fortnights" (want one of [hours minutes seconds])`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b := NewFlagBuilderWithSet(flag.NewFlagSet("test", flag.ContinueOnError))
+			value := b.IntFlag("value", "timeout value").BuildVar()
+			b.StringFlag("unit", "timeout unit").Default("seconds").BuildVar()
+			b.ScaleByUnit("value", "unit", units)
+			_, err := b.Parse(tt.args)
+			if tt.wantErr == "" {
+				if err != nil || *value != tt.want {
+					t.Errorf("expected %d, got %d (err %v)", tt.want, *value, err)
+				}
+			} else if err == nil || err.Error() != tt.wantErr {
+				t.Errorf("expected error %q, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestDisables(t *testing.T) {
+	tests := []struct {
+		name        string
+		args        []string
+		wantFeature bool
+		wantWarning string
+	}{
+		{"minimal overrides feature", []string{"--minimal", "--feature"}, false, "warning: --feature is ignored because --minimal is set\n"},
+		{"minimal alone", []string{"--minimal"}, false, ""},
+		{"feature alone", []string{"--feature"}, true, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b := NewFlagBuilderWithSet(flag.NewFlagSet("test", flag.ContinueOnError))
+			var out strings.Builder
+			b.SetOutput(&out)
+			feature := b.BoolFlag("feature", "enable the feature").Default(true).BuildVar()
+			b.BoolFlag("minimal", "turn off extras").Disables("feature").BuildVar()
+			if _, err := b.Parse(tt.args); err != nil {
+				t.Fatalf("Parse failed: %v", err)
+			}
+			if *feature != tt.wantFeature {
+				t.Errorf("expected feature %v, got %v", tt.wantFeature, *feature)
+			}
+			if out.String() != tt.wantWarning {
+				t.Errorf("expected warning %q, got %q", tt.wantWarning, out.String())
+			}
+		})
+	}
+}
@@ -0,0 +1,71 @@
+// reset.go
+// Copyright (c) 2025 mattmc3
+// SPDX-License-Identifier: MIT
+// Project home: https://github.com/mattmc3/fluentflag
+
+package fluentflag
+
+import "flag"
+
+// Reset restores every flag and positional argument to its default and
+// forgets what the last Parse set, including in subcommands, so the builder
+// can parse another command line, as in an interactive shell or a server
+// accepting command lines over RPC. Loaded config files and .env files are
+// kept. If a parse abandoned by ParseContext is still running, Reset waits
+// for it to finish first.
+//
+// The builder's *flag.FlagSet, such as flag.CommandLine, is reset in place,
+// so other references to it see the reset too. Flags defined on it directly
+// with the flag package are set back to their defaults as well.
+func (b *FlagBuilder) Reset() {
+	b.waitForParse()
+	for _, f := range b.flagsBuilt {
+		f.reset()
+	}
+	for _, p := range b.positionals {
+		p.reset()
+		p.given = false
+	}
+	// The flag set can't forget which flags were set, so it is replaced by a
+	// fresh one with the same flags, output, and usage. Flags the builder
+	// didn't make have no reset of their own, so they are set to their
+	// default text, and every flag keeps the default it was defined with.
+	fs := flag.NewFlagSet(b.flagSet.Name(), b.flagSet.ErrorHandling())
+	fs.SetOutput(b.flagSet.Output())
+	fs.Usage = b.flagSet.Usage
+	b.flagSet.VisitAll(func(fl *flag.Flag) {
+		switch fl.Value.(type) {
+		case fluentValue, *aliasValue, *completionValue:
+		default:
+			_ = fl.Value.Set(fl.DefValue)
+		}
+		fs.Var(fl.Value, fl.Name, fl.Usage)
+		fs.Lookup(fl.Name).DefValue = fl.DefValue
+	})
+	*b.flagSet = *fs
+	b.forgetParse()
+	for _, c := range b.commands {
+		c.Reset()
+	}
+}
+
+// forgetParse clears what the last Parse recorded about the command line.
+func (b *FlagBuilder) forgetParse() {
+	b.rest, b.assignments, b.unknown = nil, nil, nil
+	b.argCount = 0
+	b.sources = nil
+	b.setOnSubcommand = nil
+	b.setErr = nil
+	b.warnedRenames = nil
+}
+
+// reset restores the flag to the value it was built with.
+func (self *FluentFlag[T]) reset() {
+	self.value.setZero()
+	switch v := self.value.(type) {
+	case *flagValue[T]:
+		*v.target = self.defaultVal
+	case *timeValue:
+		*v.target = v.def
+	}
+}
@@ -0,0 +1,83 @@
+//go:build go1.18
+
+package fluentflag
+
+import (
+	"flag"
+	"reflect"
+	"testing"
+)
+
+func TestReset(t *testing.T) {
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	b := NewFlagBuilderWithSet(fs)
+	name := b.StringFlag("name", "name").Alias('n').Default("foo").Required().BuildVar()
+	tags := b.StringFlag("tag", "tags").BuildSlice()
+	labels := b.StringFlag("label", "labels").BuildMap()
+	verbose := b.BoolFlag("verbose", "more output").Alias('v').BuildCounter()
+	files := b.Positional("file", "files").BuildStrings()
+
+	if _, err := b.Parse([]string{"-n", "bar", "--tag=a", "--label=k=v", "-vv", "x", "y"}); err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	b.Reset()
+	if *name != "foo" || len(*tags) != 0 || len(*labels) != 0 || *verbose != 0 || len(*files) != 0 {
+		t.Fatalf("expected defaults after Reset, got %q %v %v %d %v", *name, *tags, *labels, *verbose, *files)
+	}
+	if b.Changed("name") || fs.Parsed() || len(b.Args()) != 0 {
+		t.Errorf("expected nothing set after Reset")
+	}
+
+	if _, err := b.Parse([]string{"--tag=b", "z"}); err == nil {
+		t.Fatal("expected missing --name after Reset")
+	}
+	b.Reset()
+	if _, err := b.Parse([]string{"--name=baz", "--tag=b", "z"}); err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	if *name != "baz" || !reflect.DeepEqual(*tags, []string{"b"}) || !reflect.DeepEqual(*files, []string{"z"}) {
+		t.Errorf("unexpected values after second Parse: %q %v %v", *name, *tags, *files)
+	}
+	if src := b.Source("tag"); src != SourceFlag {
+		t.Errorf("expected tag from %q, got %q", SourceFlag, src)
+	}
+}
+
+func TestReset_Subcommand(t *testing.T) {
+	app := NewAppWithSet(flag.NewFlagSet("app", flag.ContinueOnError))
+	debug := app.BoolFlag("debug", "debug mode").Persistent().BuildVar()
+	serve := app.Command("serve", "start the server")
+	port := serve.IntFlag("port", "port").Default(80).BuildVar()
+	if _, _, err := app.Dispatch([]string{"serve", "--debug", "--port=8080"}); err != nil {
+		t.Fatalf("Dispatch failed: %v", err)
+	}
+	app.Reset()
+	if *debug || *port != 80 || app.Changed("debug") || serve.Changed("port") {
+		t.Fatalf("expected defaults after Reset, got %v %d", *debug, *port)
+	}
+	if _, _, err := app.Dispatch([]string{"serve", "--port=9090"}); err != nil {
+		t.Fatalf("Dispatch failed: %v", err)
+	}
+	if *debug || *port != 9090 {
+		t.Errorf("unexpected values after second Dispatch: %v %d", *debug, *port)
+	}
+}
+
+func TestReset_StdlibFlags(t *testing.T) {
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	b := NewFlagBuilderWithSet(fs)
+	level := fs.Int("level", 3, "log level")
+	name := b.StringFlag("name", "name").Default("foo").BuildVar()
+	if _, err := b.Parse([]string{"--level=7", "--name=bar"}); err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	b.Reset()
+	if *level != 3 || *name != "foo" {
+		t.Errorf("expected defaults after Reset, got %d %q", *level, *name)
+	}
+	for flagName, want := range map[string]string{"level": "3", "name": "foo"} {
+		if got := fs.Lookup(flagName).DefValue; got != want {
+			t.Errorf("expected --%s to keep default %q after Reset, got %q", flagName, want, got)
+		}
+	}
+}
@@ -0,0 +1,78 @@
+// resolve.go
+// Copyright (c) 2025 mattmc3
+// SPDX-License-Identifier: MIT
+// Project home: https://github.com/mattmc3/fluentflag
+
+package fluentflag
+
+import "fmt"
+
+// Source is a place a flag's value can come from.
+type Source string
+
+const (
+	SourceFlag    Source = "flag"    // the command line
+	SourceEnv     Source = "env"     // an environment variable, see Env and EnvPrefix
+	SourceConfig  Source = "config"  // a config file, see ConfigFile
+	SourceDefault Source = "default" // the flag's default value
+)
+
+// defaultSourceOrder is the order Resolve applies sources in by default.
+var defaultSourceOrder = []Source{SourceEnv, SourceConfig}
+
+// SetSourceOrder sets which sources Resolve consults for flags not given on
+// the command line, highest precedence first. The default order is SourceEnv,
+// then SourceConfig. The command line always takes precedence, and defaults
+// always come last, so only SourceEnv and SourceConfig may be listed. A source
+// left out is not consulted.
+func (b *FlagBuilder) SetSourceOrder(sources ...Source) {
+	for _, src := range sources {
+		if src != SourceEnv && src != SourceConfig {
+			b.fail(fmt.Errorf("fluentflag: SetSourceOrder can't order source %q", src))
+			return
+		}
+	}
+	b.sourceOrder = append([]Source{}, sources...)
+}
+
+// Resolve fills in flags not set on the command line from the other sources,
+// in precedence order: the command line, environment variables, config files,
+// and finally defaults. Parse calls it after parsing; call it yourself after
+// parsing the flag set directly, before Validate.
+func (b *FlagBuilder) Resolve() error {
+	order := b.sourceOrder
+	if order == nil {
+		order = defaultSourceOrder
+	}
+	b.sources = map[string]Source{}
+	for _, src := range order {
+		var err error
+		switch src {
+		case SourceEnv:
+			err = b.applyEnv()
+		case SourceConfig:
+			err = b.applyConfig()
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Source returns where the final value of the flag with the given name or
+// alias came from: SourceFlag, SourceEnv, SourceConfig, or SourceDefault if
+// it wasn't set. It returns "" for unknown flags.
+func (b *FlagBuilder) Source(name string) Source {
+	f := b.lookup(name)
+	switch {
+	case f == nil:
+		return ""
+	case b.sources[f.names()[0]] != "":
+		return b.sources[f.names()[0]]
+	case b.isSet(f):
+		return SourceFlag
+	default:
+		return SourceDefault
+	}
+}
@@ -0,0 +1,77 @@
+//go:build go1.18
+
+package fluentflag
+
+import (
+	"flag"
+	"testing"
+)
+
+func TestResolve(t *testing.T) {
+	tests := []struct {
+		name  string
+		order []Source
+		args  []string
+		env   string
+		want  string
+	}{
+		{"flag wins", nil, []string{"--host=flag"}, "env", "flag"},
+		{"env over config", nil, []string{}, "env", "env"},
+		{"config without env", nil, []string{}, "", "config"},
+		{"config over env", []Source{SourceConfig, SourceEnv}, []string{}, "env", "config"},
+		{"env disabled", []Source{SourceConfig}, []string{}, "env", "config"},
+		{"no sources", []Source{}, []string{}, "env", "default"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.env != "" {
+				t.Setenv("APP_HOST", tt.env)
+			}
+			fs := flag.NewFlagSet("test", flag.ContinueOnError)
+			b := NewFlagBuilderWithSet(fs)
+			host := b.StringFlag("host", "listen host").Default("default").Env("APP_HOST").BuildVar()
+			if err := b.ConfigFile(writeConfig(t, "config.json", `{"host": "config"}`)); err != nil {
+				t.Fatal(err)
+			}
+			if tt.order != nil {
+				b.SetSourceOrder(tt.order...)
+			}
+			if err := fs.Parse(tt.args); err != nil {
+				t.Fatal(err)
+			}
+			if err := b.Resolve(); err != nil {
+				t.Fatal(err)
+			}
+			if *host != tt.want {
+				t.Errorf("expected %q, got %q", tt.want, *host)
+			}
+			if src := b.Source("host"); src != Source(tt.want) {
+				t.Errorf("expected source %q, got %q", tt.want, src)
+			}
+		})
+	}
+}
+
+func TestSource_Unknown(t *testing.T) {
+	b := NewFlagBuilderWithSet(flag.NewFlagSet("test", flag.ContinueOnError))
+	b.StringFlag("host", "listen host").Alias('H').BuildVar()
+	if _, err := b.Parse([]string{"-H", "example.com"}); err != nil {
+		t.Fatal(err)
+	}
+	if src := b.Source("H"); src != SourceFlag {
+		t.Errorf("expected source %q by alias, got %q", SourceFlag, src)
+	}
+	if src := b.Source("port"); src != "" {
+		t.Errorf("expected no source for an unknown flag, got %q", src)
+	}
+}
+
+func TestSetSourceOrder_InvalidPanics(t *testing.T) {
+	defer func() {
+		if r := recover(); r == nil {
+			t.Error("expected panic for ordering the command line")
+		}
+	}()
+	b := NewFlagBuilderWithSet(flag.NewFlagSet("test", flag.ContinueOnError))
+	b.SetSourceOrder(SourceConfig, SourceFlag)
+}
@@ -0,0 +1,90 @@
+// schema.go
+// Copyright (c) 2025 mattmc3
+// SPDX-License-Identifier: MIT
+// Project home: https://github.com/mattmc3/fluentflag
+
+package fluentflag
+
+import (
+	"encoding/json"
+	"io"
+)
+
+// Schema is a serializable description of a command's flags and subcommands,
+// for tools that introspect the command line, such as GUIs, documentation
+// pipelines, and wrappers.
+type Schema struct {
+	Name     string       `json:"name"`
+	Usage    string       `json:"usage,omitempty"`
+	Flags    []SchemaFlag `json:"flags"`
+	Commands []Schema     `json:"commands,omitempty"`
+}
+
+// SchemaFlag describes one flag in a Schema. Unlike usage, it includes hidden
+// flags, and its default is the flag's actual default value, which is empty
+// for slice flags.
+type SchemaFlag struct {
+	Name       string   `json:"name"`
+	Aliases    []string `json:"aliases,omitempty"`
+	Type       string   `json:"type"`
+	Default    string   `json:"default"`
+	Usage      string   `json:"usage"`
+	Required   bool     `json:"required,omitempty"`
+	Choices    []string `json:"choices,omitempty"`
+	Group      string   `json:"group,omitempty"`
+	Hidden     bool     `json:"hidden,omitempty"`
+	Deprecated string   `json:"deprecated,omitempty"`
+	Env        string   `json:"env,omitempty"`
+}
+
+// defaultValue returns the flag's default value formatted as a string, or ""
+// for slice and map flags, which always start empty.
+func (self *FluentFlag[T]) defaultValue() string {
+	if self.isList() {
+		return ""
+	}
+	return self.format(self.defaultVal)
+}
+
+// schemaFlag describes the flag in a Schema, from its usage description.
+func schemaFlag(u UsageFlag) SchemaFlag {
+	var aliases []string
+	if len(u.Aliases) > 0 {
+		aliases = u.Aliases
+	}
+	return SchemaFlag{
+		Name:       u.Name,
+		Aliases:    aliases,
+		Type:       u.GoType,
+		Default:    u.DefaultValue,
+		Usage:      u.Usage,
+		Required:   u.Required,
+		Choices:    u.Choices,
+		Group:      u.Group,
+		Hidden:     u.Hidden,
+		Deprecated: u.Deprecated,
+		Env:        u.Env,
+	}
+}
+
+// Schema returns a description of every built flag, including hidden ones, in
+// definition order.
+func (b *FlagBuilder) Schema() Schema {
+	s := Schema{Name: b.flagSet.Name(), Flags: []SchemaFlag{}}
+	for _, f := range b.flagsBuilt {
+		s.Flags = append(s.Flags, schemaFlag(f.usageFlag(0)))
+	}
+	for _, c := range b.commands {
+		sub := c.Schema()
+		sub.Name, sub.Usage = c.name, c.usage
+		s.Commands = append(s.Commands, sub)
+	}
+	return s
+}
+
+// WriteSchema writes the flag schema from Schema to w as indented JSON.
+func (b *FlagBuilder) WriteSchema(w io.Writer) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(b.Schema())
+}
@@ -0,0 +1,95 @@
+//go:build go1.18
+
+package fluentflag
+
+import (
+	"encoding/json"
+	"flag"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestSchema(t *testing.T) {
+	app := NewAppWithSet(flag.NewFlagSet("tool", flag.ContinueOnError))
+	app.StringFlag("format", "Output format").Alias('f').Default("text").Choices("text", "json").Group("Output").BuildVar()
+	app.IntFlag("level", "Levels").BuildSlice()
+	app.BoolFlag("debug", "Debug internals").Hidden().BuildVar()
+	app.StringFlag("token", "API token").Required().Env("TOOL_TOKEN").Deprecated("use --auth").BuildVar()
+	sub := app.Command("serve", "Run the server")
+	sub.IntFlag("port", "Port to listen on").Default(8080).BuildVar()
+
+	expected := Schema{
+		Name: "tool",
+		Flags: []SchemaFlag{
+			{Name: "format", Aliases: []string{"f"}, Type: "string", Default: "text", Usage: "Output format",
+				Choices: []string{"text", "json"}, Group: "Output"},
+			{Name: "level", Type: "[]int", Default: "", Usage: "Levels"},
+			{Name: "debug", Type: "bool", Default: "false", Usage: "Debug internals", Hidden: true},
+			{Name: "token", Type: "string", Default: "", Usage: "API token", Required: true,
+				Deprecated: "use --auth", Env: "TOOL_TOKEN"},
+		},
+		Commands: []Schema{{
+			Name:  "serve",
+			Usage: "Run the server",
+			Flags: []SchemaFlag{{Name: "port", Type: "int", Default: "8080", Usage: "Port to listen on"}},
+		}},
+	}
+	if actual := app.Schema(); !reflect.DeepEqual(actual, expected) {
+		t.Errorf("Schema mismatch.\nGot:  %+v\nWant: %+v", actual, expected)
+	}
+}
+
+func TestWriteSchema(t *testing.T) {
+	b := NewFlagBuilderWithSet(flag.NewFlagSet("test", flag.ContinueOnError))
+	b.StringFlag("name", "Command name").Alias('n').Default("x").BuildVar()
+
+	var buf strings.Builder
+	if err := b.WriteSchema(&buf); err != nil {
+		t.Fatalf("WriteSchema failed: %v", err)
+	}
+	var actual Schema
+	if err := json.Unmarshal([]byte(buf.String()), &actual); err != nil {
+		t.Fatalf("invalid JSON: %v\n%s", err, buf.String())
+	}
+	if !reflect.DeepEqual(actual, b.Schema()) {
+		t.Errorf("round trip mismatch.\nGot:  %+v\nWant: %+v", actual, b.Schema())
+	}
+	if !strings.Contains(buf.String(), `"aliases": [`) || strings.Contains(buf.String(), `"hidden"`) {
+		t.Errorf("unexpected JSON fields:\n%s", buf.String())
+	}
+}
+
+func TestSchema_Empty(t *testing.T) {
+	b := NewFlagBuilderWithSet(flag.NewFlagSet("test", flag.ContinueOnError))
+	var buf strings.Builder
+	if err := b.WriteSchema(&buf); err != nil {
+		t.Fatalf("WriteSchema failed: %v", err)
+	}
+	if expected := "{\n  \"name\": \"test\",\n  \"flags\": []\n}\n"; buf.String() != expected {
+		t.Errorf("got %q, want %q", buf.String(), expected)
+	}
+}
+
+func TestSchema_MatchesUsageData(t *testing.T) {
+	b := NewFlagBuilderWithSet(flag.NewFlagSet("test", flag.ContinueOnError))
+	b.StringFlag("format", "Output format").Alias('f').Default("text").Choices("text", "json").BuildVar()
+	b.IntFlag("level", "Levels").Default(3).BuildSlice()
+	b.IntFlag("workers", "Workers").HideDefault().Default(4).BuildVar()
+
+	data := b.UsageData()
+	schema := b.Schema()
+	for i, u := range data.Flags {
+		s := schema.Flags[i]
+		f, _ := b.Lookup(u.Name)
+		if s.Name != u.Name || s.Type != u.GoType || s.Default != u.DefaultValue || !reflect.DeepEqual(s.Choices, u.Choices) {
+			t.Errorf("schema flag %+v disagrees with usage flag %+v", s, u)
+		}
+		if f.Type() != u.GoType || f.Default() != u.DefaultValue || !reflect.DeepEqual(f.Aliases(), u.Aliases) {
+			t.Errorf("Lookup(%q) disagrees with usage flag %+v", u.Name, u)
+		}
+	}
+	if d := data.Flags[2]; d.Default != "" || d.DefaultValue != "4" {
+		t.Errorf("expected hidden usage default and actual default 4, got %q and %q", d.Default, d.DefaultValue)
+	}
+}
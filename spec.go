@@ -0,0 +1,358 @@
+// spec.go
+// Copyright (c) 2025 mattmc3
+// SPDX-License-Identifier: MIT
+// Project home: https://github.com/mattmc3/fluentflag
+
+package fluentflag
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"time"
+)
+
+// specFlagKeys are the keys a flag may have in a spec.
+var specFlagKeys = map[string]bool{
+	"name": true, "type": true, "usage": true, "default": true, "alias": true,
+	"aliases": true, "env": true, "required": true, "choices": true, "group": true,
+	"hidden": true, "deprecated": true, "placeholder": true, "list": true,
+}
+
+// specBuilders build a flag from a spec for each type name a spec may use.
+var specBuilders = map[string]func(b *FlagBuilder, spec map[string]any) error{
+	"bool":     buildSpecFlag[bool],
+	"string":   buildSpecFlag[string],
+	"int":      buildSpecFlag[int],
+	"int64":    buildSpecFlag[int64],
+	"uint":     buildSpecFlag[uint],
+	"uint64":   buildSpecFlag[uint64],
+	"float64":  buildSpecFlag[float64],
+	"duration": buildSpecFlag[time.Duration],
+	"time":     buildSpecTime,
+}
+
+// FromSpec builds a FlagBuilder from a declarative flag specification in JSON
+// or YAML, so flag definitions can be shared with tools in other languages:
+//
+//	name: mytool
+//	usage: Process input files
+//	flags:
+//	  output:
+//	    type: string
+//	    alias: o
+//	    default: out.txt
+//	    usage: Write results to a file
+//	    env: MYTOOL_OUTPUT
+//
+// Each flag may set type (bool, string, int, int64, uint, uint64, float64,
+// duration, or time; string if unset), usage, default, alias, aliases, env,
+// required, choices, group, hidden, deprecated, placeholder, and list, which
+// builds a slice flag. Flags given as a YAML mapping are defined in the order
+// they are written. JSON objects have no order, so flags given as a JSON
+// mapping are defined in name order; give them as a list of objects with a
+// name instead to choose their order.
+// The spec's usage and footer set UsageHeader and UsageFooter.
+//
+// The flag set is named after the spec, or else the program, and exits on
+// parse errors like flag.CommandLine. Read parsed values with typed accessors
+// like GetString and GetInt, which panic on an unknown name, or with Get.
+func FromSpec(r io.Reader) (*FlagBuilder, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	decode, order := decodeYAML, yamlKeyOrder(data, "flags")
+	if trimmed := bytes.TrimSpace(data); len(trimmed) > 0 && trimmed[0] == '{' {
+		decode, order = decodeJSON, nil
+	}
+	var spec map[string]any
+	if err := decode(data, &spec); err != nil {
+		return nil, fmt.Errorf("fluentflag: spec: %w", err)
+	}
+
+	name := filepath.Base(os.Args[0])
+	var header, footer string
+	var flags []map[string]any
+	for key, val := range spec {
+		switch key {
+		case "name":
+			name = configString(val)
+		case "usage":
+			header = configString(val)
+		case "footer":
+			footer = configString(val)
+		case "flags":
+			if flags, err = specFlags(val, order); err != nil {
+				return nil, err
+			}
+		default:
+			return nil, fmt.Errorf("fluentflag: spec: unknown key %q", key)
+		}
+	}
+
+	b := NewFlagBuilderWithSet(flag.NewFlagSet(name, flag.ExitOnError))
+	b.UsageHeader(header)
+	b.UsageFooter(footer)
+	b.SetErrorHandling(ReturnErrors)
+	for _, f := range flags {
+		typ := "string"
+		if val, ok := f["type"]; ok {
+			typ = configString(val)
+		}
+		build, ok := specBuilders[typ]
+		if !ok {
+			return nil, fmt.Errorf("fluentflag: spec: flag --%s has unknown type %q", configString(f["name"]), typ)
+		}
+		if err := build(b, f); err != nil {
+			return nil, err
+		}
+	}
+	if err := b.Err(); err != nil {
+		return nil, err
+	}
+	b.SetErrorHandling(PanicOnError)
+	return b, nil
+}
+
+// specFlags returns the flags of a spec, given either as a mapping of names to
+// flags or as a list of flags with names. Mapped flags follow the order of
+// their names in order, and any not in it follow in name order.
+func specFlags(val any, order []string) ([]map[string]any, error) {
+	var flags []map[string]any
+	switch v := val.(type) {
+	case map[string]any:
+		names := make([]string, 0, len(v))
+		for name := range v {
+			names = append(names, name)
+		}
+		rank := map[string]int{}
+		for _, name := range names {
+			rank[name] = len(order)
+		}
+		for i, name := range order {
+			rank[name] = i
+		}
+		sort.Slice(names, func(i, j int) bool {
+			if ri, rj := rank[names[i]], rank[names[j]]; ri != rj {
+				return ri < rj
+			}
+			return names[i] < names[j]
+		})
+		for _, name := range names {
+			f, ok := v[name].(map[string]any)
+			if v[name] == nil {
+				f, ok = map[string]any{}, true
+			}
+			if !ok {
+				return nil, fmt.Errorf("fluentflag: spec: flag --%s must be a mapping", name)
+			}
+			if _, given := f["name"]; given {
+				return nil, fmt.Errorf("fluentflag: spec: flag --%s is named by its key", name)
+			}
+			f["name"] = name
+			flags = append(flags, f)
+		}
+	case []any:
+		for i, item := range v {
+			f, ok := item.(map[string]any)
+			if !ok || configString(f["name"]) == "" {
+				return nil, fmt.Errorf("fluentflag: spec: flag %d must be an object with a name", i+1)
+			}
+			flags = append(flags, f)
+		}
+	default:
+		return nil, fmt.Errorf("fluentflag: spec: flags must be a mapping or a list")
+	}
+	for _, f := range flags {
+		for key := range f {
+			if !specFlagKeys[key] {
+				return nil, fmt.Errorf("fluentflag: spec: flag --%s has unknown key %q", configString(f["name"]), key)
+			}
+		}
+	}
+	return flags, nil
+}
+
+// buildSpecFlag defines and builds a flag of type T from its spec.
+func buildSpecFlag[T FlagType](b *FlagBuilder, spec map[string]any) error {
+	f, list, err := specFlag[T](b, spec)
+	if err != nil {
+		return err
+	}
+	if list {
+		return f.register(&accumValues[T]{flag: f, target: &[]T{}})
+	}
+	return f.TryBuild(new(T))
+}
+
+// buildSpecTime defines and builds a time flag, parsed as RFC 3339, from its
+// spec.
+func buildSpecTime(b *FlagBuilder, spec map[string]any) error {
+	f, list, err := specFlag[string](b, spec)
+	if err != nil {
+		return err
+	}
+	f.layout = time.RFC3339
+	if list {
+		return f.tryBuildTimes(&[]time.Time{})
+	}
+	return f.tryBuildTime(new(time.Time))
+}
+
+// specFlag defines a flag of type T from its spec, and reports whether the
+// spec makes it a list.
+func specFlag[T FlagType](b *FlagBuilder, spec map[string]any) (*FluentFlag[T], bool, error) {
+	name := configString(spec["name"])
+	str := func(key string) string {
+		if val, ok := spec[key]; ok && val != nil {
+			return configString(val)
+		}
+		return ""
+	}
+	var switches [3]bool
+	for i, key := range []string{"required", "hidden", "list"} {
+		if s := str(key); s != "" {
+			on, err := strconv.ParseBool(s)
+			if err != nil {
+				return nil, false, fmt.Errorf("fluentflag: spec: flag --%s: %s: %w", name, key, err)
+			}
+			switches[i] = on
+		}
+	}
+	required, hidden, list := switches[0], switches[1], switches[2]
+	var def T
+	if s := str("default"); s != "" {
+		v, err := parse[T](s)
+		if err != nil {
+			return nil, false, fmt.Errorf("fluentflag: spec: flag --%s: default: %w", name, err)
+		}
+		def = v
+	}
+	var choices []T
+	if val, ok := spec["choices"]; ok && val != nil {
+		for _, s := range configStrings(val) {
+			v, err := parse[T](s)
+			if err != nil {
+				return nil, false, fmt.Errorf("fluentflag: spec: flag --%s: choices: %w", name, err)
+			}
+			choices = append(choices, v)
+		}
+	}
+	var aliases []string
+	if s := str("alias"); s != "" {
+		aliases = append(aliases, s)
+	}
+	if val, ok := spec["aliases"]; ok && val != nil {
+		aliases = append(aliases, configStrings(val)...)
+	}
+
+	f := newFlag[T](b, name, str("usage")).Default(def)
+	f.addAliases(aliases...)
+	if len(choices) > 0 {
+		f.Choices(choices...)
+	}
+	if s := str("env"); s != "" {
+		f.Env(s)
+	}
+	if s := str("group"); s != "" {
+		f.Group(s)
+	}
+	if s := str("deprecated"); s != "" {
+		f.Deprecated(s)
+	}
+	if s := str("placeholder"); s != "" {
+		f.Placeholder(s)
+	}
+	if required {
+		f.Required()
+	}
+	if hidden {
+		f.Hidden()
+	}
+	return f, list, nil
+}
+
+// specValue returns the value of the flag with the given name, which must hold
+// a single T. A typo in the name or a mismatched type is a bug in the program
+// rather than a bad command line, so it panics instead of reading as the zero
+// value.
+func specValue[T any](b *FlagBuilder, name string) T {
+	v, err := Get[T](b, name)
+	if err != nil {
+		panic(err.Error())
+	}
+	return v
+}
+
+// GetString returns the value of a string flag by name. It panics if there is
+// no such flag or it isn't a string flag.
+func (b *FlagBuilder) GetString(name string) string {
+	return specValue[string](b, name)
+}
+
+// GetBool returns the value of a bool flag by name. It panics if there is no
+// such flag or it isn't a bool flag.
+func (b *FlagBuilder) GetBool(name string) bool {
+	return specValue[bool](b, name)
+}
+
+// GetInt returns the value of an int flag by name. It panics if there is no
+// such flag or it isn't an int flag.
+func (b *FlagBuilder) GetInt(name string) int {
+	return specValue[int](b, name)
+}
+
+// GetInt64 returns the value of an int64 flag by name. It panics if there is
+// no such flag or it isn't an int64 flag.
+func (b *FlagBuilder) GetInt64(name string) int64 {
+	return specValue[int64](b, name)
+}
+
+// GetUint returns the value of a uint flag by name. It panics if there is no
+// such flag or it isn't a uint flag.
+func (b *FlagBuilder) GetUint(name string) uint {
+	return specValue[uint](b, name)
+}
+
+// GetUint64 returns the value of a uint64 flag by name. It panics if there is
+// no such flag or it isn't a uint64 flag.
+func (b *FlagBuilder) GetUint64(name string) uint64 {
+	return specValue[uint64](b, name)
+}
+
+// GetFloat64 returns the value of a float64 flag by name. It panics if there
+// is no such flag or it isn't a float64 flag.
+func (b *FlagBuilder) GetFloat64(name string) float64 {
+	return specValue[float64](b, name)
+}
+
+// GetDuration returns the value of a duration flag by name. It panics if
+// there is no such flag or it isn't a duration flag.
+func (b *FlagBuilder) GetDuration(name string) time.Duration {
+	return specValue[time.Duration](b, name)
+}
+
+// GetTime returns the value of a time flag by name. It panics if there is no
+// such flag or it isn't a time flag.
+func (b *FlagBuilder) GetTime(name string) time.Time {
+	return specValue[time.Time](b, name)
+}
+
+// GetStrings returns the values of a slice flag by name, formatted as strings.
+// It panics if there is no such flag or it isn't a slice flag.
+func (b *FlagBuilder) GetStrings(name string) []string {
+	f := b.lookup(name)
+	if f == nil {
+		panic(fmt.Sprintf("fluentflag: no flag --%s", name))
+	}
+	if !f.isList() {
+		panic(fmt.Sprintf("fluentflag: --%s is not a slice flag", f.names()[0]))
+	}
+	return f.boundValue().list()
+}
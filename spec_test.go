@@ -0,0 +1,175 @@
+//go:build go1.18
+
+package fluentflag
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestFromSpec_YAML(t *testing.T) {
+	spec := `name: mytool
+usage: Process input files
+flags:
+  output:
+    type: string
+    alias: o
+    default: out.txt
+    usage: Write results to a file
+    env: MYTOOL_OUTPUT
+  workers:
+    type: int
+    default: 4
+    usage: Number of workers
+    choices: [1, 2, 4, 8]
+  timeout:
+    type: duration
+    default: 30s
+    usage: Request timeout
+  tag:
+    usage: Tags to apply
+    list: true
+  verbose:
+    type: bool
+    alias: v
+    usage: Print more
+`
+	b, err := FromSpec(strings.NewReader(spec))
+	if err != nil {
+		t.Fatalf("FromSpec returned error: %v", err)
+	}
+	if _, err := b.Parse([]string{"-v", "--workers=8", "--tag", "a", "--tag", "b"}); err != nil {
+		t.Fatalf("Parse returned error: %v", err)
+	}
+	if got := b.GetString("output"); got != "out.txt" {
+		t.Errorf("output = %q, want %q", got, "out.txt")
+	}
+	if got := b.GetInt("workers"); got != 8 {
+		t.Errorf("workers = %d, want 8", got)
+	}
+	if got := b.GetDuration("timeout"); got != 30*time.Second {
+		t.Errorf("timeout = %v, want 30s", got)
+	}
+	if got := b.GetBool("verbose"); !got {
+		t.Error("verbose = false, want true")
+	}
+	if got := b.GetStrings("tag"); !reflect.DeepEqual(got, []string{"a", "b"}) {
+		t.Errorf("tag = %v, want [a b]", got)
+	}
+
+	usage := b.UsageStringWidth(0)
+	for _, want := range []string{"Process input files", "-o, --output string", "--workers int"} {
+		if !strings.Contains(usage, want) {
+			t.Errorf("usage is missing %q:\n%s", want, usage)
+		}
+	}
+	var names []string
+	for _, f := range b.Schema().Flags {
+		names = append(names, f.Name)
+	}
+	if want := []string{"output", "workers", "timeout", "tag", "verbose"}; !reflect.DeepEqual(names, want) {
+		t.Errorf("flag order = %v, want the written order %v", names, want)
+	}
+}
+
+func TestFromSpec_JSONMappingInNameOrder(t *testing.T) {
+	b, err := FromSpec(strings.NewReader(`{"flags": {"zone": {}, "after": {}, "ratio": {}}}`))
+	if err != nil {
+		t.Fatalf("FromSpec returned error: %v", err)
+	}
+	var names []string
+	for _, f := range b.Schema().Flags {
+		names = append(names, f.Name)
+	}
+	if want := []string{"after", "ratio", "zone"}; !reflect.DeepEqual(names, want) {
+		t.Errorf("flag order = %v, want %v", names, want)
+	}
+}
+
+func TestFromSpec_GetPanics(t *testing.T) {
+	b, err := FromSpec(strings.NewReader("flags:\n  output:\n    usage: Output\n"))
+	if err != nil {
+		t.Fatalf("FromSpec returned error: %v", err)
+	}
+	tests := []struct {
+		name string
+		get  func()
+		want string
+	}{
+		{"unknown name", func() { b.GetString("ouptut") }, "fluentflag: no flag --ouptut"},
+		{"wrong type", func() { b.GetInt("output") }, "fluentflag: --output is string, not int"},
+		{"unknown slice", func() { b.GetStrings("tags") }, "fluentflag: no flag --tags"},
+		{"not a slice", func() { b.GetStrings("output") }, "fluentflag: --output is not a slice flag"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			defer func() {
+				if r := recover(); r != tt.want {
+					t.Errorf("expected panic %q, got %v", tt.want, r)
+				}
+			}()
+			tt.get()
+		})
+	}
+}
+
+func TestFromSpec_JSONKeepsOrder(t *testing.T) {
+	spec := `{
+  "name": "mytool",
+  "flags": [
+    {"name": "zone", "usage": "Zone", "required": true},
+    {"name": "after", "type": "time", "usage": "Start time"},
+    {"name": "ratio", "type": "float64", "default": 0.5, "usage": "Ratio", "hidden": true}
+  ]
+}`
+	b, err := FromSpec(strings.NewReader(spec))
+	if err != nil {
+		t.Fatalf("FromSpec returned error: %v", err)
+	}
+	var names []string
+	for _, f := range b.Schema().Flags {
+		names = append(names, f.Name)
+	}
+	if !reflect.DeepEqual(names, []string{"zone", "after", "ratio"}) {
+		t.Errorf("flag order = %v, want [zone after ratio]", names)
+	}
+	if _, err := b.Parse([]string{"--zone=us", "--after=2025-01-02T03:04:05Z"}); err != nil {
+		t.Fatalf("Parse returned error: %v", err)
+	}
+	if got := b.GetFloat64("ratio"); got != 0.5 {
+		t.Errorf("ratio = %v, want 0.5", got)
+	}
+	if got := b.GetTime("after"); !got.Equal(time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)) {
+		t.Errorf("after = %v", got)
+	}
+	if s := b.Schema().Flags; !s[0].Required || !s[2].Hidden {
+		t.Errorf("required and hidden not applied: %+v", s)
+	}
+}
+
+func TestFromSpec_Errors(t *testing.T) {
+	tests := []struct {
+		name string
+		spec string
+		err  string
+	}{
+		{"unknown key", "colour: red\n", `fluentflag: spec: unknown key "colour"`},
+		{"unknown flag key", "flags:\n  name:\n    usgae: Name\n", `fluentflag: spec: flag --name has unknown key "usgae"`},
+		{"unknown type", "flags:\n  name:\n    type: complex\n", `fluentflag: spec: flag --name has unknown type "complex"`},
+		{"bad default", "flags:\n  n:\n    type: int\n    default: many\n", `fluentflag: spec: flag --n: default: strconv.ParseInt: parsing "many": invalid syntax`},
+		{"bad switch", "flags:\n  n:\n    list: maybe\n", `fluentflag: spec: flag --n: list: strconv.ParseBool: parsing "maybe": invalid syntax`},
+		{"not a mapping", "flags:\n  - name\n", "fluentflag: spec: flag 1 must be an object with a name"},
+		{"name collision", "flags:\n  name:\n    alias: v\n  verbose:\n    alias: v\n", "fluentflag: -v for --verbose is already defined by --name"},
+		{"bad yaml", "flags:\n\t- x\n", "fluentflag: spec: line 2: tabs can't be used for indentation"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := FromSpec(strings.NewReader(tt.spec))
+			if err == nil || err.Error() != tt.err {
+				t.Errorf("got error %v, want %q", err, tt.err)
+			}
+		})
+	}
+}
@@ -0,0 +1,174 @@
+// template.go
+// Copyright (c) 2025 mattmc3
+// SPDX-License-Identifier: MIT
+// Project home: https://github.com/mattmc3/fluentflag
+
+package fluentflag
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"text/template"
+)
+
+// UsageData is the data a usage template is executed with, and that
+// documentation generators work from.
+type UsageData struct {
+	Name      string          // name of the flag set
+	Synopsis  string          // how the command is invoked, like "tool [flags] <file>"
+	Header    string          // text set with UsageHeader
+	Footer    string          // text set with UsageFooter
+	Flags     []UsageFlag     // visible flags, in definition order or sorted by SortFlags
+	Groups    []UsageGroup    // sections with visible flags, in declaration order
+	Arguments []UsageArgument // positional arguments, in order
+	Commands  []UsageCommand  // subcommands, in declaration order
+	Examples  []UsageExample  // example command lines, in the order added
+}
+
+// UsageFlag describes a flag to a usage template. It is also the description
+// that ListFlags, Schema, and Lookup are built from, so they all agree.
+type UsageFlag struct {
+	Name         string   // long name, like "output"
+	Aliases      []string // short and long aliases, like "o"
+	Type         string   // value type as shown in usage, like "string"; empty for bool flags
+	GoType       string   // Go type of the value, like "int" or "[]string"
+	TakesValue   bool     // whether the flag takes a value, unlike a bool flag
+	Default      string   // default as shown in usage, or empty if it isn't shown
+	DefaultValue string   // actual default value, or empty for slice and map flags
+	Usage        string   // usage text
+	Choices      []string // allowed values, if restricted
+	Group        string   // title of the flag's section, or empty if ungrouped
+	Deprecated   string   // deprecation message, if the flag is deprecated
+	Env          string   // environment variable the flag is read from, if any
+	Required     bool     // whether the flag must be set
+	Hidden       bool     // whether the flag is left out of usage
+	RenamedFrom  []string // former names still accepted
+	Line         string   // the flag's line in the default usage text
+}
+
+// UsageGroup describes a usage section to a usage template.
+type UsageGroup struct {
+	Title       string      // section title
+	Description string      // paragraph printed under the title, if any
+	Flags       []UsageFlag // visible flags in the section
+}
+
+// UsageArgument describes a positional argument to a usage template.
+type UsageArgument struct {
+	Name     string // argument name, like "file"
+	Usage    string // usage text
+	Default  string // default value, if any
+	Required bool   // whether the argument must be given
+	Variadic bool   // whether the argument takes all remaining arguments
+}
+
+// UsageCommand describes a subcommand to a usage template.
+type UsageCommand struct {
+	Name  string // command name
+	Usage string // usage text
+}
+
+// UsageExample describes an example command line to a usage template.
+type UsageExample struct {
+	Command     string // the command line
+	Description string // what it does, if given
+}
+
+// usageFuncs are the functions available to usage templates.
+var usageFuncs = template.FuncMap{
+	"join":  strings.Join,
+	"lower": strings.ToLower,
+	"upper": strings.ToUpper,
+}
+
+// SetUsageTemplate replaces the usage text with the output of a text/template
+// executed with a UsageData. Templates may also call join, lower, and upper.
+//
+//	b.SetUsageTemplate(`{{range .Flags}}--{{.Name}}{{if .Type}}=<{{.Type}}>{{end}}  {{.Usage}}
+//	{{end}}`)
+func (b *FlagBuilder) SetUsageTemplate(text string) {
+	tmpl, err := template.New("usage").Funcs(usageFuncs).Parse(text)
+	if err != nil {
+		b.fail(fmt.Errorf("fluentflag: usage template: %w", err))
+		return
+	}
+	b.usageTemplate = tmpl
+}
+
+// usageFlag describes the flag to a usage template, with its default usage
+// line wrapped to width columns.
+func (self *FluentFlag[T]) usageFlag(width int) UsageFlag {
+	return UsageFlag{
+		Name:         self.name,
+		Aliases:      self.names()[1:],
+		Type:         strings.TrimSpace(self.typeString()),
+		GoType:       self.goType(),
+		TakesValue:   self.takesValue(),
+		Default:      self.defaultString(),
+		DefaultValue: self.defaultValue(),
+		Usage:        self.usage,
+		Choices:      self.choiceStrings(),
+		Group:        self.group,
+		Deprecated:   self.deprecated,
+		Env:          self.envVar(),
+		Required:     self.required,
+		Hidden:       self.hidden,
+		RenamedFrom:  self.renamedFrom,
+		Line:         self.usageLine(width),
+	}
+}
+
+// UsageData returns the data usage templates are executed with, for
+// generating documentation from the flag definitions.
+func (b *FlagBuilder) UsageData() UsageData {
+	return b.usageData(0)
+}
+
+// usageData returns the data usage templates are executed with, with flags'
+// usage lines wrapped to width columns.
+func (b *FlagBuilder) usageData(width int) UsageData {
+	data := UsageData{
+		Name:     b.flagSet.Name(),
+		Synopsis: b.usageSynopsis(),
+		Header:   b.usageHeader,
+		Footer:   b.usageFooter,
+	}
+	for _, f := range b.sortedFlags(b.visibleFlags()) {
+		data.Flags = append(data.Flags, f.usageFlag(width))
+	}
+	for _, g := range b.groups {
+		group := UsageGroup{Title: g.title, Description: g.desc}
+		for _, f := range data.Flags {
+			if f.Group == g.title {
+				group.Flags = append(group.Flags, f)
+			}
+		}
+		if len(group.Flags) > 0 {
+			data.Groups = append(data.Groups, group)
+		}
+	}
+	for _, p := range b.positionals {
+		data.Arguments = append(data.Arguments, UsageArgument{
+			Name:     p.name,
+			Usage:    p.usage,
+			Default:  p.defaultVal,
+			Required: p.required,
+			Variadic: p.variadic,
+		})
+	}
+	for _, c := range b.commands {
+		data.Commands = append(data.Commands, UsageCommand{Name: c.name, Usage: c.usage})
+	}
+	for _, ex := range b.examples {
+		data.Examples = append(data.Examples, UsageExample{Command: ex.command, Description: ex.desc})
+	}
+	return data
+}
+
+// writeTemplateUsage writes the usage text from the usage template to w.
+func (b *FlagBuilder) writeTemplateUsage(w io.Writer, width int) {
+	if err := b.usageTemplate.Execute(w, b.usageData(width)); err != nil {
+		fmt.Fprintf(w, "fluentflag: usage template: %v\n", err)
+	}
+}
@@ -0,0 +1,50 @@
+//go:build go1.18
+
+package fluentflag
+
+import (
+	"flag"
+	"strings"
+	"testing"
+)
+
+func TestSetUsageTemplate(t *testing.T) {
+	b := NewFlagBuilderWithSet(flag.NewFlagSet("tool", flag.ContinueOnError))
+	b.StringFlag("output", "Output file").Alias('o').Default("out.txt").Env("TOOL_OUTPUT").BuildVar()
+	b.BoolFlag("verbose", "Print more").Group("Logging").Deprecated("use --log-level").BuildVar()
+	b.StringFlag("secret", "Internal").Hidden().BuildVar()
+	b.SetUsageTemplate(`Usage of {{.Name}}:
+{{range .Flags}}{{.Name}}{{if .Aliases}} ({{join .Aliases ", "}}){{end}}{{if .Type}} <{{upper .Type}}>{{end}}: {{.Usage}}
+{{- if .Default}} [default {{.Default}}]{{end}}
+{{- if .Env}} [${{.Env}}]{{end}}
+{{- if .Deprecated}} [deprecated]{{end}}
+{{end}}{{range .Groups}}{{.Title}}: {{len .Flags}}
+{{end}}`)
+
+	expected := `Usage of tool:
+output (o) <STRING>: Output file [default "out.txt"] [$TOOL_OUTPUT]
+verbose: Print more [deprecated]
+Logging: 1
+`
+	if got := b.UsageStringWidth(0); got != expected {
+		t.Errorf("Usage output mismatch.\nGot:\n%s\nWant:\n%s", got, expected)
+	}
+}
+
+func TestSetUsageTemplate_Line(t *testing.T) {
+	b := NewFlagBuilderWithSet(flag.NewFlagSet("tool", flag.ContinueOnError))
+	b.StringFlag("output", "Output file").Alias('o').BuildVar()
+	b.SetUsageTemplate("OPTIONS\n{{range .Flags}}{{.Line}}\n{{end}}")
+	if got, want := b.UsageStringWidth(0), "OPTIONS\n  -o, --output string      Output file\n"; got != want {
+		t.Errorf("expected %q, got %q", want, got)
+	}
+}
+
+func TestSetUsageTemplate_Invalid(t *testing.T) {
+	b := NewFlagBuilderWithSet(flag.NewFlagSet("tool", flag.ContinueOnError))
+	b.SetErrorHandling(ReturnErrors)
+	b.SetUsageTemplate("{{range .Flags}")
+	if err := b.Err(); err == nil || !strings.HasPrefix(err.Error(), "fluentflag: usage template: ") {
+		t.Errorf("expected a usage template error, got %v", err)
+	}
+}
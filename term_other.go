@@ -0,0 +1,13 @@
+// term_other.go
+// Copyright (c) 2025 mattmc3
+// SPDX-License-Identifier: MIT
+// Project home: https://github.com/mattmc3/fluentflag
+
+//go:build !(darwin || freebsd || linux)
+
+package fluentflag
+
+// terminalWidth returns 0, as terminal sizes aren't detected on this platform.
+func terminalWidth(fd uintptr) int {
+	return 0
+}
@@ -0,0 +1,27 @@
+// term_unix.go
+// Copyright (c) 2025 mattmc3
+// SPDX-License-Identifier: MIT
+// Project home: https://github.com/mattmc3/fluentflag
+
+//go:build darwin || freebsd || linux
+
+package fluentflag
+
+import (
+	"syscall"
+	"unsafe"
+)
+
+// terminalWidth returns the width of the terminal open on fd, or 0 if fd is
+// not a terminal. A terminal that doesn't report its size is taken to be 80
+// columns wide.
+func terminalWidth(fd uintptr) int {
+	var ws struct{ row, col, xpixel, ypixel uint16 }
+	_, _, errno := syscall.Syscall(syscall.SYS_IOCTL, fd, uintptr(syscall.TIOCGWINSZ), uintptr(unsafe.Pointer(&ws)))
+	if errno != 0 {
+		return 0
+	} else if ws.col == 0 {
+		return defaultUsageWidth
+	}
+	return int(ws.col)
+}
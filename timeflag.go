@@ -0,0 +1,222 @@
+// timeflag.go
+// Copyright (c) 2025 mattmc3
+// SPDX-License-Identifier: MIT
+// Project home: https://github.com/mattmc3/fluentflag
+
+package fluentflag
+
+import (
+	"fmt"
+	"time"
+)
+
+// timeValue implements flag.Value for a time, parsed with its flag's layout.
+type timeValue struct {
+	flag   *FluentFlag[string]
+	def    time.Time
+	target *time.Time
+}
+
+// String returns the time formatted with the flag's layout.
+func (self *timeValue) String() string {
+	if self.target == nil {
+		return ""
+	}
+	return self.target.Format(self.flag.layout)
+}
+
+// Set parses and validates val and stores it.
+func (self *timeValue) Set(val string) error {
+	t, err := parseTime(self.flag, val)
+	if err != nil {
+		return err
+	}
+	*self.target = t
+	return nil
+}
+
+// Get returns the time.
+func (self *timeValue) Get() any {
+	return *self.target
+}
+
+// optionalValue returns the value used when the flag is given without one.
+func (self *timeValue) optionalValue() (string, bool) {
+	return self.flag.optionalValue()
+}
+
+// list returns the time formatted as a one-element list.
+func (self *timeValue) list() []string {
+	return []string{self.String()}
+}
+
+// setZero sets the time to the zero time.
+func (self *timeValue) setZero() {
+	*self.target = time.Time{}
+}
+
+// cloneFor returns new storage set to the default, for the clone f of its
+// flag.
+func (self *timeValue) cloneFor(f *FluentFlag[string]) fluentValue {
+	target := new(time.Time) // allocate on heap
+	*target = self.def
+	return &timeValue{flag: f, def: self.def, target: target}
+}
+
+// shareWith returns a value for f, a copy of its flag under another name,
+// bound to the same storage.
+func (self *timeValue) shareWith(f *FluentFlag[string]) fluentValue {
+	return &timeValue{flag: f, def: self.def, target: self.target}
+}
+
+// goType returns the Go type of the stored value.
+func (self *timeValue) goType() string {
+	return "time.Time"
+}
+
+// timeValues implements flag.Value for a time flag given more than once.
+type timeValues struct {
+	flag   *FluentFlag[string]
+	target *[]time.Time
+}
+
+// String returns the times formatted with the flag's layout.
+func (self *timeValues) String() string {
+	if self.target == nil {
+		return "[]"
+	}
+	return fmt.Sprint(self.list())
+}
+
+// Set parses and validates val and appends it.
+func (self *timeValues) Set(val string) error {
+	t, err := parseTime(self.flag, val)
+	if err != nil {
+		return err
+	}
+	*self.target = append(*self.target, t)
+	return nil
+}
+
+// Get returns the times.
+func (self *timeValues) Get() any {
+	return *self.target
+}
+
+// optionalValue returns the value used when the flag is given without one.
+func (self *timeValues) optionalValue() (string, bool) {
+	return self.flag.optionalValue()
+}
+
+// list returns the times formatted with the flag's layout.
+func (self *timeValues) list() []string {
+	var vals []string
+	for _, t := range *self.target {
+		vals = append(vals, t.Format(self.flag.layout))
+	}
+	return vals
+}
+
+// setZero empties the slice.
+func (self *timeValues) setZero() {
+	*self.target = []time.Time{}
+}
+
+// cloneFor returns a new, empty slice for the clone f of its flag.
+func (self *timeValues) cloneFor(f *FluentFlag[string]) fluentValue {
+	return &timeValues{flag: f, target: &[]time.Time{}}
+}
+
+// shareWith returns a value for f, a copy of its flag under another name,
+// bound to the same slice.
+func (self *timeValues) shareWith(f *FluentFlag[string]) fluentValue {
+	return &timeValues{flag: f, target: self.target}
+}
+
+// isList reports that the slice collects a time each time it is set.
+func (self *timeValues) isList() bool {
+	return true
+}
+
+// goType returns the Go type of the slice.
+func (self *timeValues) goType() string {
+	return "[]time.Time"
+}
+
+// Layout sets the layout used to parse and print a time flag, as accepted by
+// time.Parse. Time flags default to time.RFC3339.
+func (self *FluentFlag[T]) Layout(layout string) *FluentFlag[T] {
+	if self.layout == "" {
+		self.builder.fail(fmt.Errorf("fluentflag: Layout requires a time flag (--%s)", self.name))
+		return self
+	}
+	self.layout = layout
+	return self
+}
+
+// parseTime transforms and validates s as the time flag f's other values
+// are, then parses it with f's layout.
+func parseTime(f *FluentFlag[string], s string) (time.Time, error) {
+	text, err := f.parse(s)
+	if err != nil {
+		return time.Time{}, err
+	}
+	t, err := time.Parse(f.layout, text)
+	if err != nil {
+		return t, f.rejected(ErrInvalidValue, s, err)
+	}
+	return t, nil
+}
+
+// timeFlag returns the flag as the string flag a time flag is built on, or an
+// error naming method if it isn't a time flag.
+func (self *FluentFlag[T]) timeFlag(method string) (*FluentFlag[string], error) {
+	f, ok := any(self).(*FluentFlag[string])
+	if !ok || self.layout == "" {
+		self.builder.building = nil
+		return nil, fmt.Errorf("fluentflag: %s requires a time flag (--%s)", method, self.name)
+	}
+	return f, nil
+}
+
+// BuildTime registers a time flag and returns a pointer to its value, which
+// is the Default parsed with the flag's layout, or the zero time.
+func (self *FluentFlag[T]) BuildTime() *time.Time {
+	t := new(time.Time) // allocate on heap
+	self.builder.fail(self.tryBuildTime(t))
+	return t
+}
+
+// tryBuildTime registers a time flag that stores its value in ptr.
+func (self *FluentFlag[T]) tryBuildTime(ptr *time.Time) error {
+	f, err := self.timeFlag("BuildTime")
+	if err != nil {
+		return err
+	}
+	var def time.Time
+	if f.defaultVal != "" {
+		if def, err = time.Parse(f.layout, f.defaultVal); err != nil {
+			f.builder.building = nil
+			return fmt.Errorf("fluentflag: --%s has an invalid default %q: %v", f.name, f.defaultVal, err)
+		}
+	}
+	*ptr = def
+	return f.register(&timeValue{flag: f, def: def, target: ptr})
+}
+
+// BuildTimes registers a time flag that accumulates a time each time it is
+// given, and returns a pointer to the slice.
+func (self *FluentFlag[T]) BuildTimes() *[]time.Time {
+	times := &[]time.Time{} // allocate on heap
+	self.builder.fail(self.tryBuildTimes(times))
+	return times
+}
+
+// tryBuildTimes registers a time flag that appends its values to ptr.
+func (self *FluentFlag[T]) tryBuildTimes(ptr *[]time.Time) error {
+	f, err := self.timeFlag("BuildTimes")
+	if err != nil {
+		return err
+	}
+	return f.register(&timeValues{flag: f, target: ptr})
+}
@@ -0,0 +1,167 @@
+// toml.go
+// Copyright (c) 2025 mattmc3
+// SPDX-License-Identifier: MIT
+// Project home: https://github.com/mattmc3/fluentflag
+
+package fluentflag
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// decodeTOML decodes the subset of TOML used by typical config files into v, a
+// *map[string]any: [table] headers, key = value pairs with dotted keys, and
+// values that are strings, numbers, booleans, or arrays of those. Scalars
+// other than strings are kept as written. Arrays of tables and inline tables
+// aren't supported; plug in a full TOML package for those.
+func decodeTOML(data []byte, v any) error {
+	out, ok := v.(*map[string]any)
+	if !ok {
+		return errors.New("fluentflag: decodeTOML needs a *map[string]any")
+	}
+	root := map[string]any{}
+	table := root
+	defined := map[string]bool{} // tables given a [table] header
+	lines := strings.Split(string(data), "\n")
+	for i := 0; i < len(lines); i++ {
+		num := i + 1
+		line := strings.TrimSpace(stripComment(lines[i]))
+		switch {
+		case line == "":
+			continue
+		case strings.HasPrefix(line, "[["):
+			return fmt.Errorf("line %d: arrays of tables are not supported", num)
+		case strings.HasPrefix(line, "["):
+			if !strings.HasSuffix(line, "]") {
+				return fmt.Errorf("line %d: invalid table header %s", num, line)
+			}
+			keys, err := tomlKeys(line[1 : len(line)-1])
+			if err != nil {
+				return fmt.Errorf("line %d: %v", num, err)
+			}
+			path := strings.Join(keys, "\x00")
+			if defined[path] {
+				return fmt.Errorf("line %d: duplicate table %s", num, line)
+			}
+			defined[path] = true
+			if table, err = tomlTable(root, keys); err != nil {
+				return fmt.Errorf("line %d: %v", num, err)
+			}
+			continue
+		}
+		key, raw, ok := strings.Cut(line, "=")
+		if !ok {
+			return fmt.Errorf("line %d: expected key = value, got %s", num, line)
+		}
+		raw = strings.TrimSpace(raw)
+		// Arrays may span lines until their closing bracket.
+		for strings.HasPrefix(raw, "[") && !strings.HasSuffix(raw, "]") && i+1 < len(lines) {
+			i++
+			raw += " " + strings.TrimSpace(stripComment(lines[i]))
+		}
+		val, err := tomlValue(raw)
+		if err != nil {
+			return fmt.Errorf("line %d: %v", num, err)
+		}
+		keys, err := tomlKeys(key)
+		if err != nil {
+			return fmt.Errorf("line %d: %v", num, err)
+		}
+		parent, err := tomlTable(table, keys[:len(keys)-1])
+		if err != nil {
+			return fmt.Errorf("line %d: %v", num, err)
+		}
+		if _, dup := parent[keys[len(keys)-1]]; dup {
+			return fmt.Errorf("line %d: duplicate key %s", num, strings.TrimSpace(key))
+		}
+		parent[keys[len(keys)-1]] = val
+	}
+	*out = root
+	return nil
+}
+
+// tomlKeys splits a dotted key like `server."host.name"` into its parts,
+// respecting quotes, so the parts here are "server" and "host.name".
+func tomlKeys(key string) ([]string, error) {
+	var keys []string
+	var quote byte
+	start := 0
+	for i := 0; i <= len(key); i++ {
+		if i < len(key) {
+			c := key[i]
+			switch {
+			case quote != 0:
+				if c == '\\' && quote == '"' {
+					i++
+				} else if c == quote {
+					quote = 0
+				}
+				continue
+			case c == '"' || c == '\'':
+				quote = c
+				continue
+			case c != '.':
+				continue
+			}
+		}
+		part := strings.TrimSpace(key[start:i])
+		start = i + 1
+		unquoted, err := unquoteScalar(part)
+		if err != nil {
+			return nil, fmt.Errorf("invalid key %s", strings.TrimSpace(key))
+		}
+		if unquoted == "" && part == "" {
+			return nil, fmt.Errorf("invalid key %q", strings.TrimSpace(key))
+		}
+		keys = append(keys, unquoted)
+	}
+	if quote != 0 {
+		return nil, fmt.Errorf("unterminated string in key %s", strings.TrimSpace(key))
+	}
+	return keys, nil
+}
+
+// tomlTable returns the table at the path of keys under root, creating it if
+// needed. An empty path is root itself.
+func tomlTable(root map[string]any, keys []string) (map[string]any, error) {
+	table := root
+	for _, key := range keys {
+		switch next := table[key].(type) {
+		case nil:
+			child := map[string]any{}
+			table[key] = child
+			table = child
+		case map[string]any:
+			table = next
+		default:
+			return nil, fmt.Errorf("key %s is not a table", key)
+		}
+	}
+	return table, nil
+}
+
+// tomlValue parses a TOML value.
+func tomlValue(raw string) (any, error) {
+	switch {
+	case raw == "":
+		return nil, errors.New("missing value")
+	case strings.HasPrefix(raw, "["):
+		if !strings.HasSuffix(raw, "]") {
+			return nil, fmt.Errorf("unterminated array %s", raw)
+		}
+		return splitInlineList(raw)
+	case strings.HasPrefix(raw, "{"):
+		return nil, errors.New("inline tables are not supported")
+	case strings.HasPrefix(raw, `"""`) || strings.HasPrefix(raw, "'''"):
+		return nil, errors.New("multi-line strings are not supported")
+	case raw[0] == '"' || raw[0] == '\'':
+		s, err := unquoteScalar(raw)
+		if err != nil || s == raw {
+			return nil, fmt.Errorf("invalid string %s", raw)
+		}
+		return s, nil
+	}
+	return raw, nil
+}
@@ -0,0 +1,58 @@
+//go:build go1.18
+
+package fluentflag
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestDecodeTOML(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		want    map[string]any
+		wantErr string
+	}{
+		{"empty", "# nothing\n", map[string]any{}, ""},
+		{"scalars", "a = 1_000\nb = \"x # y\" # comment\nc = 'C:\\dir'\nd = true\n", map[string]any{"a": "1_000", "b": "x # y", "c": `C:\dir`, "d": "true"}, ""},
+		{"tables", "top = 1\n[server]\nport = 80\n[server.tls]\ncert = \"c.pem\"\n", map[string]any{"top": "1", "server": map[string]any{"port": "80", "tls": map[string]any{"cert": "c.pem"}}}, ""},
+		{"dotted keys", "server.port = 80\n", map[string]any{"server": map[string]any{"port": "80"}}, ""},
+		{"arrays", "a = []\nb = [\"x\", 'y', 3]\n", map[string]any{"a": []any{}, "b": []any{"x", "y", "3"}}, ""},
+		{"quoted dotted key", "server.\"host.name\" = \"h\"\n'a.b'.c = 1\n", map[string]any{"server": map[string]any{"host.name": "h"}, "a.b": map[string]any{"c": "1"}}, ""},
+		{"quoted key in table", "[\"my.server\"]\n\"host.name\" = \"h\"\n", map[string]any{"my.server": map[string]any{"host.name": "h"}}, ""},
+		{"comment in quoted value", "a = \"x #1\" # note\nb = 'y # 2'\n", map[string]any{"a": "x #1", "b": "y # 2"}, ""},
+		{"multi-line array", "a = [\n  \"x # 1\", # first\n  \"y\",\n]\nb = 2\n", map[string]any{"a": []any{"x # 1", "y"}, "b": "2"}, ""},
+		{"sub-table after table", "[a]\nx = 1\n[a.b]\ny = 2\n", map[string]any{"a": map[string]any{"x": "1", "b": map[string]any{"y": "2"}}}, ""},
+		{"duplicate", "a = 1\na = 2\n", nil, "line 2: duplicate key a"},
+		{"duplicate table", "[a]\nx = 1\n[b]\n[a]\ny = 2\n", nil, "line 4: duplicate table [a]"},
+		{"duplicate quoted table", "[\"a\"]\n[a]\n", nil, "line 2: duplicate table [a]"},
+		{"unterminated key", "\"a.b = 1\n", nil, "line 1: unterminated string in key"},
+		{"empty key part", "a..b = 1\n", nil, "line 1: invalid key"},
+		{"not a table", "a = 1\n[a]\n", nil, "line 2: key a is not a table"},
+		{"array of tables", "[[servers]]\n", nil, "line 1: arrays of tables are not supported"},
+		{"inline table", "a = {b = 1}\n", nil, "line 1: inline tables are not supported"},
+		{"missing value", "a =\n", nil, "line 1: missing value"},
+		{"no equals", "a\n", nil, "line 1: expected key = value"},
+		{"unterminated string", "a = \"x\n", nil, "line 1: invalid string"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got map[string]any
+			err := decodeTOML([]byte(tt.input), &got)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Errorf("expected error containing %q, got %v", tt.wantErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("expected %v, got %v", tt.want, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,68 @@
+// transform.go
+// Copyright (c) 2025 mattmc3
+// SPDX-License-Identifier: MIT
+// Project home: https://github.com/mattmc3/fluentflag
+
+package fluentflag
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// ExpandEnv expands $VAR and ${VAR} references in a string flag's value using
+// os.ExpandEnv as the value is set. Undefined variables expand to the empty
+// string. Values are taken literally unless ExpandEnv is used.
+func (self *FluentFlag[T]) ExpandEnv() *FluentFlag[T] {
+	if !self.requireString("ExpandEnv") {
+		return self
+	}
+	self.transforms = append(self.transforms, func(f *FluentFlag[T], s string) (string, error) {
+		return os.ExpandEnv(s), nil
+	})
+	return self
+}
+
+// ExpandHome expands a leading "~" or "~/" in a string flag's value to the
+// user's home directory as the value is set.
+func (self *FluentFlag[T]) ExpandHome() *FluentFlag[T] {
+	if !self.requireString("ExpandHome") {
+		return self
+	}
+	self.transforms = append(self.transforms, func(f *FluentFlag[T], s string) (string, error) {
+		if s != "~" && !strings.HasPrefix(s, "~/") {
+			return s, nil
+		}
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return "", fmt.Errorf("--%s cannot expand ~: %v", f.name, err)
+		}
+		return filepath.Join(home, s[1:]), nil
+	})
+	return self
+}
+
+// ParseAny replaces the flag's standard parsing with a list of parsers that
+// are tried in order. The first successful result is stored, and if every
+// parser fails the error combines their messages. This suits flags that accept
+// several input shapes, such as a duration, a timestamp, or a keyword.
+func (self *FluentFlag[T]) ParseAny(parsers ...func(string) (T, error)) *FluentFlag[T] {
+	self.parsers = append(self.parsers, parsers...)
+	return self
+}
+
+// parseAny converts s with the first of the flag's parsers that succeeds.
+func (self *FluentFlag[T]) parseAny(s string) (T, error) {
+	var msgs []string
+	for _, parse := range self.parsers {
+		v, err := parse(s)
+		if err == nil {
+			return v, nil
+		}
+		msgs = append(msgs, err.Error())
+	}
+	var zero T
+	return zero, fmt.Errorf("--%s matched no accepted format: %s", self.name, strings.Join(msgs, "; "))
+}
@@ -0,0 +1,131 @@
+//go:build go1.18
+
+package fluentflag
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestExpandEnv(t *testing.T) {
+	t.Setenv("HOME", "/home/tester")
+	t.Setenv("FLUENTFLAG_UNSET", "")
+	tests := []struct {
+		name   string
+		expand bool
+		arg    string
+		want   string
+	}{
+		{"expanded", true, "$HOME/bin", "/home/tester/bin"},
+		{"braces", true, "${HOME}/bin", "/home/tester/bin"},
+		{"undefined", true, "x$FLUENTFLAG_UNSET", "x"},
+		{"off by default", false, "$HOME/bin", "$HOME/bin"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fs := flag.NewFlagSet("test", flag.ContinueOnError)
+			b := NewFlagBuilderWithSet(fs)
+			f := b.StringFlag("path", "search path")
+			if tt.expand {
+				f.ExpandEnv()
+			}
+			path := f.BuildVar()
+			if err := fs.Parse([]string{"--path=" + tt.arg}); err != nil {
+				t.Fatalf("Parse failed: %v", err)
+			}
+			if *path != tt.want {
+				t.Errorf("expected %q, got %q", tt.want, *path)
+			}
+		})
+	}
+}
+
+func TestExpandHome(t *testing.T) {
+	t.Setenv("HOME", "/home/tester")
+	tests := []struct {
+		arg  string
+		want string
+	}{
+		{"~/config", "/home/tester/config"},
+		{"~", "/home/tester"},
+		{"/etc/~/config", "/etc/~/config"},
+		{"~other/config", "~other/config"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.arg, func(t *testing.T) {
+			fs := flag.NewFlagSet("test", flag.ContinueOnError)
+			b := NewFlagBuilderWithSet(fs)
+			path := b.StringFlag("config", "config path").ExpandHome().BuildVar()
+			if err := fs.Parse([]string{"--config", tt.arg}); err != nil {
+				t.Fatalf("Parse failed: %v", err)
+			}
+			if *path != tt.want {
+				t.Errorf("expected %q, got %q", tt.want, *path)
+			}
+		})
+	}
+}
+
+func TestExpandHome_LookupFailure(t *testing.T) {
+	if runtime.GOOS == "windows" || runtime.GOOS == "plan9" {
+		t.Skip("home directory is not read from $HOME")
+	}
+	t.Setenv("HOME", "")
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+	b := NewFlagBuilderWithSet(fs)
+	b.StringFlag("config", "config path").ExpandHome().BuildVar()
+	err := fs.Parse([]string{"--config", "~/config"})
+	if err == nil || !strings.Contains(err.Error(), "--config cannot expand ~") {
+		t.Errorf("expected home lookup error, got %v", err)
+	}
+}
+
+func TestParseAny(t *testing.T) {
+	now := time.Date(2025, 6, 15, 12, 0, 0, 0, time.UTC)
+	fromDuration := func(s string) (int64, error) {
+		d, err := time.ParseDuration(s)
+		return now.Add(-d).Unix(), err
+	}
+	fromTimestamp := func(s string) (int64, error) {
+		ts, err := time.Parse(time.RFC3339, s)
+		return ts.Unix(), err
+	}
+	fromKeyword := func(s string) (int64, error) {
+		if s != "yesterday" {
+			return 0, fmt.Errorf("unknown keyword %q", s)
+		}
+		return now.AddDate(0, 0, -1).Unix(), nil
+	}
+	tests := []struct {
+		arg     string
+		want    int64
+		wantErr string
+	}{
+		{"2h", now.Add(-2 * time.Hour).Unix(), ""},
+		{"2025-06-01T00:00:00Z", time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC).Unix(), ""},
+		{"yesterday", now.AddDate(0, 0, -1).Unix(), ""},
+		{"last week", 0, `--since matched no accepted format: time: invalid duration "last week"; `},
+	}
+	for _, tt := range tests {
+		t.Run(tt.arg, func(t *testing.T) {
+			fs := flag.NewFlagSet("test", flag.ContinueOnError)
+			fs.SetOutput(io.Discard)
+			b := NewFlagBuilderWithSet(fs)
+			since := b.Int64Flag("since", "start time").ParseAny(fromDuration, fromTimestamp, fromKeyword).BuildVar()
+			err := fs.Parse([]string{"--since", tt.arg})
+			if tt.wantErr == "" {
+				if err != nil || *since != tt.want {
+					t.Errorf("expected %d, got %d (err %v)", tt.want, *since, err)
+				}
+			} else if err == nil || !strings.Contains(err.Error(), tt.wantErr) || !strings.Contains(err.Error(), `unknown keyword "last week"`) {
+				t.Errorf("expected error containing %q, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
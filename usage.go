@@ -0,0 +1,406 @@
+// usage.go
+// Copyright (c) 2025 mattmc3
+// SPDX-License-Identifier: MIT
+// Project home: https://github.com/mattmc3/fluentflag
+
+package fluentflag
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// defaultUsageWidth is the width usage text is wrapped to unless overridden.
+const defaultUsageWidth = 80
+
+// usageGroup is a titled section of flags in the usage text.
+type usageGroup struct {
+	title string
+	desc  string
+}
+
+// Group places the flag in the titled section of the usage text. Sections are
+// printed after any ungrouped flags, in the order they were first declared.
+func (self *FluentFlag[T]) Group(title string) *FluentFlag[T] {
+	self.group = title
+	self.builder.usageGroup(title)
+	return self
+}
+
+// groupTitle returns the title of the flag's usage section.
+func (self *FluentFlag[T]) groupTitle() string {
+	return self.group
+}
+
+// Hidden leaves the flag out of the usage text, flag listings, and completion
+// scripts. It is still registered and parsed, for internal or debugging flags.
+func (self *FluentFlag[T]) Hidden() *FluentFlag[T] {
+	self.hidden = true
+	return self
+}
+
+// isHidden reports whether the flag is left out of usage and completions.
+func (self *FluentFlag[T]) isHidden() bool {
+	return self.hidden
+}
+
+// visibleFlags returns the built flags that are not hidden.
+func (b *FlagBuilder) visibleFlags() []builtFlag {
+	var flags []builtFlag
+	for _, f := range b.flagsBuilt {
+		if !f.isHidden() {
+			flags = append(flags, f)
+		}
+	}
+	return flags
+}
+
+// SortFlags sets whether usage lists flags alphabetically by name, within
+// each section. By default they are listed in the order they were defined.
+func (b *FlagBuilder) SortFlags(sorted bool) {
+	b.sortFlags = sorted
+}
+
+// sortedFlags returns flags sorted by name if SortFlags is on, or else flags
+// unchanged.
+func (b *FlagBuilder) sortedFlags(flags []builtFlag) []builtFlag {
+	if !b.sortFlags {
+		return flags
+	}
+	sorted := append([]builtFlag{}, flags...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].names()[0] < sorted[j].names()[0]
+	})
+	return sorted
+}
+
+// UsageHeader sets text printed at the top of the usage text, such as a
+// one-line description of the command.
+func (b *FlagBuilder) UsageHeader(s string) {
+	b.usageHeader = s
+}
+
+// UsageFooter sets text printed at the bottom of the usage text, such as
+// where to report bugs.
+func (b *FlagBuilder) UsageFooter(s string) {
+	b.usageFooter = s
+}
+
+// usageExample is an example command line shown in the usage text.
+type usageExample struct {
+	command string
+	desc    string
+}
+
+// Example adds an example command line to the "Examples:" section of the
+// usage text. Examples are printed in the order they were added.
+func (b *FlagBuilder) Example(command string) {
+	b.examples = append(b.examples, usageExample{command: command})
+}
+
+// ExampleWithDescription adds an example command line with a short
+// description, printed as a comment above it.
+func (b *FlagBuilder) ExampleWithDescription(command, desc string) {
+	b.examples = append(b.examples, usageExample{command: command, desc: desc})
+}
+
+// GroupWithDescription declares a usage section with a short paragraph
+// printed between its title and its flags. The paragraph is wrapped to the
+// usage width.
+func (b *FlagBuilder) GroupWithDescription(title, desc string) {
+	b.usageGroup(title).desc = desc
+}
+
+// SetUsageWidth sets the width that usage text is wrapped to, or turns off
+// wrapping if width is negative. By default, usage printed to a terminal is
+// wrapped to $COLUMNS or else the terminal's width. Usage printed elsewhere
+// leaves flag descriptions unwrapped and wraps group descriptions at 80
+// columns.
+func (b *FlagBuilder) SetUsageWidth(width int) {
+	b.usageWidth = width
+}
+
+// usageGroup returns the section with the given title, declaring it if needed.
+func (b *FlagBuilder) usageGroup(title string) *usageGroup {
+	for _, g := range b.groups {
+		if g.title == title {
+			return g
+		}
+	}
+	g := &usageGroup{title: title}
+	b.groups = append(b.groups, g)
+	return g
+}
+
+// UsageStringWidth returns the usage for all built flags with descriptions
+// wrapped to width columns, regardless of the configured usage width. This
+// gives deterministic output for tests of help text.
+func (b *FlagBuilder) UsageStringWidth(width int) string {
+	var sb strings.Builder
+	b.writeUsage(&sb, width)
+	return sb.String()
+}
+
+// usageSynopsis returns how the command is invoked, like
+// "tool [flags] <file>".
+func (b *FlagBuilder) usageSynopsis() string {
+	line := filepath.Base(b.flagSet.Name())
+	if b.parent != nil {
+		line = b.flagSet.Name()
+	}
+	if len(b.visibleFlags()) > 0 || len(b.globalFlags()) > 0 {
+		line += " [flags]"
+	}
+	if len(b.commands) > 0 {
+		line += " <command>"
+	}
+	for _, p := range b.positionals {
+		arg := "<" + p.name + ">"
+		if p.variadic {
+			arg += "..."
+		}
+		if !p.required {
+			arg = "[" + arg + "]"
+		}
+		line += " " + arg
+	}
+	return line
+}
+
+// EnableHelp registers -h, --help to print the full help text, with the
+// usage header, groups, and examples, to the help output and exit with status
+// 0, whatever the flag set's error handling. Subcommands added afterwards get
+// their own.
+func (b *FlagBuilder) EnableHelp() {
+	b.helpEnabled = true
+	f := b.BoolFlag("help", "Show this help message").Alias('h').Validate(func(on bool) error {
+		if on {
+			b.printHelp(b.helpWriter())
+			b.exit(0)
+		}
+		return nil
+	})
+	f.noConfig = true
+	f.rebuild = func(c *FlagBuilder) {
+		c.EnableHelp()
+	}
+	f.BuildVar()
+}
+
+// printHelp writes the usage header and then usage for all built flags to w.
+func (b *FlagBuilder) printHelp(w io.Writer) {
+	fmt.Fprintln(w, "Usage: "+b.usageSynopsis())
+	fmt.Fprintln(w)
+	b.printUsage(w)
+}
+
+// printUsage writes usage for all built flags to w, wrapped to the width set
+// with SetUsageWidth or detected for w.
+func (b *FlagBuilder) printUsage(w io.Writer) {
+	theme := b.themeFor(w)
+	for p := b; p != nil; p = p.parent {
+		p.activeTheme = theme
+	}
+	defer func() {
+		for p := b; p != nil; p = p.parent {
+			p.activeTheme = nil
+		}
+	}()
+	b.writeUsage(w, b.widthFor(w))
+}
+
+// widthFor returns the width to wrap usage written to w to, or 0 for none.
+func (b *FlagBuilder) widthFor(w io.Writer) int {
+	if b.usageWidth < 0 {
+		return 0
+	} else if b.usageWidth > 0 {
+		return b.usageWidth
+	}
+	f, ok := w.(*os.File)
+	if !ok {
+		return 0
+	}
+	width := terminalWidth(f.Fd())
+	if width == 0 {
+		return 0 // a regular file or pipe, which $COLUMNS doesn't describe
+	}
+	if cols, err := strconv.Atoi(os.Getenv("COLUMNS")); err == nil && cols > 0 {
+		return cols
+	}
+	return width
+}
+
+// writeUsage writes usage for all built flags to w with descriptions wrapped
+// to width columns, or unwrapped if width is zero.
+func (b *FlagBuilder) writeUsage(w io.Writer, width int) {
+	if b.usageTemplate != nil {
+		b.writeTemplateUsage(w, width)
+		return
+	}
+	theme := b.activeTheme
+	header := func(title string) {
+		fmt.Fprintln(w, theme.header(title+":"))
+	}
+	flags := b.sortedFlags(b.visibleFlags())
+	printed := false
+	if b.usageHeader != "" {
+		fmt.Fprintln(w, b.usageHeader)
+		fmt.Fprintln(w)
+	}
+	for _, f := range flags {
+		if f.groupTitle() == "" {
+			fmt.Fprintln(w, f.usageLine(width))
+			printed = true
+		}
+	}
+	for _, g := range b.groups {
+		var members []builtFlag
+		for _, f := range flags {
+			if f.groupTitle() == g.title {
+				members = append(members, f)
+			}
+		}
+		if len(members) == 0 {
+			continue
+		}
+		if printed {
+			fmt.Fprintln(w)
+		}
+		header(g.title)
+		if g.desc != "" {
+			descWidth := width
+			if descWidth <= 0 {
+				descWidth = defaultUsageWidth
+			}
+			for _, line := range wrapText(g.desc, descWidth-2) {
+				fmt.Fprintf(w, "  %s\n", line)
+			}
+			fmt.Fprintln(w)
+		}
+		for _, f := range members {
+			fmt.Fprintln(w, f.usageLine(width))
+		}
+		printed = true
+	}
+	if len(b.positionals) > 0 {
+		if printed {
+			fmt.Fprintln(w)
+		}
+		header("Arguments")
+		for _, p := range b.positionals {
+			fmt.Fprintln(w, p.usageLine(width))
+		}
+		printed = true
+	}
+	var globals []builtFlag
+	for _, f := range b.globalFlags() {
+		if !f.isHidden() {
+			globals = append(globals, f)
+		}
+	}
+	globals = b.sortedFlags(globals)
+	if len(globals) > 0 {
+		if printed {
+			fmt.Fprintln(w)
+		}
+		header("Global Flags")
+		for _, f := range globals {
+			fmt.Fprintln(w, f.usageLine(width))
+		}
+		printed = true
+	}
+	if len(b.commands) > 0 {
+		if printed {
+			fmt.Fprintln(w)
+		}
+		header("Commands")
+		for _, c := range b.commands {
+			fmt.Fprintln(w, formatUsageLine(c.name, c.usage, width))
+		}
+		printed = true
+	}
+	if len(b.examples) > 0 {
+		if printed {
+			fmt.Fprintln(w)
+		}
+		header("Examples")
+		for _, ex := range b.examples {
+			if ex.desc != "" {
+				fmt.Fprintf(w, "  # %s\n", ex.desc)
+			}
+			fmt.Fprintf(w, "  %s\n", ex.command)
+		}
+		printed = true
+	}
+	if b.usageFooter != "" {
+		if printed {
+			fmt.Fprintln(w)
+		}
+		fmt.Fprintln(w, b.usageFooter)
+	}
+}
+
+// usageColumn is the width of the names column in the usage text.
+const usageColumn = 25
+
+// minDescWidth is the narrowest description column. Below it, descriptions
+// start on the line after the flag's names with a short indent instead.
+const minDescWidth = 20
+
+// formatUsageLine lays out a flag's names column and description, wrapping
+// the description to width columns with a hanging indent, or leaving it
+// unwrapped if width is zero.
+func formatUsageLine(names, desc string, width int) string {
+	lines := []string{desc}
+	if width > 0 && width-usageColumn-2 < minDescWidth {
+		var sb strings.Builder
+		sb.WriteString("  " + names)
+		for _, line := range wrapText(desc, width-8) {
+			sb.WriteString("\n        " + line)
+		}
+		return sb.String()
+	}
+	if width > 0 {
+		if wrapped := wrapText(desc, width-usageColumn-2); len(wrapped) > 0 {
+			lines = wrapped
+		}
+	}
+	indent := strings.Repeat(" ", usageColumn+2)
+	var sb strings.Builder
+	if n := visibleLen(names); n >= usageColumn {
+		fmt.Fprintf(&sb, "  %s\n%s%s", names, indent, lines[0])
+	} else {
+		fmt.Fprintf(&sb, "  %s%s%s", names, strings.Repeat(" ", usageColumn-n), lines[0])
+	}
+	for _, line := range lines[1:] {
+		sb.WriteString("\n" + indent + line)
+	}
+	return sb.String()
+}
+
+// wrapText splits s into lines of at most width characters, breaking between
+// words. Words longer than width are kept whole.
+func wrapText(s string, width int) []string {
+	var lines []string
+	line := ""
+	for _, word := range strings.Fields(s) {
+		switch {
+		case line == "":
+			line = word
+		case visibleLen(line)+1+visibleLen(word) <= width:
+			line += " " + word
+		default:
+			lines = append(lines, line)
+			line = word
+		}
+	}
+	if line != "" {
+		lines = append(lines, line)
+	}
+	return lines
+}
@@ -0,0 +1,387 @@
+//go:build go1.18
+
+package fluentflag
+
+import (
+	"errors"
+	"flag"
+	"io"
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestGroupWithDescription(t *testing.T) {
+	b := NewFlagBuilderWithSet(flag.NewFlagSet("test", flag.ContinueOnError))
+	b.SetUsageWidth(50)
+	b.GroupWithDescription("Output options", "Control how results are rendered and where they are written.")
+	b.BoolFlag("help", "Show this help message").Alias('h').BuildVar()
+	b.StringFlag("format", "Output format").Alias('f').Group("Output options").BuildVar()
+	b.BoolFlag("verbose", "Print more").Alias('v').Group("Logging").BuildVar()
+	b.StringFlag("output", "Output file").Alias('o').Group("Output options").BuildVar()
+
+	var buf strings.Builder
+	b.SetOutput(&buf)
+	b.PrintUsage()
+
+	expected := `  -h, --help               Show this help message
+
+Output options:
+  Control how results are rendered and where they
+  are written.
+
+  -f, --format string      Output format
+  -o, --output string      Output file
+
+Logging:
+  -v, --verbose            Print more
+`
+	if buf.String() != expected {
+		t.Errorf("Usage output mismatch.\nGot:\n%s\nWant:\n%s", buf.String(), expected)
+	}
+}
+
+func TestWrapText(t *testing.T) {
+	got := wrapText("the quick brown fox jumps over the lazy dog", 10)
+	want := []string{"the quick", "brown fox", "jumps over", "the lazy", "dog"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("expected %q, got %q", want, got)
+	}
+	if got := wrapText("", 10); got != nil {
+		t.Errorf("expected no lines, got %q", got)
+	}
+}
+
+func TestUsageStringWidth(t *testing.T) {
+	b := NewFlagBuilderWithSet(flag.NewFlagSet("test", flag.ContinueOnError))
+	b.StringFlag("name", "Command name for error messages").Alias('n').Default("foo").BuildVar()
+	b.BoolFlag("help", "Show this help message").Alias('h').BuildVar()
+	b.IntFlag("min-args", "Minimum number of non-option arguments").Alias('N').Default(-1).BuildVar()
+	b.StringFlag("this-is-a-very-long-flag-name-for-testing", "A very long flag name to test wrapping").Alias('L').Default("long").BuildVar()
+
+	expected := `  -n, --name string        Command name for error messages
+                           (default "foo")
+  -h, --help               Show this help message
+  -N, --min-args int       Minimum number of non-option
+                           arguments (default -1)
+  -L, --this-is-a-very-long-flag-name-for-testing string
+                           A very long flag name to test
+                           wrapping (default "long")
+`
+	if actual := b.UsageStringWidth(60); actual != expected {
+		t.Errorf("Usage output mismatch.\nGot:\n%s\nWant:\n%s", actual, expected)
+	}
+}
+
+func TestHidden(t *testing.T) {
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	b := NewFlagBuilderWithSet(fs)
+	verbose := b.BoolFlag("verbose", "Print more").Alias('v').BuildVar()
+	trace := b.BoolFlag("trace-internals", "Dump internal state").Hidden().BuildVar()
+	b.BoolFlag("debug-gc", "Log collections").Group("Debugging").Hidden().BuildVar()
+
+	if _, err := b.Parse([]string{"-v", "--trace-internals"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !*verbose || !*trace {
+		t.Errorf("expected both flags set, got verbose=%v trace=%v", *verbose, *trace)
+	}
+
+	var buf strings.Builder
+	b.SetOutput(&buf)
+	b.PrintUsage()
+	if want := "  -v, --verbose            Print more\n"; buf.String() != want {
+		t.Errorf("Usage output mismatch.\nGot:\n%s\nWant:\n%s", buf.String(), want)
+	}
+
+	var script strings.Builder
+	if err := b.GenerateCompletion(&script, "bash", "prog"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if strings.Contains(script.String(), "trace-internals") {
+		t.Errorf("hidden flag in completion script:\n%s", script.String())
+	}
+}
+
+func TestGroup_DefinitionOrder(t *testing.T) {
+	b := NewFlagBuilderWithSet(flag.NewFlagSet("test", flag.ContinueOnError))
+	b.StringFlag("format", "Output format").Group("Output options").BuildVar()
+	b.BoolFlag("verbose", "Print more").Group("Logging").BuildVar()
+	b.BoolFlag("help", "Show this help message").BuildVar()
+	b.StringFlag("output", "Output file").Group("Output options").BuildVar()
+	b.BoolFlag("quiet", "Print less").Group("Logging").BuildVar()
+
+	expected := `      --help               Show this help message
+
+Output options:
+      --format string      Output format
+      --output string      Output file
+
+Logging:
+      --verbose            Print more
+      --quiet              Print less
+`
+	if got := b.UsageStringWidth(0); got != expected {
+		t.Errorf("Usage output mismatch.\nGot:\n%s\nWant:\n%s", got, expected)
+	}
+}
+
+func TestPlaceholder(t *testing.T) {
+	b := NewFlagBuilderWithSet(flag.NewFlagSet("test", flag.ContinueOnError))
+	b.StringFlag("output", "Output file").Alias('o').Placeholder("FILE").BuildVar()
+	b.DurationFlag("wait", "How long to wait").Placeholder("TIME").OptionalValue(time.Second).BuildVar()
+	b.StringFlag("mode", "Mode").Choices("fast", "safe").Placeholder("MODE").BuildVar()
+	expected := `  -o, --output FILE        Output file
+      --wait TIME[=1s]     How long to wait
+      --mode MODE          Mode (choices: fast, safe)
+`
+	if got := b.UsageStringWidth(0); got != expected {
+		t.Errorf("Usage output mismatch.\nGot:\n%s\nWant:\n%s", got, expected)
+	}
+}
+
+func TestDefaultText(t *testing.T) {
+	b := NewFlagBuilderWithSet(flag.NewFlagSet("test", flag.ContinueOnError))
+	dir := b.StringFlag("config-dir", "Config directory").Default("/home/me/.config/app").DefaultText("$HOME/.config/app").BuildVar()
+	b.IntFlag("jobs", "Parallel jobs").DefaultText("number of CPUs").BuildVar()
+	expected := `      --config-dir string  Config directory (default $HOME/.config/app)
+      --jobs int           Parallel jobs (default number of CPUs)
+`
+	if got := b.UsageStringWidth(0); got != expected {
+		t.Errorf("Usage output mismatch.\nGot:\n%s\nWant:\n%s", got, expected)
+	}
+	if *dir != "/home/me/.config/app" {
+		t.Errorf("expected the default value to be unchanged, got %q", *dir)
+	}
+}
+
+func TestHideDefault(t *testing.T) {
+	b := NewFlagBuilderWithSet(flag.NewFlagSet("test", flag.ContinueOnError))
+	b.SetAlwaysShowDefault(true)
+	token := b.StringFlag("token", "API token").Default("s3cr3t").Env("API_TOKEN").HideDefault().BuildVar()
+	b.BoolFlag("verbose", "Print more").HideDefault().BuildVar()
+	expected := `      --token string       API token [env: API_TOKEN]
+      --verbose            Print more
+`
+	if got := b.UsageStringWidth(0); got != expected {
+		t.Errorf("Usage output mismatch.\nGot:\n%s\nWant:\n%s", got, expected)
+	}
+	if *token != "s3cr3t" {
+		t.Errorf("expected the default to still apply, got %q", *token)
+	}
+}
+
+func TestSortFlags(t *testing.T) {
+	b := NewFlagBuilderWithSet(flag.NewFlagSet("test", flag.ContinueOnError))
+	b.BoolFlag("verbose", "Print more").BuildVar()
+	b.StringFlag("output", "Output file").Alias('o').BuildVar()
+	b.BoolFlag("quiet", "Print less").Group("Logging").BuildVar()
+	b.BoolFlag("debug", "Print everything").Group("Logging").BuildVar()
+	b.BoolFlag("all", "Everything").BuildVar()
+
+	b.SortFlags(true)
+	expected := `      --all                Everything
+  -o, --output string      Output file
+      --verbose            Print more
+
+Logging:
+      --debug              Print everything
+      --quiet              Print less
+`
+	if got := b.UsageStringWidth(0); got != expected {
+		t.Errorf("Usage output mismatch.\nGot:\n%s\nWant:\n%s", got, expected)
+	}
+
+	b.SortFlags(false)
+	if got := b.UsageStringWidth(0); !strings.HasPrefix(got, "      --verbose") {
+		t.Errorf("expected definition order, got:\n%s", got)
+	}
+}
+
+func TestUsageWidthDetection(t *testing.T) {
+	file, err := os.CreateTemp(t.TempDir(), "usage")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+
+	b := NewFlagBuilderWithSet(flag.NewFlagSet("test", flag.ContinueOnError))
+	t.Setenv("COLUMNS", "")
+	if got := b.widthFor(file); got != 0 {
+		t.Errorf("expected no wrapping for a regular file, got %d", got)
+	}
+	if got := b.widthFor(&strings.Builder{}); got != 0 {
+		t.Errorf("expected no wrapping for a buffer, got %d", got)
+	}
+	t.Setenv("COLUMNS", "100")
+	if got := b.widthFor(file); got != 0 {
+		t.Errorf("expected $COLUMNS to be ignored for a regular file, got %d", got)
+	}
+	b.SetUsageWidth(60)
+	if got := b.widthFor(file); got != 60 {
+		t.Errorf("expected override width 60, got %d", got)
+	}
+	b.SetUsageWidth(-1)
+	if got := b.widthFor(file); got != 0 {
+		t.Errorf("expected wrapping off, got %d", got)
+	}
+}
+
+func TestUsageNarrowWidth(t *testing.T) {
+	b := NewFlagBuilderWithSet(flag.NewFlagSet("test", flag.ContinueOnError))
+	b.StringFlag("output", "Where to write the rendered report").Alias('o').BuildVar()
+	expected := `  -o, --output string
+        Where to write the
+        rendered report
+`
+	if got := b.UsageStringWidth(30); got != expected {
+		t.Errorf("Usage output mismatch.\nGot:\n%s\nWant:\n%s", got, expected)
+	}
+}
+
+func TestHelpHeader(t *testing.T) {
+	fs := flag.NewFlagSet("/usr/bin/tool", flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+	b := NewFlagBuilderWithSet(fs)
+	var help strings.Builder
+	b.SetHelpOutput(&help)
+	b.StringFlag("output", "Output file").Alias('o').BuildVar()
+	b.Positional("src", "Source file").Required().BuildString()
+	b.Positional("dest", "Destination").BuildString()
+
+	if _, err := b.Parse([]string{"-h"}); !errors.Is(err, flag.ErrHelp) {
+		t.Fatalf("expected flag.ErrHelp, got %v", err)
+	}
+	want := `Usage: tool [flags] <src> [<dest>]
+
+  -o, --output string      Output file
+
+Arguments:
+  src                      Source file (required)
+  dest                     Destination
+`
+	if help.String() != want {
+		t.Errorf("help mismatch.\nGot:\n%s\nWant:\n%s", help.String(), want)
+	}
+
+	// Parsing the flag set directly prints the same help.
+	var out strings.Builder
+	b.SetOutput(&out)
+	if err := fs.Parse([]string{"--bogus"}); err == nil {
+		t.Fatal("expected an error for an unknown flag")
+	}
+	if out.String() != want {
+		t.Errorf("help mismatch.\nGot:\n%s\nWant:\n%s", out.String(), want)
+	}
+}
+
+func TestExamples(t *testing.T) {
+	b := NewFlagBuilderWithSet(flag.NewFlagSet("mytool", flag.ContinueOnError))
+	b.StringFlag("format", "Output format").BuildVar()
+	b.ExampleWithDescription("mytool --format json ./input", "Convert the input to JSON")
+	b.Example("mytool ./input")
+
+	expected := `      --format string      Output format
+
+Examples:
+  # Convert the input to JSON
+  mytool --format json ./input
+  mytool ./input
+`
+	if got := b.UsageStringWidth(0); got != expected {
+		t.Errorf("Usage output mismatch.\nGot:\n%s\nWant:\n%s", got, expected)
+	}
+
+	b.SetUsageTemplate("{{range .Examples}}{{.Command}}|{{.Description}}\n{{end}}")
+	if got, want := b.UsageStringWidth(0), "mytool --format json ./input|Convert the input to JSON\nmytool ./input|\n"; got != want {
+		t.Errorf("expected %q, got %q", want, got)
+	}
+}
+
+func TestUsageHeaderFooter(t *testing.T) {
+	fs := flag.NewFlagSet("mytool", flag.ContinueOnError)
+	b := NewFlagBuilderWithSet(fs)
+	var help strings.Builder
+	b.SetHelpOutput(&help)
+	b.StringFlag("format", "Output format").BuildVar()
+	b.UsageHeader("Convert files between formats.")
+	b.UsageFooter("Report bugs at https://example.com/mytool/issues")
+
+	if _, err := b.Parse([]string{"--help"}); !errors.Is(err, flag.ErrHelp) {
+		t.Fatalf("expected flag.ErrHelp, got %v", err)
+	}
+	expected := `Usage: mytool [flags]
+
+Convert files between formats.
+
+      --format string      Output format
+
+Report bugs at https://example.com/mytool/issues
+`
+	if help.String() != expected {
+		t.Errorf("help mismatch.\nGot:\n%s\nWant:\n%s", help.String(), expected)
+	}
+}
+
+func TestEnableHelp(t *testing.T) {
+	for _, arg := range []string{"-h", "--help"} {
+		t.Run(arg, func(t *testing.T) {
+			b := NewFlagBuilderWithSet(flag.NewFlagSet("test", flag.ContinueOnError))
+			b.EnableHelp()
+			b.UsageHeader("Process input files")
+			b.StringFlag("host", "Host to connect to").Required().Group("Connection").BuildVar()
+			b.Example("test --host example.com")
+			var out, errOut strings.Builder
+			exitCode := -1
+			b.SetHelpOutput(&out)
+			b.SetOutput(&errOut)
+			b.SetExitFunc(func(code int) { exitCode = code })
+			b.Parse([]string{arg})
+
+			expected := `Usage: test [flags]
+
+Process input files
+
+  -h, --help               Show this help message
+
+Connection:
+      --host string        Host to connect to (required)
+
+Examples:
+  test --host example.com
+`
+			if out.String() != expected {
+				t.Errorf("Usage output mismatch.\nGot:\n%s\nWant:\n%s", out.String(), expected)
+			}
+			if exitCode != 0 {
+				t.Errorf("expected exit code 0, got %d", exitCode)
+			}
+			if errOut.String() != "" {
+				t.Errorf("expected nothing on the error output, got:\n%s", errOut.String())
+			}
+		})
+	}
+}
+
+func TestEnableHelp_Subcommand(t *testing.T) {
+	app := NewAppWithSet(flag.NewFlagSet("tool", flag.ContinueOnError))
+	app.EnableHelp()
+	serve := app.Command("serve", "Run the server")
+	serve.IntFlag("port", "Port to listen on").BuildVar()
+	var out strings.Builder
+	exitCode := -1
+	app.SetHelpOutput(&out)
+	serve.SetHelpOutput(&out)
+	app.SetExitFunc(func(code int) { exitCode = code })
+	serve.SetExitFunc(func(code int) { exitCode = code })
+	app.Dispatch([]string{"serve", "--help"})
+
+	if !strings.HasPrefix(out.String(), "Usage: tool serve [flags]\n") || !strings.Contains(out.String(), "--port int") {
+		t.Errorf("expected the subcommand's help, got:\n%s", out.String())
+	}
+	if exitCode != 0 {
+		t.Errorf("expected exit code 0, got %d", exitCode)
+	}
+}
@@ -0,0 +1,562 @@
+// validate.go
+// Copyright (c) 2025 mattmc3
+// SPDX-License-Identifier: MIT
+// Project home: https://github.com/mattmc3/fluentflag
+
+package fluentflag
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"net/mail"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// ChoicesFromFlag restricts the flag to one of the values given to another
+// (typically slice) flag. The check runs in FlagBuilder.Validate, after parsing,
+// and only when this flag was set.
+func (self *FluentFlag[T]) ChoicesFromFlag(otherName string) *FluentFlag[T] {
+	self.choicesFrom = otherName
+	return self
+}
+
+// Choices restricts the flag to the given values. Other values are rejected
+// when set, and the choices are listed in the usage text.
+// Calling Choices again replaces the earlier choices. Calling it without
+// any choices is misconfiguration, since no value could be accepted.
+func (self *FluentFlag[T]) Choices(choices ...T) *FluentFlag[T] {
+	if len(choices) == 0 {
+		self.builder.fail(fmt.Errorf("fluentflag: Choices for --%s needs at least one choice", self.name))
+		return self
+	}
+	if self.choices == nil {
+		self.checks = append(self.checks, (*FluentFlag[T]).checkChoice)
+	}
+	self.choices = append([]T{}, choices...)
+	return self
+}
+
+// checkChoice rejects values that aren't one of the flag's choices.
+func (self *FluentFlag[T]) checkChoice(v T) error {
+	for _, c := range self.choices {
+		if v == c {
+			return nil
+		}
+	}
+	return fmt.Errorf("--%s must be one of [%s], got %q", self.name, strings.Join(self.choiceStrings(), " "), self.format(v))
+}
+
+// choiceStrings returns the flag's choices formatted as strings.
+func (self *FluentFlag[T]) choiceStrings() []string {
+	var strs []string
+	for _, c := range self.choices {
+		strs = append(strs, self.format(c))
+	}
+	return strs
+}
+
+// Required makes Validate fail if the flag is never set, either on the command
+// line or from a config file. Required flags are marked in the usage text.
+func (self *FluentFlag[T]) Required() *FluentFlag[T] {
+	self.required = true
+	return self
+}
+
+// Validate adds a custom check on the flag's parsed value. A non-nil error
+// rejects the value at parse time, and is reported through the flag set's
+// error handling like any other invalid value.
+func (self *FluentFlag[T]) Validate(fn func(T) error) *FluentFlag[T] {
+	self.checks = append(self.checks, func(_ *FluentFlag[T], v T) error {
+		return fn(v)
+	})
+	return self
+}
+
+// Positive requires a numeric flag's value to be greater than zero. Like Min,
+// the allowed range is shown in the usage text.
+func (self *FluentFlag[T]) Positive() *FluentFlag[T] {
+	var zero T
+	return self.atLeast("Positive", zero, true, func(f *FluentFlag[T], _ T) error {
+		return fmt.Errorf("--%s must be positive", f.name)
+	})
+}
+
+// NonNegative requires a numeric flag's value to be zero or greater. Like Min,
+// the allowed range is shown in the usage text.
+func (self *FluentFlag[T]) NonNegative() *FluentFlag[T] {
+	var zero T
+	return self.atLeast("NonNegative", zero, false, func(f *FluentFlag[T], _ T) error {
+		return fmt.Errorf("--%s must not be negative", f.name)
+	})
+}
+
+// Min requires a numeric flag's value to be at least n. The allowed range is
+// shown in the usage text.
+func (self *FluentFlag[T]) Min(n T) *FluentFlag[T] {
+	return self.atLeast("Min", n, false, func(f *FluentFlag[T], v T) error {
+		return fmt.Errorf("--%s must be at least %s, got %s", f.name, f.format(n), f.format(v))
+	})
+}
+
+// atLeast sets the lower bound of a numeric flag for method, rejecting values
+// below n, or equal to it if exclusive, with the error from tooLow.
+func (self *FluentFlag[T]) atLeast(method string, n T, exclusive bool, tooLow func(f *FluentFlag[T], v T) error) *FluentFlag[T] {
+	if !self.requireNumeric(method) {
+		return self
+	}
+	self.minVal, self.minExclusive = &n, exclusive
+	self.checks = append(self.checks, func(f *FluentFlag[T], v T) error {
+		if c := compareNumeric(v, n); c < 0 || (exclusive && c == 0) {
+			return tooLow(f, v)
+		}
+		return nil
+	})
+	return self
+}
+
+// Max requires a numeric flag's value to be at most n. The allowed range is
+// shown in the usage text.
+func (self *FluentFlag[T]) Max(n T) *FluentFlag[T] {
+	if !self.requireNumeric("Max") {
+		return self
+	}
+	self.maxVal = &n
+	self.checks = append(self.checks, func(f *FluentFlag[T], v T) error {
+		if compareNumeric(v, n) > 0 {
+			return fmt.Errorf("--%s must be at most %s, got %s", f.name, f.format(n), f.format(v))
+		}
+		return nil
+	})
+	return self
+}
+
+// rangeString returns the range allowed by Min and Max as shown in usage, like
+// "1-65535", ">= 1", "> 0" or "<= 10", or "" if neither is set.
+func (self *FluentFlag[T]) rangeString() string {
+	lower := ">= "
+	if self.minExclusive {
+		lower = "> "
+	}
+	switch {
+	case self.minVal != nil && self.maxVal != nil && self.minExclusive:
+		return lower + self.format(*self.minVal) + ", <= " + self.format(*self.maxVal)
+	case self.minVal != nil && self.maxVal != nil:
+		return self.format(*self.minVal) + "-" + self.format(*self.maxVal)
+	case self.minVal != nil:
+		return lower + self.format(*self.minVal)
+	case self.maxVal != nil:
+		return "<= " + self.format(*self.maxVal)
+	}
+	return ""
+}
+
+// ASCIIOnly requires a string flag's value to contain only ASCII characters.
+func (self *FluentFlag[T]) ASCIIOnly() *FluentFlag[T] {
+	if !self.requireString("ASCIIOnly") {
+		return self
+	}
+	self.checks = append(self.checks, func(f *FluentFlag[T], v T) error {
+		s := any(v).(string)
+		for i := 0; i < len(s); i++ {
+			if s[i] > unicode.MaxASCII {
+				return fmt.Errorf("--%s must be ASCII, got byte 0x%02x at offset %d", f.name, s[i], i)
+			}
+		}
+		return nil
+	})
+	return self
+}
+
+// ValidUTF8 requires a string flag's value to be valid UTF-8.
+func (self *FluentFlag[T]) ValidUTF8() *FluentFlag[T] {
+	if !self.requireString("ValidUTF8") {
+		return self
+	}
+	self.checks = append(self.checks, func(f *FluentFlag[T], v T) error {
+		s := any(v).(string)
+		for i := 0; i < len(s); {
+			r, size := utf8.DecodeRuneInString(s[i:])
+			if r == utf8.RuneError && size == 1 {
+				return fmt.Errorf("--%s must be valid UTF-8, got byte 0x%02x at offset %d", f.name, s[i], i)
+			}
+			i += size
+		}
+		return nil
+	})
+	return self
+}
+
+// MaxLen requires a string flag's value to be at most n characters long.
+func (self *FluentFlag[T]) MaxLen(n int) *FluentFlag[T] {
+	if !self.requireString("MaxLen") {
+		return self
+	}
+	self.checks = append(self.checks, func(f *FluentFlag[T], v T) error {
+		if length, unit := f.length(any(v).(string)); length > n {
+			return fmt.Errorf("--%s must be at most %d %s, got %d", f.name, n, unit, length)
+		}
+		return nil
+	})
+	return self
+}
+
+// MinLen requires a string flag's value to be at least n characters long.
+func (self *FluentFlag[T]) MinLen(n int) *FluentFlag[T] {
+	if !self.requireString("MinLen") {
+		return self
+	}
+	self.checks = append(self.checks, func(f *FluentFlag[T], v T) error {
+		if length, unit := f.length(any(v).(string)); length < n {
+			return fmt.Errorf("--%s must be at least %d %s, got %d", f.name, n, unit, length)
+		}
+		return nil
+	})
+	return self
+}
+
+// Match requires a string flag's value to match the regular expression
+// pattern. The pattern is compiled once, and an invalid one is misconfiguration.
+func (self *FluentFlag[T]) Match(pattern string) *FluentFlag[T] {
+	if !self.requireString("Match") {
+		return self
+	}
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		self.builder.fail(fmt.Errorf("fluentflag: Match pattern for --%s: %v", self.name, err))
+		return self
+	}
+	self.checks = append(self.checks, func(f *FluentFlag[T], v T) error {
+		if !re.MatchString(any(v).(string)) {
+			return fmt.Errorf("--%s must match %s, got %q", f.name, pattern, any(v).(string))
+		}
+		return nil
+	})
+	return self
+}
+
+// CountBytes makes MaxLen and MinLen count bytes rather than characters.
+func (self *FluentFlag[T]) CountBytes() *FluentFlag[T] {
+	self.countBytes = true
+	return self
+}
+
+// length returns the length of s and its unit for MaxLen and MinLen.
+func (self *FluentFlag[T]) length(s string) (int, string) {
+	if self.countBytes {
+		return len(s), "bytes"
+	}
+	return utf8.RuneCountInString(s), "characters"
+}
+
+// Email requires a string flag's value to be an email address, such as
+// "jo@example.com" or "Jo <jo@example.com>", and stores the bare address.
+// Only the syntax is checked, not whether the address can receive mail.
+func (self *FluentFlag[T]) Email() *FluentFlag[T] {
+	if !self.requireString("Email") {
+		return self
+	}
+	self.transforms = append(self.transforms, func(f *FluentFlag[T], s string) (string, error) {
+		addr, err := mail.ParseAddress(s)
+		if err != nil {
+			return "", fmt.Errorf("--%s must be an email address: %v", f.name, err)
+		}
+		return addr.Address, nil
+	})
+	return self
+}
+
+// requireString reports whether the flag's type is string, failing if not.
+func (self *FluentFlag[T]) requireString(method string) bool {
+	var zero T
+	if _, ok := any(zero).(string); !ok {
+		self.builder.fail(fmt.Errorf("fluentflag: %s requires a string flag (--%s)", method, self.name))
+		return false
+	}
+	return true
+}
+
+// requireNumeric reports whether the flag's type is numeric, failing if not.
+func (self *FluentFlag[T]) requireNumeric(method string) bool {
+	var zero T
+	if _, ok := toFloat64(zero); !ok {
+		self.builder.fail(fmt.Errorf("fluentflag: %s requires a numeric flag (--%s)", method, self.name))
+		return false
+	}
+	return true
+}
+
+// toFloat64 converts a numeric flag value to float64. Types are matched by
+// kind, so named types like time.Duration or a user's ~int type are numeric.
+func toFloat64[T FlagType](v T) (float64, bool) {
+	rv := reflect.ValueOf(v)
+	switch {
+	case rv.CanInt():
+		return float64(rv.Int()), true
+	case rv.CanUint():
+		return float64(rv.Uint()), true
+	case rv.CanFloat():
+		return rv.Float(), true
+	}
+	return 0, false
+}
+
+// compareNumeric compares the numeric values a and b in their own kind, so
+// large int64 and uint64 values don't lose precision, and returns -1, 0, or +1.
+func compareNumeric[T FlagType](a, b T) int {
+	va, vb := reflect.ValueOf(a), reflect.ValueOf(b)
+	switch {
+	case va.CanInt():
+		return compareOrdered(va.Int(), vb.Int())
+	case va.CanUint():
+		return compareOrdered(va.Uint(), vb.Uint())
+	case va.CanFloat():
+		return compareOrdered(va.Float(), vb.Float())
+	}
+	return 0
+}
+
+// compareOrdered returns -1, 0, or +1 as a is less than, equal to, or greater
+// than b.
+func compareOrdered[N int64 | uint64 | float64](a, b N) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	}
+	return 0
+}
+
+// confirmation is how a dangerous flag must be confirmed.
+type confirmation struct {
+	env   string
+	flags []string
+}
+
+// RequireConfirmation makes a bool flag like --force fail validation unless
+// the environment variable env is set to a true value, like "1" or "true", or
+// one of confirmFlags, like --yes, is also set. Any other value of env,
+// including one strconv.ParseBool can't parse, doesn't confirm. A warning is
+// written to the output when the flag is used without confirmation.
+func (self *FluentFlag[T]) RequireConfirmation(env string, confirmFlags ...string) *FluentFlag[T] {
+	self.confirm = &confirmation{env: env, flags: confirmFlags}
+	return self
+}
+
+// confirmed reports whether a dangerous flag's confirmation was given.
+func (b *FlagBuilder) confirmed(c *confirmation) bool {
+	if val, _ := b.lookupEnv(c.env); c.env != "" && val != "" {
+		if ok, err := strconv.ParseBool(val); err == nil && ok {
+			return true
+		}
+	}
+	for _, name := range c.flags {
+		if f := b.lookup(name); f != nil && b.isOn(f) {
+			return true
+		}
+	}
+	return false
+}
+
+// MinCount requires a slice flag to be given at least n values. It is checked
+// by FlagBuilder.Validate, once all values are known. Building the flag with
+// Build or BuildVar rather than BuildSlice is misconfiguration.
+func (self *FluentFlag[T]) MinCount(n int) *FluentFlag[T] {
+	self.minCount = n
+	return self
+}
+
+// MaxCount allows a slice flag to be given at most n values. It is checked by
+// FlagBuilder.Validate, once all values are known.
+func (self *FluentFlag[T]) MaxCount(n int) *FluentFlag[T] {
+	self.maxCount = n
+	return self
+}
+
+// checkSingle returns an error if the flag, built to hold a single value, was
+// given a count that only a slice flag can satisfy.
+func (self *FluentFlag[T]) checkSingle() error {
+	for _, c := range []struct {
+		method string
+		n      int
+	}{{"MinCount", self.minCount}, {"MaxCount", self.maxCount}} {
+		if c.n != 0 {
+			return fmt.Errorf("fluentflag: %s requires a slice flag (--%s)", c.method, self.name)
+		}
+	}
+	return nil
+}
+
+// validate runs the post-parse constraints for the flag.
+func (self *FluentFlag[T]) validate() error {
+	if self.required && !self.builder.isSet(self) {
+		return newFlagError(ErrMissingRequired, self.name, "", "--%s is required", self.name)
+	}
+	if count := len(self.values()); count < self.minCount {
+		return newFlagError(ErrConstraintViolated, self.name, "", "--%s requires at least %d %s, got %d", self.name, self.minCount, plural(self.minCount, "value"), count)
+	} else if self.maxCount > 0 && count > self.maxCount {
+		return newFlagError(ErrConstraintViolated, self.name, "", "--%s allows at most %d %s, got %d", self.name, self.maxCount, plural(self.maxCount, "value"), count)
+	}
+	if self.choicesFrom != "" && self.builder.isSet(self) {
+		other := self.builder.lookup(self.choicesFrom)
+		if other == nil {
+			return fmt.Errorf("fluentflag: --%s takes its choices from unknown flag --%s", self.name, self.choicesFrom)
+		}
+		allowed := other.values()
+		for _, val := range self.values() {
+			if !containsString(allowed, val) {
+				valid := "(none given)"
+				if len(allowed) > 0 {
+					valid = "[" + strings.Join(allowed, " ") + "]"
+				}
+				return newFlagError(ErrConstraintViolated, self.name, val, "--%s must be one of the --%s values %s, got %q", self.name, self.choicesFrom, valid, val)
+			}
+		}
+	}
+	for _, name := range self.requires {
+		other := self.builder.lookup(name)
+		if other == nil {
+			return fmt.Errorf("fluentflag: --%s requires unknown flag --%s", self.name, name)
+		}
+		if self.builder.isSet(self) && !self.builder.isSet(other) {
+			return newFlagError(ErrConstraintViolated, self.name, "", "--%s requires --%s", self.name, name)
+		}
+	}
+	if self.confirm != nil && self.builder.isOn(self) && !self.builder.confirmed(self.confirm) {
+		how := []string{}
+		for _, name := range self.confirm.flags {
+			how = append(how, "pass --"+name)
+		}
+		if self.confirm.env != "" {
+			how = append(how, "set "+self.confirm.env+"=1")
+		}
+		fmt.Fprintf(self.builder.outputWriter(), "warning: --%s is a dangerous action and needs confirmation\n", self.name)
+		return newFlagError(ErrConstraintViolated, self.name, "", "--%s must be confirmed: %s", self.name, strings.Join(how, " or "))
+	}
+	return nil
+}
+
+// Validate checks the post-parse constraints of all built flags. Call it after
+// parsing the flag set. If more than one flag is invalid, the returned error is
+// a ValidationErrors listing each problem.
+func (b *FlagBuilder) Validate() error {
+	var errs ValidationErrors
+	for _, f := range b.flagsBuilt {
+		if err := f.validate(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	errs = append(errs, b.validateConstraints()...)
+	errs = append(errs, b.validatePositionals()...)
+	return errs.err()
+}
+
+// ValidationErrors is the combined error for several invalid flags.
+type ValidationErrors []error
+
+// Error returns the messages of the errors, one per line.
+func (errs ValidationErrors) Error() string {
+	msgs := make([]string, len(errs))
+	for i, err := range errs {
+		msgs[i] = err.Error()
+	}
+	return strings.Join(msgs, "\n")
+}
+
+// Unwrap returns the errors, for errors.Is and errors.As in Go 1.20 and later.
+func (errs ValidationErrors) Unwrap() []error {
+	return errs
+}
+
+// Is reports whether any of the errors matches target, so errors.Is can match
+// them in Go versions before 1.20, which don't use Unwrap() []error.
+func (errs ValidationErrors) Is(target error) bool {
+	for _, err := range errs {
+		if errors.Is(err, target) {
+			return true
+		}
+	}
+	return false
+}
+
+// As finds the first of the errors that matches target, so errors.As can
+// match them in Go versions before 1.20, which don't use Unwrap() []error.
+func (errs ValidationErrors) As(target any) bool {
+	for _, err := range errs {
+		if errors.As(err, target) {
+			return true
+		}
+	}
+	return false
+}
+
+// err returns nil for no errors, the error itself for one, or errs.
+func (errs ValidationErrors) err() error {
+	switch len(errs) {
+	case 0:
+		return nil
+	case 1:
+		return errs[0]
+	}
+	return errs
+}
+
+// lookup finds a built flag by its long name or alias.
+func (b *FlagBuilder) lookup(name string) builtFlag {
+	for _, f := range b.flagsBuilt {
+		if containsString(f.names(), name) {
+			return f
+		}
+	}
+	return nil
+}
+
+// isSet reports whether the flag was set during parsing, on the command line,
+// from the environment, or from a config file. The flag package can't tell an
+// unset flag from one set to its default, so this relies on the flag set's
+// record of what it saw.
+func (b *FlagBuilder) isSet(f builtFlag) bool {
+	if _, ok := b.sources[f.names()[0]]; ok || b.setOnSubcommand[f.names()[0]] {
+		return true
+	}
+	set := false
+	b.flagSet.Visit(func(fl *flag.Flag) {
+		if hasName(f, fl.Name) {
+			set = true
+		}
+	})
+	return set
+}
+
+// hasName reports whether name is registered for f: its long name, alias,
+// negated form, or a former name.
+func hasName(f builtFlag, name string) bool {
+	if name == "" {
+		return false
+	}
+	return containsString(f.names(), name) || name == f.negatedName() || containsString(f.oldNames(), name)
+}
+
+// plural returns word, with an "s" appended unless n is 1.
+func plural(n int, word string) string {
+	if n == 1 {
+		return word
+	}
+	return word + "s"
+}
+
+// containsString reports whether s is in list.
+func containsString(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
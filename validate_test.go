@@ -0,0 +1,593 @@
+//go:build go1.18
+
+package fluentflag
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"testing"
+	"unicode"
+)
+
+func TestChoicesFromFlag(t *testing.T) {
+	tests := []struct {
+		name    string
+		args    []string
+		wantErr string
+	}{
+		{"listed", []string{"--profile=dev", "--profile=prod", "--default-profile=prod"}, ""},
+		{"unlisted", []string{"--profile=dev", "--profile=prod", "--default-profile=x"}, `--default-profile must be one of the --profile values [dev prod], got "x"`},
+		{"no profiles", []string{"--default-profile=x"}, `--default-profile must be one of the --profile values (none given), got "x"`},
+		{"unset", []string{"--profile=dev"}, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fs := flag.NewFlagSet("test", flag.ContinueOnError)
+			b := NewFlagBuilderWithSet(fs)
+			b.StringFlag("profile", "profiles").BuildSlice()
+			b.StringFlag("default-profile", "default profile").ChoicesFromFlag("profile").BuildVar()
+			if err := fs.Parse(tt.args); err != nil {
+				t.Fatalf("Parse failed: %v", err)
+			}
+			err := b.Validate()
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("unexpected error: %v", err)
+				}
+			} else if err == nil || err.Error() != tt.wantErr {
+				t.Errorf("expected error %q, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestChoicesFromFlag_UnknownFlag(t *testing.T) {
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	b := NewFlagBuilderWithSet(fs)
+	b.StringFlag("default-profile", "default profile").ChoicesFromFlag("nope").BuildVar()
+	fs.Parse([]string{"--default-profile=x"})
+	err := b.Validate()
+	if err == nil || !strings.Contains(err.Error(), "unknown flag --nope") {
+		t.Errorf("expected unknown flag error, got %v", err)
+	}
+}
+
+func TestPositiveAndNonNegative(t *testing.T) {
+	tests := []struct {
+		name    string
+		nonNeg  bool
+		arg     string
+		wantErr string
+	}{
+		{"positive zero", false, "0", "--workers must be positive"},
+		{"positive negative", false, "-3", "--workers must be positive"},
+		{"positive valid", false, "4", ""},
+		{"non-negative zero", true, "0", ""},
+		{"non-negative negative", true, "-3", "--workers must not be negative"},
+		{"non-negative valid", true, "4", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fs := flag.NewFlagSet("test", flag.ContinueOnError)
+			fs.SetOutput(io.Discard)
+			b := NewFlagBuilderWithSet(fs)
+			f := b.IntFlag("workers", "number of workers").Default(1)
+			if tt.nonNeg {
+				f.NonNegative()
+			} else {
+				f.Positive()
+			}
+			workers := f.BuildVar()
+			err := fs.Parse([]string{"--workers=" + tt.arg})
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("unexpected error: %v", err)
+				} else if fmt.Sprint(*workers) != tt.arg {
+					t.Errorf("expected %s, got %d", tt.arg, *workers)
+				}
+			} else if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("expected error containing %q, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestPositive_Float(t *testing.T) {
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+	b := NewFlagBuilderWithSet(fs)
+	ratio := b.Float64Flag("ratio", "sample ratio").Positive().BuildVar()
+	if err := fs.Parse([]string{"--ratio=0.5"}); err != nil || *ratio != 0.5 {
+		t.Errorf("expected 0.5, got %v (err %v)", *ratio, err)
+	}
+	if err := fs.Parse([]string{"--ratio=0"}); err == nil || !strings.Contains(err.Error(), "--ratio must be positive") {
+		t.Errorf("expected positive error, got %v", err)
+	}
+}
+
+func TestPositive_NonNumericPanics(t *testing.T) {
+	defer func() {
+		if r := recover(); r == nil {
+			t.Error("expected panic for non-numeric flag")
+		}
+	}()
+	b := NewFlagBuilderWithSet(flag.NewFlagSet("test", flag.ContinueOnError))
+	b.StringFlag("name", "name").Positive()
+}
+
+func TestChoices(t *testing.T) {
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+	b := NewFlagBuilderWithSet(fs)
+	level := b.StringFlag("level", "Log level").Choices("debug", "info", "warn").BuildVar()
+	if err := fs.Parse([]string{"--level=info"}); err != nil || *level != "info" {
+		t.Errorf("expected info, got %q (err %v)", *level, err)
+	}
+	err := fs.Parse([]string{"--level=loud"})
+	if err == nil || !strings.Contains(err.Error(), `--level must be one of [debug info warn], got "loud"`) {
+		t.Errorf("expected choices error, got %v", err)
+	}
+}
+
+func TestChoices_Replace(t *testing.T) {
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+	b := NewFlagBuilderWithSet(fs)
+	f := b.StringFlag("format", "output format").Choices("json").Choices("json", "yaml", "table")
+	format := f.BuildVar()
+	if err := fs.Parse([]string{"--format=table"}); err != nil || *format != "table" {
+		t.Errorf("expected table, got %q (err %v)", *format, err)
+	}
+	if len(f.checks) != 1 {
+		t.Errorf("expected one choices check, got %d", len(f.checks))
+	}
+	want := "      --format string      output format (choices: json, yaml, table)"
+	if got := f.Usage(); got != want {
+		t.Errorf("expected usage %q, got %q", want, got)
+	}
+}
+
+func TestChoices_Empty(t *testing.T) {
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+	b := NewFlagBuilderWithSet(fs)
+	b.SetErrorHandling(ReturnErrors)
+	f := b.StringFlag("format", "output format").Choices()
+	f.BuildVar()
+	if err := b.Err(); err == nil || !strings.Contains(err.Error(), "Choices for --format needs at least one choice") {
+		t.Errorf("expected an error for empty choices, got %v", err)
+	}
+	if len(f.checks) != 0 {
+		t.Errorf("expected no choices check, got %d", len(f.checks))
+	}
+}
+
+func TestASCIIOnlyAndValidUTF8(t *testing.T) {
+	tests := []struct {
+		name    string
+		ascii   bool
+		arg     string
+		wantErr string
+	}{
+		{"ascii valid", true, "X-Token", ""},
+		{"ascii non-ascii", true, "café", "--header must be ASCII, got byte 0xc3 at offset 3"},
+		{"utf8 valid", false, "café", ""},
+		{"utf8 invalid", false, "ab\xffc", "--header must be valid UTF-8, got byte 0xff at offset 2"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fs := flag.NewFlagSet("test", flag.ContinueOnError)
+			fs.SetOutput(io.Discard)
+			b := NewFlagBuilderWithSet(fs)
+			f := b.StringFlag("header", "header value")
+			if tt.ascii {
+				f.ASCIIOnly()
+			} else {
+				f.ValidUTF8()
+			}
+			header := f.BuildVar()
+			err := fs.Parse([]string{"--header", tt.arg})
+			if tt.wantErr == "" {
+				if err != nil || *header != tt.arg {
+					t.Errorf("expected %q, got %q (err %v)", tt.arg, *header, err)
+				}
+			} else if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("expected error containing %q, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestEmail(t *testing.T) {
+	tests := []struct {
+		name    string
+		arg     string
+		want    string
+		wantErr string
+	}{
+		{"address", "ops@example.com", "ops@example.com", ""},
+		{"display name", "Ops Team <ops@example.com>", "ops@example.com", ""},
+		{"invalid", "not-an-email", "", "--notify-email must be an email address"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fs := flag.NewFlagSet("test", flag.ContinueOnError)
+			fs.SetOutput(io.Discard)
+			b := NewFlagBuilderWithSet(fs)
+			email := b.StringFlag("notify-email", "where to send notices").Email().BuildVar()
+			err := fs.Parse([]string{"--notify-email", tt.arg})
+			if tt.wantErr == "" {
+				if err != nil || *email != tt.want {
+					t.Errorf("expected %q, got %q (err %v)", tt.want, *email, err)
+				}
+			} else if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("expected error containing %q, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestMaxLenAndMinLen(t *testing.T) {
+	tests := []struct {
+		name    string
+		bytes   bool
+		arg     string
+		wantErr string
+	}{
+		{"too long", false, "abcdefg", "--name must be at most 5 characters, got 7"},
+		{"too short", false, "a", "--name must be at least 2 characters, got 1"},
+		{"within bounds", false, "abc", ""},
+		{"multi-byte runes", false, "héllo", ""},
+		{"multi-byte bytes", true, "héllo", "--name must be at most 5 bytes, got 6"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fs := flag.NewFlagSet("test", flag.ContinueOnError)
+			fs.SetOutput(io.Discard)
+			b := NewFlagBuilderWithSet(fs)
+			f := b.StringFlag("name", "name").MinLen(2).MaxLen(5)
+			if tt.bytes {
+				f.CountBytes()
+			}
+			name := f.BuildVar()
+			err := fs.Parse([]string{"--name", tt.arg})
+			if tt.wantErr == "" {
+				if err != nil || *name != tt.arg {
+					t.Errorf("expected %q, got %q (err %v)", tt.arg, *name, err)
+				}
+			} else if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("expected error containing %q, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestMinCountAndMaxCount(t *testing.T) {
+	tests := []struct {
+		name    string
+		args    []string
+		wantErr string
+	}{
+		{"below minimum", []string{"--host=a"}, "--host requires at least 2 values, got 1"},
+		{"none", []string{}, "--host requires at least 2 values, got 0"},
+		{"exactly minimum", []string{"--host=a", "--host=b"}, ""},
+		{"above minimum", []string{"--host=a", "--host=b", "--host=c"}, ""},
+		{"above maximum", []string{"--host=a", "--host=b", "--host=c", "--host=d"}, "--host allows at most 3 values, got 4"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fs := flag.NewFlagSet("test", flag.ContinueOnError)
+			b := NewFlagBuilderWithSet(fs)
+			b.StringFlag("host", "hosts").MinCount(2).MaxCount(3).BuildSlice()
+			fs.Parse(tt.args)
+			err := b.Validate()
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("unexpected error: %v", err)
+				}
+			} else if err == nil || err.Error() != tt.wantErr {
+				t.Errorf("expected error %q, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestMinCount_SingleValue(t *testing.T) {
+	b := NewFlagBuilderWithSet(flag.NewFlagSet("test", flag.ContinueOnError))
+	b.SetErrorHandling(ReturnErrors)
+	b.StringFlag("x", "x").MinCount(2).BuildVar()
+	b.StringFlag("y", "y").MaxCount(2).BuildVar()
+	want := "fluentflag: MinCount requires a slice flag (--x)\nfluentflag: MaxCount requires a slice flag (--y)"
+	if err := b.Err(); err == nil || err.Error() != want {
+		t.Errorf("expected error %q, got %v", want, err)
+	}
+}
+
+func TestRequireConfirmation(t *testing.T) {
+	tests := []struct {
+		name    string
+		args    []string
+		env     string
+		wantErr string
+	}{
+		{"force alone", []string{"--force"}, "", "--force must be confirmed: pass --yes or set MYTOOL_CONFIRM=1"},
+		{"force and yes", []string{"--force", "--yes"}, "", ""},
+		{"force and env", []string{"--force"}, "1", ""},
+		{"force and env false", []string{"--force"}, "false", "--force must be confirmed"},
+		{"force and env no", []string{"--force"}, "no", "--force must be confirmed"},
+		{"force and env typo", []string{"--force"}, "ture", "--force must be confirmed"},
+		{"no force", []string{}, "", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("MYTOOL_CONFIRM", tt.env)
+			b := NewFlagBuilderWithSet(flag.NewFlagSet("test", flag.ContinueOnError))
+			var out strings.Builder
+			b.SetOutput(&out)
+			b.BoolFlag("force", "delete everything").RequireConfirmation("MYTOOL_CONFIRM", "yes").BuildVar()
+			b.BoolFlag("yes", "confirm dangerous actions").BuildVar()
+			_, err := b.Parse(tt.args)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("unexpected error: %v", err)
+				}
+				if out.String() != "" {
+					t.Errorf("unexpected warning: %q", out.String())
+				}
+			} else {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Errorf("expected error containing %q, got %v", tt.wantErr, err)
+				}
+				if !strings.Contains(out.String(), "warning: --force") {
+					t.Errorf("expected warning, got %q", out.String())
+				}
+			}
+		})
+	}
+}
+
+func TestRequired(t *testing.T) {
+	tests := []struct {
+		name    string
+		args    []string
+		config  string
+		wantErr string
+	}{
+		{"missing", []string{}, `{}`, "--token is required"},
+		{"set to default", []string{"--token="}, `{}`, ""},
+		{"alias", []string{"-t", "abc"}, `{}`, ""},
+		{"from config", []string{}, `{"token": "abc"}`, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b := NewFlagBuilderWithSet(flag.NewFlagSet("test", flag.ContinueOnError))
+			b.StringFlag("token", "API token").Alias('t').Required().BuildVar()
+			if err := b.ConfigFile(writeConfig(t, "config.json", tt.config)); err != nil {
+				t.Fatal(err)
+			}
+			_, err := b.Parse(tt.args)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("unexpected error: %v", err)
+				}
+			} else if err == nil || err.Error() != tt.wantErr {
+				t.Errorf("expected error %q, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestRequired_Usage(t *testing.T) {
+	b := NewFlagBuilderWithSet(flag.NewFlagSet("test", flag.ContinueOnError))
+	f := b.StringFlag("token", "API token").Required()
+	f.BuildVar()
+	if got := f.Usage(); !strings.HasSuffix(got, "API token (required)") {
+		t.Errorf("expected required marker, got %q", got)
+	}
+}
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		arg     string
+		wantErr string
+	}{
+		{"valid", "ab-1234", ""},
+		{"rejected", "1234", `invalid value "1234" for flag -id: IDs start with two letters`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fs := flag.NewFlagSet("test", flag.ContinueOnError)
+			fs.SetOutput(io.Discard)
+			b := NewFlagBuilderWithSet(fs)
+			id := b.StringFlag("id", "ticket ID").Validate(func(s string) error {
+				if len(s) < 2 || !unicode.IsLetter(rune(s[0])) || !unicode.IsLetter(rune(s[1])) {
+					return errors.New("IDs start with two letters")
+				}
+				return nil
+			}).BuildVar()
+			err := fs.Parse([]string{"-id", tt.arg})
+			if tt.wantErr == "" {
+				if err != nil || *id != tt.arg {
+					t.Errorf("expected %q, got %q (err %v)", tt.arg, *id, err)
+				}
+			} else if err == nil || err.Error() != tt.wantErr {
+				t.Errorf("expected error %q, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestMinAndMax(t *testing.T) {
+	tests := []struct {
+		name    string
+		arg     string
+		wantErr string
+	}{
+		{"lowest", "1", ""},
+		{"highest", "65535", ""},
+		{"below", "0", "--port must be at least 1, got 0"},
+		{"above", "70000", "--port must be at most 65535, got 70000"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fs := flag.NewFlagSet("test", flag.ContinueOnError)
+			fs.SetOutput(io.Discard)
+			b := NewFlagBuilderWithSet(fs)
+			port := b.IntFlag("port", "listen port").Min(1).Max(65535).BuildVar()
+			err := fs.Parse([]string{"--port", tt.arg})
+			if tt.wantErr == "" {
+				if err != nil || fmt.Sprint(*port) != tt.arg {
+					t.Errorf("expected %s, got %d (err %v)", tt.arg, *port, err)
+				}
+			} else if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("expected error containing %q, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestMinAndMax_Usage(t *testing.T) {
+	tests := []struct {
+		name string
+		flag func(b *FlagBuilder) builtFlag
+		want string
+	}{
+		{"range", func(b *FlagBuilder) builtFlag {
+			f := b.IntFlag("port", "listen port").Min(1).Max(65535).Default(8080)
+			f.BuildVar()
+			return f
+		}, "listen port (1-65535) (default 8080)"},
+		{"min only", func(b *FlagBuilder) builtFlag {
+			f := b.Float64Flag("ratio", "sample ratio").Min(0.5)
+			f.BuildVar()
+			return f
+		}, "sample ratio (>= 0.5)"},
+		{"max only", func(b *FlagBuilder) builtFlag {
+			f := b.UintFlag("retries", "retry count").Max(10)
+			f.BuildVar()
+			return f
+		}, "retry count (<= 10)"},
+		{"positive", func(b *FlagBuilder) builtFlag {
+			f := b.IntFlag("workers", "worker count").Positive()
+			f.BuildVar()
+			return f
+		}, "worker count (> 0)"},
+		{"positive with max", func(b *FlagBuilder) builtFlag {
+			f := b.IntFlag("workers", "worker count").Positive().Max(10)
+			f.BuildVar()
+			return f
+		}, "worker count (> 0, <= 10)"},
+		{"non-negative", func(b *FlagBuilder) builtFlag {
+			f := b.DurationFlag("delay", "retry delay").NonNegative()
+			f.BuildVar()
+			return f
+		}, "retry delay (>= 0s)"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b := NewFlagBuilderWithSet(flag.NewFlagSet("test", flag.ContinueOnError))
+			if got := tt.flag(b).Usage(); !strings.HasSuffix(got, tt.want) {
+				t.Errorf("expected usage ending in %q, got %q", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestMinAndMax_LargeLimits(t *testing.T) {
+	tests := []struct {
+		name    string
+		flag    func(b *FlagBuilder)
+		arg     string
+		wantErr string
+	}{
+		{"int64 below min", func(b *FlagBuilder) { b.Int64Flag("n", "n").Min(9007199254740993).BuildVar() }, "9007199254740992", "--n must be at least 9007199254740993"},
+		{"int64 at min", func(b *FlagBuilder) { b.Int64Flag("n", "n").Min(9007199254740993).BuildVar() }, "9007199254740993", ""},
+		{"int64 above min", func(b *FlagBuilder) { b.Int64Flag("n", "n").Min(9007199254740993).BuildVar() }, "9007199254740994", ""},
+		{"int64 below max", func(b *FlagBuilder) { b.Int64Flag("n", "n").Max(-9007199254740993).BuildVar() }, "-9007199254740994", ""},
+		{"int64 at max", func(b *FlagBuilder) { b.Int64Flag("n", "n").Max(-9007199254740993).BuildVar() }, "-9007199254740993", ""},
+		{"int64 above max", func(b *FlagBuilder) { b.Int64Flag("n", "n").Max(-9007199254740993).BuildVar() }, "-9007199254740992", "--n must be at most -9007199254740993"},
+		{"uint64 below max", func(b *FlagBuilder) { b.Uint64Flag("n", "n").Max(18446744073709551614).BuildVar() }, "18446744073709551613", ""},
+		{"uint64 at max", func(b *FlagBuilder) { b.Uint64Flag("n", "n").Max(18446744073709551614).BuildVar() }, "18446744073709551614", ""},
+		{"uint64 above max", func(b *FlagBuilder) { b.Uint64Flag("n", "n").Max(18446744073709551614).BuildVar() }, "18446744073709551615", "--n must be at most 18446744073709551614"},
+		{"uint64 below min", func(b *FlagBuilder) { b.Uint64Flag("n", "n").Min(18446744073709551614).BuildVar() }, "18446744073709551613", "--n must be at least 18446744073709551614"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fs := flag.NewFlagSet("test", flag.ContinueOnError)
+			fs.SetOutput(io.Discard)
+			b := NewFlagBuilderWithSet(fs)
+			tt.flag(b)
+			err := fs.Parse([]string{"--n=" + tt.arg})
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("unexpected error: %v", err)
+				}
+			} else if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("expected error containing %q, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestMin_NamedType(t *testing.T) {
+	type level int
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+	b := NewFlagBuilderWithSet(fs)
+	parseLevel := func(s string) (level, error) {
+		n, err := strconv.Atoi(s)
+		return level(n), err
+	}
+	newFlag[level](b, "level", "log level").ParseAny(parseLevel).Min(1).Max(5).BuildSlice()
+	if err := b.Err(); err != nil {
+		t.Fatalf("unexpected build error: %v", err)
+	}
+	if err := fs.Parse([]string{"--level=3"}); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	if err := fs.Parse([]string{"--level=6"}); err == nil || !strings.Contains(err.Error(), "--level must be at most 5, got 6") {
+		t.Errorf("expected range error, got %v", err)
+	}
+}
+
+func TestMin_NonNumericPanics(t *testing.T) {
+	defer func() {
+		if r := recover(); r == nil {
+			t.Error("expected panic for non-numeric flag")
+		}
+	}()
+	b := NewFlagBuilderWithSet(flag.NewFlagSet("test", flag.ContinueOnError))
+	b.StringFlag("name", "name").Min("a")
+}
+
+func TestMatch(t *testing.T) {
+	tests := []struct {
+		name    string
+		arg     string
+		wantErr string
+	}{
+		{"match", "v1.2.3", ""},
+		{"no match", "1.2", `--version must match ^v\d+\.\d+\.\d+$, got "1.2"`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fs := flag.NewFlagSet("test", flag.ContinueOnError)
+			fs.SetOutput(io.Discard)
+			b := NewFlagBuilderWithSet(fs)
+			version := b.StringFlag("version", "release version").Match(`^v\d+\.\d+\.\d+$`).BuildVar()
+			err := fs.Parse([]string{"--version", tt.arg})
+			if tt.wantErr == "" {
+				if err != nil || *version != tt.arg {
+					t.Errorf("expected %q, got %q (err %v)", tt.arg, *version, err)
+				}
+			} else if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("expected error containing %q, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
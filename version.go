@@ -0,0 +1,100 @@
+// version.go
+// Copyright (c) 2025 mattmc3
+// SPDX-License-Identifier: MIT
+// Project home: https://github.com/mattmc3/fluentflag
+
+package fluentflag
+
+import (
+	"fmt"
+	"path/filepath"
+	"runtime/debug"
+	"strings"
+)
+
+// VersionInfo describes a program's version, as printed by --version.
+type VersionInfo struct {
+	Version string // version, like "1.4.2"
+	Commit  string // VCS revision the program was built from, if known
+	Date    string // date of the build or commit, if known
+}
+
+// String formats the version like "1.4.2 (commit 1a2b3c4, built 2025-03-14)".
+func (v VersionInfo) String() string {
+	var details []string
+	if v.Commit != "" {
+		details = append(details, "commit "+v.Commit)
+	}
+	if v.Date != "" {
+		details = append(details, "built "+v.Date)
+	}
+	if len(details) == 0 {
+		return v.Version
+	}
+	return v.Version + " (" + strings.Join(details, ", ") + ")"
+}
+
+// Version registers -V, --version to print the program's name and version to
+// the help output and exit with status 0. If version is empty, it is taken
+// from the module version and VCS stamps Go embeds in the binary.
+func (b *FlagBuilder) Version(version string) {
+	info := VersionInfo{Version: version}
+	if version == "" {
+		info = buildVersionInfo()
+	}
+	b.VersionWithInfo(info)
+}
+
+// VersionWithInfo is like Version, but with the commit and date given too.
+// Empty fields are left out of the output.
+func (b *FlagBuilder) VersionWithInfo(info VersionInfo) {
+	f := b.BoolFlag("version", "Print the version and exit").Alias('V').Validate(func(on bool) error {
+		if on {
+			fmt.Fprintf(b.helpWriter(), "%s %s\n", filepath.Base(b.flagSet.Name()), info)
+			b.exit(0)
+		}
+		return nil
+	})
+	f.noConfig = true
+	f.rebuild = func(c *FlagBuilder) {
+		c.VersionWithInfo(info)
+	}
+	f.BuildVar()
+}
+
+// readBuildInfo is debug.ReadBuildInfo, replaced in tests.
+var readBuildInfo = debug.ReadBuildInfo
+
+// buildVersionInfo returns the version of the main module and the VCS
+// revision and time it was built from, as embedded by the go command. The
+// version is "(devel)" for builds outside of module mode or from a checkout,
+// and revisions are shortened to 7 characters with "-dirty" appended for
+// builds with uncommitted changes.
+func buildVersionInfo() VersionInfo {
+	info := VersionInfo{Version: "(devel)"}
+	bi, ok := readBuildInfo()
+	if !ok {
+		return info
+	}
+	if v := bi.Main.Version; v != "" {
+		info.Version = strings.TrimPrefix(v, "v")
+	}
+	dirty := false
+	for _, s := range bi.Settings {
+		switch s.Key {
+		case "vcs.revision":
+			info.Commit = s.Value
+			if len(info.Commit) > 7 {
+				info.Commit = info.Commit[:7]
+			}
+		case "vcs.time":
+			info.Date = s.Value
+		case "vcs.modified":
+			dirty = s.Value == "true"
+		}
+	}
+	if dirty && info.Commit != "" {
+		info.Commit += "-dirty"
+	}
+	return info
+}
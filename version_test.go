@@ -0,0 +1,79 @@
+//go:build go1.18
+
+package fluentflag
+
+import (
+	"flag"
+	"runtime/debug"
+	"strings"
+	"testing"
+)
+
+func TestVersion(t *testing.T) {
+	tests := []struct {
+		name string
+		args []string
+		info VersionInfo
+		want string
+	}{
+		{"long", []string{"--version"}, VersionInfo{Version: "1.4.2"}, "mytool 1.4.2\n"},
+		{"short", []string{"-V"}, VersionInfo{Version: "1.4.2"}, "mytool 1.4.2\n"},
+		{"details", []string{"-V"}, VersionInfo{Version: "1.4.2", Commit: "1a2b3c4", Date: "2025-03-14"}, "mytool 1.4.2 (commit 1a2b3c4, built 2025-03-14)\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b := NewFlagBuilderWithSet(flag.NewFlagSet("/usr/bin/mytool", flag.ContinueOnError))
+			b.StringFlag("host", "Host").Required().BuildVar()
+			b.VersionWithInfo(tt.info)
+			var out strings.Builder
+			exitCode := -1
+			b.SetHelpOutput(&out)
+			b.SetExitFunc(func(code int) { exitCode = code })
+			b.Parse(tt.args)
+			if exitCode != 0 {
+				t.Errorf("expected exit code 0, got %d", exitCode)
+			}
+			if out.String() != tt.want {
+				t.Errorf("expected %q, got %q", tt.want, out.String())
+			}
+		})
+	}
+}
+
+func TestVersion_Usage(t *testing.T) {
+	b := NewFlagBuilderWithSet(flag.NewFlagSet("test", flag.ContinueOnError))
+	b.Version("1.4.2")
+	expected := "  -V, --version            Print the version and exit\n"
+	if actual := b.UsageStringWidth(0); actual != expected {
+		t.Errorf("Usage output mismatch.\nGot:\n%s\nWant:\n%s", actual, expected)
+	}
+}
+
+func TestBuildVersionInfo(t *testing.T) {
+	defer func() { readBuildInfo = debug.ReadBuildInfo }()
+	tests := []struct {
+		name string
+		bi   *debug.BuildInfo
+		ok   bool
+		want VersionInfo
+	}{
+		{"unavailable", nil, false, VersionInfo{Version: "(devel)"}},
+		{"module", &debug.BuildInfo{Main: debug.Module{Version: "v1.4.2"}}, true, VersionInfo{Version: "1.4.2"}},
+		{"vcs", &debug.BuildInfo{
+			Main: debug.Module{Version: "(devel)"},
+			Settings: []debug.BuildSetting{
+				{Key: "vcs.revision", Value: "1a2b3c4d5e6f"},
+				{Key: "vcs.time", Value: "2025-03-14T10:00:00Z"},
+				{Key: "vcs.modified", Value: "true"},
+			},
+		}, true, VersionInfo{Version: "(devel)", Commit: "1a2b3c4-dirty", Date: "2025-03-14T10:00:00Z"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			readBuildInfo = func() (*debug.BuildInfo, bool) { return tt.bi, tt.ok }
+			if got := buildVersionInfo(); got != tt.want {
+				t.Errorf("got %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
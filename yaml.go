@@ -0,0 +1,225 @@
+// yaml.go
+// Copyright (c) 2025 mattmc3
+// SPDX-License-Identifier: MIT
+// Project home: https://github.com/mattmc3/fluentflag
+
+package fluentflag
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// yamlLine is a non-blank line of a YAML document.
+type yamlLine struct {
+	num    int    // line number, for errors
+	indent int    // number of leading spaces
+	text   string // the line without indentation or comment
+}
+
+// decodeYAML decodes the subset of YAML used by typical config files into v, a
+// *map[string]any: nested block mappings, block lists of scalars, inline
+// [a, b] lists, which may span lines, and plain or quoted scalars, which are
+// all kept as strings. Anchors, multi-line strings, and multiple documents
+// aren't supported; plug in a full YAML package for those.
+func decodeYAML(data []byte, v any) error {
+	out, ok := v.(*map[string]any)
+	if !ok {
+		return errors.New("fluentflag: decodeYAML needs a *map[string]any")
+	}
+	lines, err := yamlLines(data)
+	if err != nil {
+		return err
+	}
+	root := map[string]any{}
+	if len(lines) > 0 {
+		val, next, err := parseYAMLBlock(lines, 0)
+		if err != nil {
+			return err
+		}
+		if next < len(lines) {
+			return fmt.Errorf("line %d: unexpected indentation", lines[next].num)
+		}
+		m, ok := val.(map[string]any)
+		if !ok {
+			return fmt.Errorf("line %d: expected a mapping at the top level", lines[0].num)
+		}
+		root = m
+	}
+	*out = root
+	return nil
+}
+
+// yamlLines splits a YAML document into its non-blank lines, without
+// comments, joining inline lists that span lines.
+func yamlLines(data []byte) ([]yamlLine, error) {
+	var lines []yamlLine
+	raw := strings.Split(string(data), "\n")
+	for i := 0; i < len(raw); i++ {
+		num := i + 1
+		line := strings.TrimRight(stripComment(raw[i]), " \t\r")
+		text := strings.TrimLeft(line, " ")
+		if text == "" || (text == "---" && len(lines) == 0) {
+			continue
+		}
+		if strings.HasPrefix(text, "\t") {
+			return nil, fmt.Errorf("line %d: tabs can't be used for indentation", num)
+		}
+		indent := len(line) - len(text)
+		// Inline lists may span lines until their closing bracket.
+		for isOpenYAMLList(text) && i+1 < len(raw) {
+			i++
+			text += " " + strings.TrimSpace(stripComment(raw[i]))
+		}
+		lines = append(lines, yamlLine{num: num, indent: indent, text: text})
+	}
+	return lines, nil
+}
+
+// yamlKeyOrder returns the keys of the mapping under the top-level key in the
+// order they are written, which decoding into a map loses.
+func yamlKeyOrder(data []byte, key string) []string {
+	lines, err := yamlLines(data)
+	if err != nil {
+		return nil
+	}
+	for i, line := range lines {
+		if k, raw, ok := cutYAMLKey(line.text); !ok || line.indent != 0 || k != key || raw != "" {
+			continue
+		}
+		var keys []string
+		for _, child := range lines[i+1:] {
+			if child.indent <= line.indent {
+				break
+			}
+			if child.indent != lines[i+1].indent {
+				continue
+			}
+			if k, _, ok := cutYAMLKey(child.text); ok {
+				keys = append(keys, k)
+			}
+		}
+		return keys
+	}
+	return nil
+}
+
+// parseYAMLBlock parses the mapping or list that starts at lines[i], and
+// returns it along with the index of the first line after it.
+func parseYAMLBlock(lines []yamlLine, i int) (any, int, error) {
+	indent := lines[i].indent
+	if isYAMLListItem(lines[i].text) {
+		list := []any{}
+		for ; i < len(lines) && lines[i].indent == indent && isYAMLListItem(lines[i].text); i++ {
+			item := strings.TrimSpace(lines[i].text[1:])
+			if item == "" || strings.HasSuffix(item, ":") || strings.Contains(item, ": ") {
+				return nil, 0, fmt.Errorf("line %d: only lists of scalars are supported", lines[i].num)
+			}
+			val, err := yamlScalar(item)
+			if err != nil {
+				return nil, 0, fmt.Errorf("line %d: %v", lines[i].num, err)
+			}
+			list = append(list, val)
+		}
+		return list, i, nil
+	}
+
+	m := map[string]any{}
+	for i < len(lines) && lines[i].indent == indent {
+		line := lines[i]
+		key, raw, ok := cutYAMLKey(line.text)
+		if !ok {
+			return nil, 0, fmt.Errorf("line %d: expected key: value, got %s", line.num, line.text)
+		}
+		if _, dup := m[key]; dup {
+			return nil, 0, fmt.Errorf("line %d: duplicate key %s", line.num, key)
+		}
+		i++
+		if raw != "" {
+			val, err := yamlScalar(raw)
+			if err != nil {
+				return nil, 0, fmt.Errorf("line %d: %v", line.num, err)
+			}
+			m[key] = val
+			continue
+		}
+		// A key without a value holds the block that follows. Lists may be
+		// indented at the same level as their key.
+		if i < len(lines) && (lines[i].indent > indent || (lines[i].indent == indent && isYAMLListItem(lines[i].text))) {
+			val, next, err := parseYAMLBlock(lines, i)
+			if err != nil {
+				return nil, 0, err
+			}
+			m[key], i = val, next
+		} else {
+			m[key] = nil
+		}
+	}
+	if i < len(lines) && lines[i].indent > indent {
+		return nil, 0, fmt.Errorf("line %d: unexpected indentation", lines[i].num)
+	}
+	return m, i, nil
+}
+
+// isYAMLListItem reports whether text is a block list item like "- a".
+func isYAMLListItem(text string) bool {
+	return text == "-" || strings.HasPrefix(text, "- ")
+}
+
+// isOpenYAMLList reports whether the mapping line text holds an inline list
+// that continues on the next line.
+func isOpenYAMLList(text string) bool {
+	_, raw, ok := cutYAMLKey(text)
+	return ok && strings.HasPrefix(raw, "[") && !strings.HasSuffix(raw, "]")
+}
+
+// cutYAMLKey splits a mapping line like `name: value` into its unquoted key
+// and raw value.
+func cutYAMLKey(text string) (key, raw string, ok bool) {
+	if text[0] == '"' || text[0] == '\'' {
+		end := strings.IndexByte(text[1:], text[0])
+		if end == -1 {
+			return "", "", false
+		}
+		key, rest := text[1:end+1], text[end+2:]
+		if !strings.HasPrefix(rest, ":") {
+			return "", "", false
+		}
+		return key, strings.TrimSpace(rest[1:]), true
+	}
+	if strings.HasSuffix(text, ":") {
+		return strings.TrimSpace(text[:len(text)-1]), "", true
+	}
+	key, raw, ok = strings.Cut(text, ": ")
+	return strings.TrimSpace(key), strings.TrimSpace(raw), ok
+}
+
+// yamlScalar parses a scalar or an inline list.
+func yamlScalar(raw string) (any, error) {
+	switch {
+	case strings.HasPrefix(raw, "["):
+		if !strings.HasSuffix(raw, "]") {
+			return nil, fmt.Errorf("unterminated list %s", raw)
+		}
+		return splitInlineList(raw)
+	case strings.HasPrefix(raw, "{"):
+		return nil, errors.New("inline mappings are not supported")
+	case raw == "|" || raw == ">" || strings.HasPrefix(raw, "|") || strings.HasPrefix(raw, ">"):
+		return nil, errors.New("multi-line strings are not supported")
+	case raw[0] == '\'':
+		if len(raw) < 2 || raw[len(raw)-1] != '\'' {
+			return nil, fmt.Errorf("invalid string %s", raw)
+		}
+		return strings.ReplaceAll(raw[1:len(raw)-1], "''", "'"), nil
+	case raw[0] == '"':
+		s, err := unquoteScalar(raw)
+		if err != nil || s == raw {
+			return nil, fmt.Errorf("invalid string %s", raw)
+		}
+		return s, nil
+	case raw == "~" || raw == "null":
+		return nil, nil
+	}
+	return raw, nil
+}
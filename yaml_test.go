@@ -0,0 +1,51 @@
+//go:build go1.18
+
+package fluentflag
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestDecodeYAML(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		want    map[string]any
+		wantErr string
+	}{
+		{"empty", "# nothing\n", map[string]any{}, ""},
+		{"scalars", "---\na: 1\nb: \"x # y\"\nc: 'it''s'\nd: ~\n", map[string]any{"a": "1", "b": "x # y", "c": "it's", "d": nil}, ""},
+		{"nested", "a:\n  b:\n    c: deep\n  d: shallow\ne: top\n", map[string]any{"a": map[string]any{"b": map[string]any{"c": "deep"}, "d": "shallow"}, "e": "top"}, ""},
+		{"list at key indent", "hosts:\n- a\n- b\nport: 1\n", map[string]any{"hosts": []any{"a", "b"}, "port": "1"}, ""},
+		{"comment in quoted value", "a: \"x #1\" # note\nb: 'y # 2'\n", map[string]any{"a": "x #1", "b": "y # 2"}, ""},
+		{"inline list trailing comma", "a: [x, y,]\n", map[string]any{"a": []any{"x", "y"}}, ""},
+		{"multi-line inline list", "a: [\n  \"x # 1\", # first\n  y,\n]\nb: 2\n", map[string]any{"a": []any{"x # 1", "y"}, "b": "2"}, ""},
+		{"duplicate mapping", "a:\n  x: 1\na:\n  y: 2\n", nil, "line 3: duplicate key a"},
+		{"quoted key", "\"a.b\": 1\n", map[string]any{"a.b": "1"}, ""},
+		{"bad indent", "a: 1\n  b: 2\n", nil, "line 2: unexpected indentation"},
+		{"duplicate", "a: 1\na: 2\n", nil, "line 2: duplicate key a"},
+		{"list of maps", "a:\n  - b: 1\n", nil, "line 2: only lists of scalars are supported"},
+		{"no key", "a: 1\njust text\n", nil, "line 2: expected key: value"},
+		{"top-level list", "- a\n", nil, "line 1: expected a mapping"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got map[string]any
+			err := decodeYAML([]byte(tt.input), &got)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Errorf("expected error containing %q, got %v", tt.wantErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("expected %v, got %v", tt.want, got)
+			}
+		})
+	}
+}